@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,11 +12,14 @@ import (
 	"time"
 
 	"ecdsa-scanner/internal/api"
+	"ecdsa-scanner/internal/auth"
 	"ecdsa-scanner/internal/config"
 	"ecdsa-scanner/internal/db"
 	"ecdsa-scanner/internal/logger"
+	"ecdsa-scanner/internal/metrics"
 	"ecdsa-scanner/internal/notify"
 	"ecdsa-scanner/internal/scanner"
+	"ecdsa-scanner/internal/storage"
 )
 
 func main() {
@@ -25,14 +29,14 @@ func main() {
 	appLogger := logger.New(500)
 
 	// Initialize database
-	var database db.Database
+	var database db.Store
 	var err error
 
 	if cfg.DatabaseURL == "" {
 		appLogger.Warn("DATABASE_URL not set - running in demo mode")
 		database = db.NewMockWithSampleData()
 	} else {
-		database, err = db.New(cfg.DatabaseURL)
+		database, err = storage.New(cfg.StorageDriver, cfg.DatabaseURL, cfg.ClickHouseURL, config.SystemAddresses(), appLogger)
 		if err != nil {
 			log.Fatalf("Database error: %v", err)
 		}
@@ -41,22 +45,94 @@ func main() {
 	defer database.Close()
 
 	// Initialize notifier
-	notifier := notify.New(cfg.PushoverAppToken, cfg.PushoverUserKey)
+	notifier, err := notify.New(notify.Config{
+		Pushover: notify.PushoverConfig{AppToken: cfg.PushoverAppToken, UserKey: cfg.PushoverUserKey},
+		Slack:    notify.SlackConfig{WebhookURL: cfg.SlackWebhookURL},
+		Discord:  notify.DiscordConfig{WebhookURL: cfg.DiscordWebhookURL},
+		Telegram: notify.TelegramConfig{BotToken: cfg.TelegramBotToken, ChatID: cfg.TelegramChatID},
+		Webhook:  notify.WebhookConfig{URL: cfg.WebhookURL},
+		Email: notify.EmailConfig{
+			SMTPHost: cfg.SMTPHost, SMTPPort: cfg.SMTPPort,
+			From: cfg.SMTPFrom, To: cfg.SMTPTo,
+			Username: cfg.SMTPUsername, Password: cfg.SMTPPassword,
+		},
+		RulesPath: cfg.NotifyRulesFile,
+	})
+	if err != nil {
+		log.Fatalf("Notify error: %v", err)
+	}
 	if notifier.IsEnabled() {
-		appLogger.Info("Pushover notifications enabled")
+		appLogger.Info("Notifications enabled")
 	}
 
 	// Initialize scanner
-	sc, err := scanner.New(database, appLogger, cfg.AnkrAPIKey, notifier)
+	sc, err := scanner.New(database, appLogger, cfg.AnkrAPIKey, notifier, cfg.ScanMode)
 	if err != nil {
 		log.Fatalf("Scanner error: %v", err)
 	}
 
+	// Shard coordination is opt-in, same as Bitcoin support: it needs a
+	// stable per-replica identity, so it only turns on when SHARD_OWNER_ID
+	// is configured. Left unset, Scanner keeps its default no-op
+	// Coordinator and every replica catches up its full confirmed range
+	// itself.
+	if cfg.ShardOwnerID != "" {
+		sc.SetCoordinator(scanner.NewDBCoordinator(database, cfg.ShardOwnerID, cfg.ShardLeaseDuration))
+		appLogger.Info("Shard coordination enabled (owner=%s)", cfg.ShardOwnerID)
+	}
+
+	// Prometheus metrics are always collected; /metrics just exposes them.
+	prom := metrics.NewPrometheus()
+	sc.SetMetrics(prom)
+
+	// Mutating routes (/api/start, /api/stop, /api/recovery/toggle,
+	// /api/notifications/test) are gated behind bearer-token and/or OIDC
+	// auth; see auth.Middleware. Leaving both unset leaves those routes
+	// unreachable (401) rather than falling open.
+	var authenticators auth.ChainAuthenticator
+	if cfg.AuthTokens != "" {
+		roleByToken, err := auth.ParseTokenConfig(cfg.AuthTokens)
+		if err != nil {
+			log.Fatalf("Auth tokens error: %v", err)
+		}
+		authenticators = append(authenticators, auth.NewTokenAuthenticator(roleByToken))
+		appLogger.Info("Bearer-token auth enabled (%d tokens)", len(roleByToken))
+	}
+	if cfg.OIDCJWKSURL != "" {
+		oidcAuth, err := auth.NewOIDCAuthenticator(context.Background(), auth.OIDCConfig{
+			JWKSURL:  cfg.OIDCJWKSURL,
+			Issuer:   cfg.OIDCIssuer,
+			Audience: cfg.OIDCAudience,
+		})
+		if err != nil {
+			log.Fatalf("OIDC auth error: %v", err)
+		}
+		authenticators = append(authenticators, oidcAuth)
+		appLogger.Info("OIDC auth enabled (jwks=%s)", cfg.OIDCJWKSURL)
+	}
+
 	// Initialize API
-	handler := api.NewHandler(sc, database, appLogger, cfg.AnkrAPIKey, notifier)
+	configHandler := config.NewConfigHandler(cfg)
+	handler := api.NewHandler(sc, database, appLogger, cfg.AnkrAPIKey, notifier, prom, authenticators, configHandler)
 	mux := http.NewServeMux()
 	handler.RegisterRoutes(mux)
 
+	// Bitcoin is opt-in: it needs its own full node rather than a shared
+	// public RPC, so it's only started when BTC_RPC_URL is configured.
+	var btcScanner *scanner.BTCScanner
+	if cfg.BTCRPCURL != "" {
+		btcScanner, err = scanner.NewBTCScanner(scanner.BTCScannerConfig{
+			Name:              "Bitcoin",
+			RPCURL:            cfg.BTCRPCURL,
+			ConfirmationDepth: cfg.BTCConfirmationDepth,
+		}, database, appLogger)
+		if err != nil {
+			log.Fatalf("BTC scanner error: %v", err)
+		}
+		go btcScanner.Run(context.Background())
+		appLogger.Log("Bitcoin scanner enabled")
+	}
+
 	// Auto-start scanners
 	go func() {
 		time.Sleep(2 * time.Second)
@@ -71,6 +147,9 @@ func main() {
 		<-sigChan
 		appLogger.Log("Shutting down...")
 		sc.StopAll()
+		if btcScanner != nil {
+			btcScanner.Stop()
+		}
 		os.Exit(0)
 	}()
 
@@ -3,31 +3,94 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"math/big"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
-
+	"ecdsa-scanner/internal/archive"
+	"ecdsa-scanner/internal/auth"
+	"ecdsa-scanner/internal/balances"
 	"ecdsa-scanner/internal/config"
 	"ecdsa-scanner/internal/db"
+	"ecdsa-scanner/internal/events"
 	"ecdsa-scanner/internal/logger"
+	"ecdsa-scanner/internal/metrics"
 	"ecdsa-scanner/internal/notify"
+	"ecdsa-scanner/internal/rpcpool"
 	"ecdsa-scanner/internal/scanner"
 )
 
+// eventStreamHeartbeat is how often handleEventStream sends a comment-only
+// SSE line to keep the connection alive through idle proxies/load balancers
+// between real scanner events.
+const eventStreamHeartbeat = 15 * time.Second
+
+// maxLongPollWait caps how long a GET /api/collisions or /api/logs
+// long-poll (?wait=) will block, regardless of what the caller asked for,
+// so a misbehaving client can't tie up a handler goroutine indefinitely.
+const maxLongPollWait = 30 * time.Second
+
+// deadlineTimer is a time.AfterFunc-driven cancel channel: C is closed once
+// wait has elapsed, so a long-poll handler can select on it alongside a
+// data-arrived channel and a ctx.Done() without needing its own timer
+// bookkeeping.
+type deadlineTimer struct {
+	C      <-chan struct{}
+	cancel func() bool
+}
+
+func newDeadlineTimer(wait time.Duration) *deadlineTimer {
+	ch := make(chan struct{})
+	var once sync.Once
+	t := time.AfterFunc(wait, func() { once.Do(func() { close(ch) }) })
+	return &deadlineTimer{C: ch, cancel: t.Stop}
+}
+
+// Stop releases the underlying timer; safe to call after C has already
+// fired.
+func (d *deadlineTimer) Stop() {
+	d.cancel()
+}
+
+// parseLongPoll reads ?since=<cursor> and ?wait=<duration> off r, so
+// handleCollisions and handleLogs can share one long-poll query contract.
+// longPoll is false (and the handler should behave exactly as it did
+// before this existed) unless wait is present and parses to a positive
+// duration.
+func parseLongPoll(r *http.Request) (since uint64, wait time.Duration, longPoll bool) {
+	since, _ = strconv.ParseUint(r.URL.Query().Get("since"), 10, 64)
+
+	raw := r.URL.Query().Get("wait")
+	if raw == "" {
+		return since, 0, false
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return since, 0, false
+	}
+	if d > maxLongPollWait {
+		d = maxLongPollWait
+	}
+	return since, d, true
+}
+
 // GlobalStats represents overall statistics
 type GlobalStats struct {
-	Chains            []scanner.ChainStats `json:"chains"`
-	TotalRValues      int                  `json:"total_r_values"`
-	TotalCollisions   int                  `json:"total_collisions"`
-	RecoveredKeys     int                  `json:"recovered_keys"`
-	RecoveredNonces   int                  `json:"recovered_nonces"`
-	PendingComponents int                  `json:"pending_components"`
-	AutoRecovery      bool                 `json:"auto_recovery"`
-	DatabaseHealthy   bool                 `json:"database_healthy"`
+	Chains            []scanner.ChainStats               `json:"chains"`
+	TotalRValues      int                                `json:"total_r_values"`
+	TotalCollisions   int                                `json:"total_collisions"`
+	RecoveredKeys     int                                `json:"recovered_keys"`
+	RecoveredNonces   int                                `json:"recovered_nonces"`
+	PendingComponents int                                `json:"pending_components"`
+	AutoRecovery      bool                               `json:"auto_recovery"`
+	DatabaseHealthy   bool                               `json:"database_healthy"`
+	RPCPools          map[string][]rpcpool.EndpointStats `json:"rpc_pools"`
 }
 
 // HealthResponse represents health check response
@@ -47,41 +110,76 @@ type ChainHealth struct {
 
 // Handler holds HTTP handler dependencies
 type Handler struct {
-	scanner    *scanner.Scanner
-	db         db.Database
-	logger     *logger.Logger
-	ankrAPIKey string
-	notifier   *notify.Notifier
+	scanner     *scanner.Scanner
+	db          db.Store
+	logger      *logger.Logger
+	ankrAPIKey  string
+	notifier    *notify.Notifier
+	promMetrics *metrics.Prometheus
+	balances    *balances.Service
+	auth        *auth.Middleware
+	config      *config.ConfigHandler
 }
 
-// NewHandler creates a new API handler
-func NewHandler(s *scanner.Scanner, database db.Database, log *logger.Logger, ankrAPIKey string, notifier *notify.Notifier) *Handler {
+// NewHandler creates a new API handler. promMetrics may be nil, in which
+// case /metrics isn't registered - a deployment that doesn't want
+// Prometheus just doesn't pass one. authenticator may also be nil, in
+// which case every mutating route stays unreachable (401) rather than
+// falling back to unauthenticated access. configHandler may also be nil, in
+// which case /api/config's routes answer 501 Not Implemented - a
+// deployment that doesn't wire one up simply doesn't get hot-reload.
+func NewHandler(s *scanner.Scanner, database db.Store, log *logger.Logger, ankrAPIKey string, notifier *notify.Notifier, promMetrics *metrics.Prometheus, authenticator auth.Authenticator, configHandler *config.ConfigHandler) *Handler {
+	balanceSvc, err := balances.NewService(ankrAPIKey)
+	if err != nil {
+		// Only fails if the embedded Multicall3/ERC-20 ABI JSON is malformed,
+		// which would be a bug in this package, not a runtime condition -
+		// fail loudly rather than silently serving zero balances forever.
+		log.Error("Failed to initialize balances service: %v", err)
+	}
 	return &Handler{
-		scanner:    s,
-		db:         database,
-		logger:     log,
-		ankrAPIKey: ankrAPIKey,
-		notifier:   notifier,
+		scanner:     s,
+		db:          database,
+		logger:      log,
+		ankrAPIKey:  ankrAPIKey,
+		notifier:    notifier,
+		promMetrics: promMetrics,
+		balances:    balanceSvc,
+		auth:        auth.NewMiddleware(authenticator, log),
+		config:      configHandler,
 	}
 }
 
 // RegisterRoutes registers all HTTP routes
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/", h.serveIndex)
-	mux.HandleFunc("/api/stats", h.handleStats)
+	mux.HandleFunc("/api/stats", h.auth.Require(auth.RoleViewer, h.handleStats))
 	mux.HandleFunc("/api/health", h.handleHealth)
-	mux.HandleFunc("/api/collisions", h.handleCollisions)
-	mux.HandleFunc("/api/recovered-keys", h.handleRecoveredKeys)
-	mux.HandleFunc("/api/recovered-nonces", h.handleRecoveredNonces)
-	mux.HandleFunc("/api/pending-components", h.handlePendingComponents)
-	mux.HandleFunc("/api/recovery/toggle", h.handleRecoveryToggle)
-	mux.HandleFunc("/api/start", h.handleStart)
-	mux.HandleFunc("/api/stop", h.handleStop)
-	mux.HandleFunc("/api/logs", h.handleLogs)
-	mux.HandleFunc("/api/notifications/test", h.handleTestNotification)
+	mux.HandleFunc("/api/collisions", h.auth.Require(auth.RoleViewer, h.handleCollisions))
+	mux.HandleFunc("/api/recovered-keys", h.auth.Require(auth.RoleViewer, h.handleRecoveredKeys))
+	mux.HandleFunc("/api/recovered-keys/refresh", h.auth.Require(auth.RoleOperator, h.handleRefreshBalances))
+	mux.HandleFunc("/api/recovered-nonces", h.auth.Require(auth.RoleViewer, h.handleRecoveredNonces))
+	mux.HandleFunc("/api/pending-components", h.auth.Require(auth.RoleViewer, h.handlePendingComponents))
+	mux.HandleFunc("/api/reorg-events", h.auth.Require(auth.RoleViewer, h.handleReorgEvents))
+	mux.HandleFunc("/api/stats/refresh", h.auth.Require(auth.RoleOperator, h.handleRefreshStats))
+	mux.HandleFunc("/api/recovery/toggle", h.auth.Require(auth.RoleAdmin, h.handleRecoveryToggle))
+	mux.HandleFunc("/api/start", h.auth.Require(auth.RoleOperator, h.handleStart))
+	mux.HandleFunc("/api/stop", h.auth.Require(auth.RoleOperator, h.handleStop))
+	mux.HandleFunc("/api/logs", h.auth.Require(auth.RoleViewer, h.handleLogs))
+	mux.HandleFunc("/logs/stream", h.auth.Require(auth.RoleViewer, h.handleLogStream))
+	mux.HandleFunc("/api/events", h.auth.Require(auth.RoleViewer, h.handleEventStream))
+	mux.HandleFunc("/api/notifications/test", h.auth.Require(auth.RoleOperator, h.handleTestNotification))
+	mux.HandleFunc("/api/export", h.auth.Require(auth.RoleAdmin, h.handleExport))
+	mux.HandleFunc("/api/import", h.auth.Require(auth.RoleAdmin, h.handleImport))
+	mux.HandleFunc("/api/config", h.auth.Require(auth.RoleAdmin, h.handleConfig))
+	mux.HandleFunc("/api/config/", h.auth.Require(auth.RoleAdmin, h.handleConfigPath))
+
+	if h.promMetrics != nil {
+		mux.Handle("/metrics", h.promMetrics.Handler())
+	}
 }
 
 func (h *Handler) serveIndex(w http.ResponseWriter, r *http.Request) {
+	h.auth.IssueCSRFCookie(w, r)
 	http.ServeFile(w, r, "static/index.html")
 }
 
@@ -95,6 +193,7 @@ func (h *Handler) handleStats(w http.ResponseWriter, r *http.Request) {
 		Chains:          h.scanner.GetChainStats(),
 		AutoRecovery:    h.scanner.IsRecoveryEnabled(),
 		DatabaseHealthy: true,
+		RPCPools:        h.scanner.RPCPoolStats(),
 	}
 
 	if err != nil {
@@ -108,6 +207,11 @@ func (h *Handler) handleStats(w http.ResponseWriter, r *http.Request) {
 		stats.PendingComponents = dbStats.PendingComponents
 		stats.DatabaseHealthy = dbStats.Healthy
 	}
+	h.scanner.Metrics().SetDatabaseHealthy(stats.DatabaseHealthy)
+	h.scanner.Metrics().SetPendingComponents(stats.PendingComponents)
+	for _, cs := range stats.Chains {
+		h.scanner.Metrics().SetHeadLag(cs.Chain, int64(cs.LatestBlock)-int64(cs.CurrentBlock))
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(stats)
@@ -144,7 +248,19 @@ func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleCollisions serves the current collision list. Given ?wait=<duration>,
+// it long-polls: blocking until a new collision is published, wait elapses,
+// or the client disconnects, whichever comes first, so a dashboard can hold
+// a request open instead of polling every 1-5s. ?since=<cursor> is only
+// consulted for the wait (a cursor the caller already has - nothing newer
+// published yet? keep waiting); the response always carries the full
+// current list, with the new cursor in the ETag header.
 func (h *Handler) handleCollisions(w http.ResponseWriter, r *http.Request) {
+	since, wait, longPoll := parseLongPoll(r)
+	if longPoll {
+		h.waitForCollisionActivity(r.Context(), since, wait)
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
 	defer cancel()
 
@@ -157,8 +273,8 @@ func (h *Handler) handleCollisions(w http.ResponseWriter, r *http.Request) {
 
 	// Enrich with chain names
 	type EnrichedCollision struct {
-		RValue  string `json:"r_value"`
-		TxRefs  []struct {
+		RValue string `json:"r_value"`
+		TxRefs []struct {
 			TxHash    string `json:"tx_hash"`
 			ChainID   int    `json:"chain_id"`
 			ChainName string `json:"chain_name"`
@@ -186,15 +302,41 @@ func (h *Handler) handleCollisions(w http.ResponseWriter, r *http.Request) {
 		enriched = append(enriched, ec)
 	}
 
+	w.Header().Set("ETag", strconv.FormatUint(h.scanner.Events().LastID(), 10))
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(enriched)
 }
 
+// waitForCollisionActivity blocks until a KindCollision event newer than
+// since is published on the scanner's event bus, wait elapses, or ctx is
+// canceled (the client disconnected), whichever comes first.
+func (h *Handler) waitForCollisionActivity(ctx context.Context, since uint64, wait time.Duration) {
+	stream, unsubscribe := h.scanner.Events().Subscribe(since)
+	defer unsubscribe()
+
+	timer := newDeadlineTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case ev, ok := <-stream:
+			if !ok || ev.Kind == events.KindCollision {
+				return
+			}
+		case <-timer.C:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // RecoveredKeyWithBalance extends RecoveredKey with current balance
 type RecoveredKeyWithBalance struct {
 	db.RecoveredKey
-	BalanceWei string `json:"balance_wei"`
-	BalanceEth string `json:"balance_eth"`
+	BalanceWei string                  `json:"balance_wei"`
+	BalanceEth string                  `json:"balance_eth"`
+	Tokens     []balances.TokenBalance `json:"tokens,omitempty"`
 }
 
 func (h *Handler) handleRecoveredKeys(w http.ResponseWriter, r *http.Request) {
@@ -208,7 +350,22 @@ func (h *Handler) handleRecoveredKeys(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Enrich with chain names and balances
+	// Group addresses by chain so each chain is enriched with a single
+	// Multicall3 round trip instead of one RPC call per key.
+	addressesByChain := make(map[int][]string)
+	for _, key := range keys {
+		addressesByChain[key.ChainID] = append(addressesByChain[key.ChainID], key.Address)
+	}
+	balancesByChain := make(map[int]map[string]balances.Result, len(addressesByChain))
+	for chainID, addrs := range addressesByChain {
+		res, err := h.balances.LookupMany(ctx, chainID, addrs)
+		if err != nil {
+			h.logger.Warn("Failed to look up balances for chain %d: %v", chainID, err)
+			continue
+		}
+		balancesByChain[chainID] = res
+	}
+
 	result := make([]RecoveredKeyWithBalance, len(keys))
 	for i, key := range keys {
 		if cfg := config.ChainByID(key.ChainID); cfg != nil {
@@ -220,11 +377,10 @@ func (h *Handler) handleRecoveredKeys(w http.ResponseWriter, r *http.Request) {
 			BalanceEth:   "0",
 		}
 
-		// Fetch balance from RPC
-		balance, err := h.getBalance(ctx, key.Address, key.ChainID)
-		if err == nil {
-			result[i].BalanceWei = balance.String()
-			result[i].BalanceEth = weiToEth(balance)
+		if bal, ok := balancesByChain[key.ChainID][key.Address]; ok {
+			result[i].BalanceWei = bal.BalanceWei.String()
+			result[i].BalanceEth = weiToEth(bal.BalanceWei)
+			result[i].Tokens = bal.Tokens
 		}
 	}
 
@@ -232,25 +388,19 @@ func (h *Handler) handleRecoveredKeys(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(result)
 }
 
-// getBalance fetches the current balance of an address
-func (h *Handler) getBalance(ctx context.Context, address string, chainID int) (*big.Int, error) {
-	cfg := config.ChainByID(chainID)
-	if cfg == nil {
-		return nil, nil
-	}
-
-	rpcURL := cfg.RPCURL
-	if h.ankrAPIKey != "" && strings.Contains(rpcURL, "ankr.com") {
-		rpcURL = rpcURL + "/" + h.ankrAPIKey
+// handleRefreshBalances force-invalidates the balance cache, so the next
+// /api/recovered-keys request requeries RPC for every address instead of
+// serving a cached (possibly up-to-60s-stale) balance.
+func (h *Handler) handleRefreshBalances(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
-	client, err := ethclient.DialContext(ctx, rpcURL)
-	if err != nil {
-		return nil, err
-	}
-	defer client.Close()
+	h.balances.InvalidateAll()
 
-	return client.BalanceAt(ctx, common.HexToAddress(address), nil)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "invalidated"})
 }
 
 // weiToEth converts wei to ETH as a string with 6 decimal places
@@ -261,7 +411,7 @@ func weiToEth(wei *big.Int) string {
 	// Convert to float: wei / 1e18
 	fWei := new(big.Float).SetInt(wei)
 	ethValue := new(big.Float).Quo(fWei, big.NewFloat(1e18))
-	
+
 	// Format with up to 6 decimal places, trim trailing zeros
 	text := ethValue.Text('f', 6)
 	// Trim trailing zeros after decimal point
@@ -305,6 +455,59 @@ func (h *Handler) handlePendingComponents(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(comps)
 }
 
+// handleReorgEvents returns the most recently detected reorgs for a chain,
+// so operators can see how often (and how deep) reorgs are happening
+// without trawling logs.
+func (h *Handler) handleReorgEvents(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	chainID, err := strconv.Atoi(r.URL.Query().Get("chain_id"))
+	if err != nil {
+		http.Error(w, "chain_id is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := 50
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	events, err := h.db.GetReorgEvents(ctx, chainID, limit)
+	if err != nil {
+		h.logger.Error("Failed to get reorg events: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// handleRefreshStats forces an immediate stats materialized view refresh,
+// rather than waiting for the background interval, for operators who just
+// ran a backfill and want GetStats to reflect it right away.
+func (h *Handler) handleRefreshStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	if err := h.db.RefreshStats(ctx); err != nil {
+		h.logger.Error("Failed to refresh stats: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "refreshed"})
+}
+
 func (h *Handler) handleRecoveryToggle(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -365,9 +568,123 @@ func (h *Handler) handleStop(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "stopped"})
 }
 
+// handleLogs serves buffered log entries newer than ?since=<id> (default 0,
+// i.e. everything buffered). Given ?wait=<duration>, it long-polls:
+// blocking until a new entry is logged, wait elapses, or the client
+// disconnects, whichever comes first, so a dashboard can hold a request
+// open instead of polling every 1-5s. The response carries the new cursor
+// in the ETag header.
 func (h *Handler) handleLogs(w http.ResponseWriter, r *http.Request) {
+	since, wait, longPoll := parseLongPoll(r)
+	if longPoll {
+		h.waitForLogActivity(r.Context(), wait)
+	}
+
+	w.Header().Set("ETag", strconv.FormatUint(h.logger.LastID(), 10))
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(h.logger.GetEntries())
+	json.NewEncoder(w).Encode(h.logger.EntriesSince(since))
+}
+
+// waitForLogActivity blocks until a new log entry is published, wait
+// elapses, or ctx is canceled (the client disconnected), whichever comes
+// first.
+func (h *Handler) waitForLogActivity(ctx context.Context, wait time.Duration) {
+	stream, unsubscribe := h.logger.Subscribe()
+	defer unsubscribe()
+
+	timer := newDeadlineTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-stream:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// handleLogStream streams newly-logged entries as they happen via
+// server-sent events, so the dashboard can tail logs instead of polling
+// /api/logs.
+func (h *Handler) handleLogStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	entries, unsubscribe := h.logger.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case entry, ok := <-entries:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(entry)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+// handleEventStream streams live scanner/recovery events (new R-values,
+// collisions, recovered keys/nonces, chain start/stop, RPC error
+// thresholds) as server-sent events, so the dashboard can react as things
+// happen instead of polling /api/stats, /api/collisions, etc. A client
+// reconnecting after a drop can send a Last-Event-ID header (standard SSE
+// resume behavior) to replay whatever it missed from the bus's in-memory
+// backlog.
+func (h *Handler) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	var lastEventID uint64
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		lastEventID, _ = strconv.ParseUint(id, 10, 64)
+	}
+
+	stream, unsubscribe := h.scanner.Events().Subscribe(lastEventID)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-stream:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\ndata: %s\n\n", ev.ID, data)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprintf(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
 }
 
 func (h *Handler) handleTestNotification(w http.ResponseWriter, r *http.Request) {
@@ -401,3 +718,224 @@ func (h *Handler) handleTestNotification(w http.ResponseWriter, r *http.Request)
 		"success": true,
 	})
 }
+
+// handleExport streams an encrypted, versioned archive of recovered keys,
+// recovered nonces, pending cross-key components, and observed r-values,
+// so an operator can move a full installation's recovered state to another
+// host (or an offline backup) without a private key ever touching disk in
+// the clear. Encryption is mandatory: the caller must supply either an
+// X-Export-Passphrase header or one or more ?recipient= age public keys.
+func (h *Handler) handleExport(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	var chainID int
+	if v := r.URL.Query().Get("chain_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "invalid chain_id", http.StatusBadRequest)
+			return
+		}
+		chainID = id
+	}
+
+	bundle, err := archive.Collect(ctx, h.db, chainID)
+	if err != nil {
+		h.logger.Error("Export: failed to collect archive: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	plaintext, err := archive.Pack(bundle, chainID)
+	if err != nil {
+		h.logger.Error("Export: failed to pack archive: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ciphertext, err := encryptArchive(r, plaintext)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Info("Export: archive produced (chain_id=%d, keys=%d, nonces=%d, pending=%d, r_values=%d)",
+		chainID, len(bundle.RecoveredKeys), len(bundle.RecoveredNonces), len(bundle.PendingComponents), len(bundle.Collisions))
+
+	w.Header().Set("Content-Type", "application/age-encryption")
+	w.Header().Set("Content-Disposition", `attachment; filename="ecdsa-scanner-export.age"`)
+	w.Write(ciphertext)
+}
+
+// handleImport decrypts and applies an archive produced by handleExport.
+// It's transactional to the extent db.Store allows: the whole archive is
+// decoded and checksum-verified before a single row is written, so a
+// malformed or tampered upload never partially applies.
+func (h *Handler) handleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	if err := r.ParseMultipartForm(64 << 20); err != nil {
+		http.Error(w, "expected multipart/form-data with an 'archive' file", http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("archive")
+	if err != nil {
+		http.Error(w, "missing 'archive' file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	ciphertext, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "failed to read archive", http.StatusBadRequest)
+		return
+	}
+
+	plaintext, err := decryptArchive(r, ciphertext)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bundle, manifest, err := archive.Unpack(plaintext)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summary, err := archive.Apply(ctx, h.db, bundle)
+	if err != nil {
+		h.logger.Error("Import: failed to apply archive: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.Info("Import: archive applied (manifest_chain_id=%d, keys=%d/%d written, nonces=%d/%d, pending=%d/%d, r_values=%d/%d)",
+		manifest.ChainID,
+		summary.KeysWritten, summary.KeysWritten+summary.KeysSkipped,
+		summary.NoncesWritten, summary.NoncesWritten+summary.NoncesSkipped,
+		summary.PendingWritten, summary.PendingWritten+summary.PendingSkipped,
+		summary.RValuesWritten, summary.RValuesWritten+summary.RValuesSkipped)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// encryptArchive picks passphrase or recipient-list encryption based on
+// which the request supplied. The X-Export-Passphrase header takes
+// precedence over repeated ?recipient= query params if both are present.
+func encryptArchive(r *http.Request, plaintext []byte) ([]byte, error) {
+	if passphrase := r.Header.Get("X-Export-Passphrase"); passphrase != "" {
+		return archive.EncryptWithPassphrase(plaintext, passphrase)
+	}
+	if recipients := r.URL.Query()["recipient"]; len(recipients) > 0 {
+		return archive.EncryptWithRecipients(plaintext, recipients)
+	}
+	return nil, fmt.Errorf("export requires either an X-Export-Passphrase header or one or more ?recipient= age public keys")
+}
+
+// decryptArchive mirrors encryptArchive for the import side: the caller
+// proves they hold the passphrase or a matching age identity.
+func decryptArchive(r *http.Request, ciphertext []byte) ([]byte, error) {
+	if passphrase := r.Header.Get("X-Export-Passphrase"); passphrase != "" {
+		return archive.DecryptWithPassphrase(ciphertext, passphrase)
+	}
+	if identities := r.MultipartForm.Value["identity"]; len(identities) > 0 {
+		return archive.DecryptWithIdentities(ciphertext, identities)
+	}
+	return nil, fmt.Errorf("import requires either an X-Export-Passphrase header or one or more 'identity' form fields")
+}
+
+// configPatchRequest is the PATCH /api/config request body: the caller's
+// fingerprint (from a prior GET /api/config) plus the chains it wants to
+// replace. Only chains is patchable for now - the rest of Config is either
+// process-startup-only (ports, DSNs) or not something this API should let a
+// remote caller rewrite (auth tokens).
+type configPatchRequest struct {
+	Fingerprint string               `json:"fingerprint"`
+	Chains      []config.ChainConfig `json:"chains"`
+}
+
+// handleConfig serves GET /api/config (the running config plus its
+// fingerprint) and PATCH /api/config (a fingerprint-guarded chain-list
+// update, applied live to the running scanner and persisted to disk).
+func (h *Handler) handleConfig(w http.ResponseWriter, r *http.Request) {
+	if h.config == nil {
+		http.Error(w, "config hot-reload not configured", http.StatusNotImplemented)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		data, err := h.config.MarshalJSON()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+
+	case http.MethodPatch:
+		var req configPatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		err := h.config.DoLockedAction(req.Fingerprint, func(cfg *config.Config) error {
+			cfg.Chains = req.Chains
+			return nil
+		})
+		if err != nil {
+			if errors.Is(err, config.ErrFingerprintMismatch) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			h.logger.Error("Config: failed to apply patch: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		h.scanner.ApplyChainConfig(h.config.Snapshot().Chains)
+		h.logger.Info("Config: chain list updated (%d chains), applied live", len(req.Chains))
+
+		data, err := h.config.MarshalJSON()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleConfigPath serves GET /api/config/<dot.path>, resolving a single
+// field out of the running config via config.Lookup - e.g.
+// /api/config/chains.0.rpc_url - so a caller doesn't have to fetch and
+// parse the whole config just to read one value.
+func (h *Handler) handleConfigPath(w http.ResponseWriter, r *http.Request) {
+	if h.config == nil {
+		http.Error(w, "config hot-reload not configured", http.StatusNotImplemented)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/config/")
+	value, err := config.Lookup(h.config.Snapshot().Redacted(), path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,282 @@
+// Package archive implements encrypted export/import of a scanner
+// installation's recovered-key state, so an operator can move it between
+// installs (or to an offline host for safekeeping) without ever writing a
+// private key to disk in the clear.
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/blake2b"
+
+	"ecdsa-scanner/internal/db"
+)
+
+// ManifestVersion is bumped whenever the archive's table set or row schema
+// changes in a way that isn't forward-compatible with older importers.
+const ManifestVersion = 1
+
+const manifestFile = "manifest.json"
+
+// Manifest describes the contents of an archive, so an importer (possibly
+// a different build of this scanner) can tell what it's being asked to
+// load before it trusts any of the row data.
+type Manifest struct {
+	Version      int    `json:"version"`
+	ChainID      int    `json:"chain_id,omitempty"` // 0 means "all chains"
+	Checksum     string `json:"checksum"`           // hex BLAKE2b-256 over the concatenated table files, in Bundle field order
+	KeyCount     int    `json:"key_count"`
+	NonceCount   int    `json:"nonce_count"`
+	PendingCount int    `json:"pending_count"`
+	RValueCount  int    `json:"r_value_count"`
+}
+
+// Bundle is the decoded contents of an archive: one row slice per table,
+// each of which is serialized to the tar as newline-delimited JSON.
+type Bundle struct {
+	RecoveredKeys     []db.RecoveredKey
+	RecoveredNonces   []db.RecoveredNonce
+	PendingComponents []db.PendingComponent
+	Collisions        []db.Collision
+}
+
+// tableFiles lists, in the fixed order the checksum and tar entries use,
+// the NDJSON file each Bundle field is serialized to.
+var tableFiles = []string{
+	"recovered_keys.ndjson",
+	"recovered_nonces.ndjson",
+	"pending_components.ndjson",
+	"observed_r_values.ndjson",
+}
+
+// Collect reads every table this archive format covers out of store,
+// optionally restricted to chainID (0 means every chain).
+func Collect(ctx context.Context, store db.Store, chainID int) (Bundle, error) {
+	keys, err := store.GetRecoveredKeys(ctx)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("archive: reading recovered keys: %w", err)
+	}
+	nonces, err := store.GetRecoveredNonces(ctx)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("archive: reading recovered nonces: %w", err)
+	}
+	pending, err := store.GetPendingComponents(ctx)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("archive: reading pending components: %w", err)
+	}
+	collisions, err := store.GetAllCollisions(ctx)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("archive: reading observed r-values: %w", err)
+	}
+
+	b := Bundle{Collisions: collisions}
+	for _, k := range keys {
+		if chainID == 0 || k.ChainID == chainID {
+			b.RecoveredKeys = append(b.RecoveredKeys, k)
+		}
+	}
+	b.RecoveredNonces = nonces
+	b.PendingComponents = pending
+	if chainID != 0 {
+		b.PendingComponents = nil
+		for _, p := range pending {
+			if containsChain(p.ChainIDs, chainID) {
+				b.PendingComponents = append(b.PendingComponents, p)
+			}
+		}
+		b.Collisions = nil
+		for _, c := range collisions {
+			for _, ref := range c.TxRefs {
+				if ref.ChainID == chainID {
+					b.Collisions = append(b.Collisions, c)
+					break
+				}
+			}
+		}
+	}
+	return b, nil
+}
+
+func containsChain(ids []int, chainID int) bool {
+	for _, id := range ids {
+		if id == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+// Pack serializes b into a tar.gz, plaintext (encryption is a separate
+// step - see EncryptWithPassphrase and EncryptWithRecipients), prefixed
+// with a manifest computed from its content.
+func Pack(b Bundle, chainID int) ([]byte, error) {
+	tableData, err := ndjsonTables(b)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := Manifest{
+		Version:      ManifestVersion,
+		ChainID:      chainID,
+		Checksum:     checksumTables(tableData),
+		KeyCount:     len(b.RecoveredKeys),
+		NonceCount:   len(b.RecoveredNonces),
+		PendingCount: len(b.PendingComponents),
+		RValueCount:  len(b.Collisions),
+	}
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("archive: marshaling manifest: %w", err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	if err := writeTarFile(tw, manifestFile, manifestJSON); err != nil {
+		return nil, err
+	}
+	for i, name := range tableFiles {
+		if err := writeTarFile(tw, name, tableData[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("archive: closing tar writer: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("archive: closing gzip writer: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Unpack parses a plaintext tar.gz produced by Pack back into a Bundle,
+// verifying the manifest's checksum before handing back a single row.
+func Unpack(data []byte) (Bundle, Manifest, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return Bundle{}, Manifest{}, fmt.Errorf("archive: opening gzip: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Bundle{}, Manifest{}, fmt.Errorf("archive: reading tar: %w", err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return Bundle{}, Manifest{}, fmt.Errorf("archive: reading tar entry %s: %w", hdr.Name, err)
+		}
+		files[hdr.Name] = content
+	}
+
+	manifestJSON, ok := files[manifestFile]
+	if !ok {
+		return Bundle{}, Manifest{}, fmt.Errorf("archive: missing %s", manifestFile)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return Bundle{}, Manifest{}, fmt.Errorf("archive: parsing manifest: %w", err)
+	}
+	if manifest.Version != ManifestVersion {
+		return Bundle{}, Manifest{}, fmt.Errorf("archive: unsupported manifest version %d (want %d)", manifest.Version, ManifestVersion)
+	}
+
+	tableData := make([][]byte, len(tableFiles))
+	for i, name := range tableFiles {
+		tableData[i] = files[name] // absent entries decode as zero rows
+	}
+	if got := checksumTables(tableData); got != manifest.Checksum {
+		return Bundle{}, Manifest{}, fmt.Errorf("archive: checksum mismatch (manifest says %s, computed %s) - archive is corrupt or was tampered with", manifest.Checksum, got)
+	}
+
+	var b Bundle
+	if err := unmarshalNDJSON(tableData[0], &b.RecoveredKeys); err != nil {
+		return Bundle{}, Manifest{}, fmt.Errorf("archive: decoding %s: %w", tableFiles[0], err)
+	}
+	if err := unmarshalNDJSON(tableData[1], &b.RecoveredNonces); err != nil {
+		return Bundle{}, Manifest{}, fmt.Errorf("archive: decoding %s: %w", tableFiles[1], err)
+	}
+	if err := unmarshalNDJSON(tableData[2], &b.PendingComponents); err != nil {
+		return Bundle{}, Manifest{}, fmt.Errorf("archive: decoding %s: %w", tableFiles[2], err)
+	}
+	if err := unmarshalNDJSON(tableData[3], &b.Collisions); err != nil {
+		return Bundle{}, Manifest{}, fmt.Errorf("archive: decoding %s: %w", tableFiles[3], err)
+	}
+	return b, manifest, nil
+}
+
+func ndjsonTables(b Bundle) ([][]byte, error) {
+	keys, err := marshalNDJSON(b.RecoveredKeys)
+	if err != nil {
+		return nil, fmt.Errorf("archive: encoding recovered keys: %w", err)
+	}
+	nonces, err := marshalNDJSON(b.RecoveredNonces)
+	if err != nil {
+		return nil, fmt.Errorf("archive: encoding recovered nonces: %w", err)
+	}
+	pending, err := marshalNDJSON(b.PendingComponents)
+	if err != nil {
+		return nil, fmt.Errorf("archive: encoding pending components: %w", err)
+	}
+	collisions, err := marshalNDJSON(b.Collisions)
+	if err != nil {
+		return nil, fmt.Errorf("archive: encoding observed r-values: %w", err)
+	}
+	return [][]byte{keys, nonces, pending, collisions}, nil
+}
+
+func marshalNDJSON[T any](rows []T) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshalNDJSON[T any](data []byte, out *[]T) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var row T
+		if err := dec.Decode(&row); err != nil {
+			return err
+		}
+		*out = append(*out, row)
+	}
+	return nil
+}
+
+func checksumTables(tableData [][]byte) string {
+	h, _ := blake2b.New256(nil) // nil key, fixed digest size: never errors
+	for _, data := range tableData {
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(data)), Mode: 0o600}); err != nil {
+		return fmt.Errorf("archive: writing tar header for %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("archive: writing tar entry %s: %w", name, err)
+	}
+	return nil
+}
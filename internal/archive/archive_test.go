@@ -0,0 +1,230 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"ecdsa-scanner/internal/db"
+)
+
+func sampleBundle() Bundle {
+	return Bundle{
+		RecoveredKeys: []db.RecoveredKey{
+			{Address: "0xabc", PrivateKey: "deadbeef", ChainID: 1, ChainName: "Ethereum", RValues: []string{"r1"}, TxHashes: []string{"t1"}},
+		},
+		RecoveredNonces: []db.RecoveredNonce{
+			{RValue: "r1", KValue: "k1", DerivedFromKeyID: 1},
+		},
+		PendingComponents: []db.PendingComponent{
+			{RValues: []string{"r2", "r3"}, Addresses: []string{"0xdef"}, ChainIDs: []int{1}, Equations: 1, Unknowns: 2},
+		},
+		Collisions: []db.Collision{
+			{RValue: "r1", TxRefs: []db.TxRef{{TxHash: "t1", ChainID: 1, BlockNumber: 100}}},
+		},
+	}
+}
+
+func TestPackUnpackRoundTrip(t *testing.T) {
+	b := sampleBundle()
+	packed, err := Pack(b, 0)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	got, manifest, err := Unpack(packed)
+	if err != nil {
+		t.Fatalf("Unpack failed: %v", err)
+	}
+	if manifest.KeyCount != 1 || manifest.NonceCount != 1 || manifest.PendingCount != 1 || manifest.RValueCount != 1 {
+		t.Errorf("unexpected manifest counts: %+v", manifest)
+	}
+	if len(got.RecoveredKeys) != 1 || got.RecoveredKeys[0].Address != "0xabc" {
+		t.Errorf("recovered keys didn't round-trip: %+v", got.RecoveredKeys)
+	}
+	if len(got.RecoveredNonces) != 1 || got.RecoveredNonces[0].RValue != "r1" {
+		t.Errorf("recovered nonces didn't round-trip: %+v", got.RecoveredNonces)
+	}
+	if len(got.PendingComponents) != 1 {
+		t.Errorf("pending components didn't round-trip: %+v", got.PendingComponents)
+	}
+	if len(got.Collisions) != 1 {
+		t.Errorf("collisions didn't round-trip: %+v", got.Collisions)
+	}
+}
+
+func TestUnpackRejectsTamperedArchive(t *testing.T) {
+	bundle := sampleBundle()
+	// Pack two different bundles and splice the second's recovered-keys
+	// table into the first's archive: a structurally valid tar.gz whose
+	// content no longer matches its own manifest checksum.
+	packed, err := Pack(bundle, 0)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	bundle.RecoveredKeys[0].Address = "0xdifferent"
+	repacked, err := Pack(bundle, 0)
+	if err != nil {
+		t.Fatalf("Pack (modified) failed: %v", err)
+	}
+
+	tampered := spliceTarEntry(t, packed, repacked, "recovered_keys.ndjson")
+
+	if _, _, err := Unpack(tampered); err == nil {
+		t.Error("expected checksum mismatch on tampered archive")
+	}
+}
+
+// spliceTarEntry decompresses base and donor, replaces base's copy of name
+// with donor's, and regzips the result - used to build an archive whose
+// manifest checksum no longer matches its content.
+func spliceTarEntry(t *testing.T, base, donor []byte, name string) []byte {
+	t.Helper()
+	donorEntries := readTarEntries(t, donor)
+	baseEntries := readTarEntries(t, base)
+	baseEntries[name] = donorEntries[name]
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, entryName := range []string{manifestFile, "recovered_keys.ndjson", "recovered_nonces.ndjson", "pending_components.ndjson", "observed_r_values.ndjson"} {
+		if err := writeTarFile(tw, entryName, baseEntries[entryName]); err != nil {
+			t.Fatalf("writeTarFile failed: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar Close failed: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip Close failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func readTarEntries(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip.NewReader failed: %v", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	entries := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar read failed: %v", err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("tar content read failed: %v", err)
+		}
+		entries[hdr.Name] = content
+	}
+	return entries
+}
+
+func TestEncryptDecryptPassphraseRoundTrip(t *testing.T) {
+	plaintext := []byte("super secret archive contents")
+	ciphertext, err := EncryptWithPassphrase(plaintext, "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("EncryptWithPassphrase failed: %v", err)
+	}
+	if string(ciphertext) == string(plaintext) {
+		t.Fatal("ciphertext should not equal plaintext")
+	}
+
+	decrypted, err := DecryptWithPassphrase(ciphertext, "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("DecryptWithPassphrase failed: %v", err)
+	}
+	if string(decrypted) != string(plaintext) {
+		t.Errorf("decrypted = %q, want %q", decrypted, plaintext)
+	}
+
+	if _, err := DecryptWithPassphrase(ciphertext, "wrong passphrase"); err == nil {
+		t.Error("expected decryption to fail with the wrong passphrase")
+	}
+}
+
+func TestApplyDeduplicatesAgainstExistingRows(t *testing.T) {
+	ctx := context.Background()
+	store := db.NewMock()
+	b := sampleBundle()
+
+	first, err := Apply(ctx, store, b)
+	if err != nil {
+		t.Fatalf("first Apply failed: %v", err)
+	}
+	if first.KeysWritten != 1 || first.NoncesWritten != 1 || first.PendingWritten != 1 || first.RValuesWritten != 1 {
+		t.Errorf("unexpected first-apply summary: %+v", first)
+	}
+
+	second, err := Apply(ctx, store, b)
+	if err != nil {
+		t.Fatalf("second Apply failed: %v", err)
+	}
+	if second.KeysSkipped != 1 || second.NoncesSkipped != 1 || second.PendingSkipped != 1 || second.RValuesSkipped != 1 {
+		t.Errorf("expected re-applying the same bundle to skip every row, got: %+v", second)
+	}
+
+	keys, err := store.GetRecoveredKeys(ctx)
+	if err != nil {
+		t.Fatalf("GetRecoveredKeys failed: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Errorf("expected exactly one recovered key after importing twice, got %d", len(keys))
+	}
+}
+
+// failingStore wraps a Store and fails the one call named by failOn, so
+// tests can force Apply to error partway through without needing a real
+// backend that can be made to fail on demand. WithinTransaction re-wraps
+// the tx it's handed, so the injected failure also applies to writes made
+// through the transactional view Apply actually uses.
+type failingStore struct {
+	db.Store
+	failOn string
+}
+
+func (f *failingStore) WithinTransaction(ctx context.Context, fn func(db.Store) error) error {
+	return f.Store.WithinTransaction(ctx, func(tx db.Store) error {
+		return fn(&failingStore{Store: tx, failOn: f.failOn})
+	})
+}
+
+func (f *failingStore) SavePendingComponent(ctx context.Context, comp *db.PendingComponent) error {
+	if f.failOn == "SavePendingComponent" {
+		return errors.New("forced failure")
+	}
+	return f.Store.SavePendingComponent(ctx, comp)
+}
+
+func TestApplyRollsBackEveryWriteOnMidImportFailure(t *testing.T) {
+	ctx := context.Background()
+	mock := db.NewMock()
+	store := &failingStore{Store: mock, failOn: "SavePendingComponent"}
+	b := sampleBundle()
+
+	if _, err := Apply(ctx, store, b); err == nil {
+		t.Fatal("expected Apply to fail")
+	}
+
+	keys, err := mock.GetRecoveredKeys(ctx)
+	if err != nil {
+		t.Fatalf("GetRecoveredKeys failed: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("expected the recovered key write to be rolled back, got %d keys", len(keys))
+	}
+	if _, err := mock.GetRecoveredNonce(ctx, "r1"); err != db.ErrNotFound {
+		t.Errorf("expected the recovered nonce write to be rolled back, got err=%v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package archive
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// EncryptWithPassphrase wraps plaintext in an age passphrase-encrypted
+// envelope, so the archive is safe to move over an untrusted channel or
+// rest on an offline host's disk.
+func EncryptWithPassphrase(plaintext []byte, passphrase string) ([]byte, error) {
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("archive: building scrypt recipient: %w", err)
+	}
+	return encryptTo(plaintext, recipient)
+}
+
+// EncryptWithRecipients wraps plaintext for one or more age X25519
+// recipients (e.g. an operator's and a backup custodian's public keys), so
+// either can decrypt independently without sharing a passphrase.
+func EncryptWithRecipients(plaintext []byte, recipientStrs []string) ([]byte, error) {
+	recipients, err := age.ParseRecipients(strings.NewReader(strings.Join(recipientStrs, "\n")))
+	if err != nil {
+		return nil, fmt.Errorf("archive: parsing recipients: %w", err)
+	}
+	return encryptTo(plaintext, recipients...)
+}
+
+func encryptTo(plaintext []byte, recipients ...age.Recipient) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("archive: opening age writer: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("archive: writing age ciphertext: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("archive: closing age writer: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DecryptWithPassphrase reverses EncryptWithPassphrase.
+func DecryptWithPassphrase(ciphertext []byte, passphrase string) ([]byte, error) {
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("archive: building scrypt identity: %w", err)
+	}
+	return decryptWith(ciphertext, identity)
+}
+
+// DecryptWithIdentities reverses EncryptWithRecipients, given the
+// corresponding age X25519 identity strings (AGE-SECRET-KEY-1...).
+func DecryptWithIdentities(ciphertext []byte, identityStrs []string) ([]byte, error) {
+	identities, err := age.ParseIdentities(strings.NewReader(strings.Join(identityStrs, "\n")))
+	if err != nil {
+		return nil, fmt.Errorf("archive: parsing identities: %w", err)
+	}
+	return decryptWith(ciphertext, identities...)
+}
+
+func decryptWith(ciphertext []byte, identities ...age.Identity) ([]byte, error) {
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), identities...)
+	if err != nil {
+		return nil, fmt.Errorf("archive: decrypting: %w", err)
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("archive: reading decrypted plaintext: %w", err)
+	}
+	return plaintext, nil
+}
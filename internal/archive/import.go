@@ -0,0 +1,119 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"ecdsa-scanner/internal/db"
+)
+
+// Summary reports how many rows of each table an Apply actually wrote,
+// versus how many it skipped because an equivalent row already existed.
+type Summary struct {
+	KeysWritten, KeysSkipped       int
+	NoncesWritten, NoncesSkipped   int
+	PendingWritten, PendingSkipped int
+	RValuesWritten, RValuesSkipped int
+}
+
+// Apply writes b into store, deduplicating every table against what's
+// already there by primary key (recovered keys by address+chain, nonces by
+// r-value, r-values by r-value, pending components by their r-value set)
+// so the same archive can be imported more than once without creating
+// duplicate rows.
+//
+// Bundle content is fully decoded and checksum-verified by Unpack before
+// Apply ever runs (see Unpack). Apply itself runs entirely inside
+// store.WithinTransaction, so a write error partway through leaves store
+// exactly as it was rather than with a partial import - all-or-nothing,
+// per store.WithinTransaction's guarantee for the backend in use (a real
+// transaction for db/postgres, a snapshot/restore for db.MockDB; see
+// db/clickhouse's WithinTransaction for its documented exception).
+func Apply(ctx context.Context, store db.Store, b Bundle) (Summary, error) {
+	var s Summary
+
+	err := store.WithinTransaction(ctx, func(tx db.Store) error {
+		for i := range b.RecoveredKeys {
+			key := b.RecoveredKeys[i]
+			recovered, err := tx.IsKeyRecovered(ctx, key.Address, key.ChainID)
+			if err != nil {
+				return fmt.Errorf("archive: checking existing key %s/%d: %w", key.Address, key.ChainID, err)
+			}
+			if recovered {
+				s.KeysSkipped++
+				continue
+			}
+			if _, err := tx.SaveRecoveredKey(ctx, &key); err != nil {
+				return fmt.Errorf("archive: saving recovered key %s/%d: %w", key.Address, key.ChainID, err)
+			}
+			s.KeysWritten++
+		}
+
+		for i := range b.RecoveredNonces {
+			nonce := b.RecoveredNonces[i]
+			if _, err := tx.GetRecoveredNonce(ctx, nonce.RValue); err == nil {
+				s.NoncesSkipped++
+				continue
+			} else if err != db.ErrNotFound {
+				return fmt.Errorf("archive: checking existing nonce %s: %w", nonce.RValue, err)
+			}
+			if err := tx.SaveRecoveredNonce(ctx, &nonce); err != nil {
+				return fmt.Errorf("archive: saving recovered nonce %s: %w", nonce.RValue, err)
+			}
+			s.NoncesWritten++
+		}
+
+		existingPending, err := tx.GetPendingComponents(ctx)
+		if err != nil {
+			return fmt.Errorf("archive: listing existing pending components: %w", err)
+		}
+		seenPending := make(map[string]bool, len(existingPending))
+		for _, p := range existingPending {
+			seenPending[pendingKey(p)] = true
+		}
+		for i := range b.PendingComponents {
+			comp := b.PendingComponents[i]
+			key := pendingKey(comp)
+			if seenPending[key] {
+				s.PendingSkipped++
+				continue
+			}
+			if err := tx.SavePendingComponent(ctx, &comp); err != nil {
+				return fmt.Errorf("archive: saving pending component: %w", err)
+			}
+			seenPending[key] = true
+			s.PendingWritten++
+		}
+
+		for _, c := range b.Collisions {
+			for _, ref := range c.TxRefs {
+				_, existed, err := tx.CheckAndInsertRValue(ctx, c.RValue, ref.TxHash, ref.ChainID, ref.BlockNumber)
+				if err != nil {
+					return fmt.Errorf("archive: restoring r-value %s: %w", c.RValue, err)
+				}
+				if existed {
+					s.RValuesSkipped++
+				} else {
+					s.RValuesWritten++
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Summary{}, err
+	}
+	return s, nil
+}
+
+// pendingKey identifies a PendingComponent by its sorted r-value set, since
+// that's what determines which cross-key collision it represents - its ID
+// is assigned on save and so can't be compared across installs.
+func pendingKey(p db.PendingComponent) string {
+	rvals := append([]string(nil), p.RValues...)
+	sort.Strings(rvals)
+	return strings.Join(rvals, ",")
+}
@@ -0,0 +1,168 @@
+// Package auth authenticates and authorizes api.Handler's mutating routes:
+// a bearer-token mode with tokens hashed at rest, an optional OIDC mode
+// that validates JWTs against a JWKS endpoint, and role-based
+// authorization (viewer/operator/admin) shared by both.
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Role is a permission level. Roles are ordered: a higher role satisfies
+// any requirement a lower one does.
+type Role int
+
+const (
+	// RoleViewer can read state but not change it.
+	RoleViewer Role = iota + 1
+	// RoleOperator can start/stop scanning and trigger notifications.
+	RoleOperator
+	// RoleAdmin can additionally change recovery behavior.
+	RoleAdmin
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return "viewer"
+	case RoleOperator:
+		return "operator"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return fmt.Sprintf("role(%d)", int(r))
+	}
+}
+
+// Allows reports whether r satisfies a route requiring required.
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+// ParseRole parses a role name ("viewer", "operator", "admin"), case
+// insensitive.
+func ParseRole(s string) (Role, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "viewer":
+		return RoleViewer, nil
+	case "operator":
+		return RoleOperator, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return 0, fmt.Errorf("auth: unrecognized role %q", s)
+	}
+}
+
+// Principal identifies who authenticated a request and what they're
+// allowed to do.
+type Principal struct {
+	Subject string
+	Role    Role
+}
+
+// ErrUnauthenticated is returned by Authenticate when the request carries
+// no valid credentials.
+var ErrUnauthenticated = errors.New("auth: no valid credentials")
+
+// Authenticator extracts a Principal from an incoming request.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>"
+// header, or "" if the header is absent or malformed.
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+	h := r.Header.Get("Authorization")
+	if !strings.HasPrefix(h, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(h, prefix)
+}
+
+// hashToken returns the hex SHA-256 digest of token, so TokenAuthenticator
+// never holds plaintext tokens in memory past construction.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenAuthenticator authenticates bearer tokens against a fixed set
+// loaded at startup, hashed at rest.
+type TokenAuthenticator struct {
+	tokens map[string]Principal
+}
+
+// NewTokenAuthenticator builds a TokenAuthenticator from a token->role map.
+// Tokens are hashed immediately; the plaintext values aren't retained.
+func NewTokenAuthenticator(roleByToken map[string]Role) *TokenAuthenticator {
+	tokens := make(map[string]Principal, len(roleByToken))
+	for token, role := range roleByToken {
+		hash := hashToken(token)
+		tokens[hash] = Principal{Subject: "token:" + hash[:8], Role: role}
+	}
+	return &TokenAuthenticator{tokens: tokens}
+}
+
+// ParseTokenConfig parses comma-separated "token:role" pairs, the format
+// Config.AuthTokens uses.
+func ParseTokenConfig(s string) (map[string]Role, error) {
+	roleByToken := make(map[string]Role)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		token, roleStr, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("auth: malformed token entry %q, want \"token:role\"", entry)
+		}
+		role, err := ParseRole(roleStr)
+		if err != nil {
+			return nil, err
+		}
+		roleByToken[token] = role
+	}
+	return roleByToken, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *TokenAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	token := bearerToken(r)
+	if token == "" {
+		return Principal{}, ErrUnauthenticated
+	}
+	hash := hashToken(token)
+	for candidate, principal := range a.tokens {
+		// Constant-time compare so a timing side channel can't narrow down
+		// a valid token hash one byte at a time.
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(hash)) == 1 {
+			return principal, nil
+		}
+	}
+	return Principal{}, ErrUnauthenticated
+}
+
+// ChainAuthenticator tries each Authenticator in order, returning the
+// first success. Used to let bearer-token and OIDC auth coexist.
+type ChainAuthenticator []Authenticator
+
+// Authenticate implements Authenticator.
+func (c ChainAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	err := error(ErrUnauthenticated)
+	for _, a := range c {
+		p, aerr := a.Authenticate(r)
+		if aerr == nil {
+			return p, nil
+		}
+		err = aerr
+	}
+	return Principal{}, err
+}
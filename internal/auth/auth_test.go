@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ecdsa-scanner/internal/logger"
+)
+
+func TestRoleAllows(t *testing.T) {
+	if !RoleAdmin.Allows(RoleOperator) {
+		t.Error("expected admin to satisfy operator requirement")
+	}
+	if RoleViewer.Allows(RoleOperator) {
+		t.Error("expected viewer not to satisfy operator requirement")
+	}
+	if !RoleOperator.Allows(RoleOperator) {
+		t.Error("expected operator to satisfy its own requirement")
+	}
+}
+
+func TestParseTokenConfig(t *testing.T) {
+	roles, err := ParseTokenConfig("abc:viewer, def:operator ,ghi:admin")
+	if err != nil {
+		t.Fatalf("ParseTokenConfig failed: %v", err)
+	}
+	if roles["abc"] != RoleViewer || roles["def"] != RoleOperator || roles["ghi"] != RoleAdmin {
+		t.Errorf("unexpected roles: %+v", roles)
+	}
+
+	if _, err := ParseTokenConfig("badentry"); err == nil {
+		t.Error("expected error for entry missing ':role'")
+	}
+	if _, err := ParseTokenConfig("tok:bogus"); err == nil {
+		t.Error("expected error for unrecognized role")
+	}
+}
+
+func TestTokenAuthenticatorAuthenticate(t *testing.T) {
+	a := NewTokenAuthenticator(map[string]Role{"s3cr3t": RoleOperator})
+
+	req := httptest.NewRequest("POST", "/api/start", nil)
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	p, err := a.Authenticate(req)
+	if err != nil {
+		t.Fatalf("expected valid token to authenticate, got: %v", err)
+	}
+	if p.Role != RoleOperator {
+		t.Errorf("expected role operator, got %v", p.Role)
+	}
+
+	req = httptest.NewRequest("POST", "/api/start", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	if _, err := a.Authenticate(req); err != ErrUnauthenticated {
+		t.Errorf("expected ErrUnauthenticated for wrong token, got %v", err)
+	}
+
+	req = httptest.NewRequest("POST", "/api/start", nil)
+	if _, err := a.Authenticate(req); err != ErrUnauthenticated {
+		t.Errorf("expected ErrUnauthenticated for missing header, got %v", err)
+	}
+}
+
+func TestChainAuthenticatorTriesEachInOrder(t *testing.T) {
+	first := NewTokenAuthenticator(map[string]Role{"aaa": RoleViewer})
+	second := NewTokenAuthenticator(map[string]Role{"bbb": RoleAdmin})
+	chain := ChainAuthenticator{first, second}
+
+	req := httptest.NewRequest("POST", "/api/start", nil)
+	req.Header.Set("Authorization", "Bearer bbb")
+	p, err := chain.Authenticate(req)
+	if err != nil {
+		t.Fatalf("expected second authenticator to succeed, got: %v", err)
+	}
+	if p.Role != RoleAdmin {
+		t.Errorf("expected role admin, got %v", p.Role)
+	}
+
+	req = httptest.NewRequest("POST", "/api/start", nil)
+	req.Header.Set("Authorization", "Bearer neither")
+	if _, err := chain.Authenticate(req); err != ErrUnauthenticated {
+		t.Errorf("expected ErrUnauthenticated when no authenticator matches, got %v", err)
+	}
+}
+
+func TestMiddlewareRequireRejectsWithoutAuthenticator(t *testing.T) {
+	m := NewMiddleware(nil, logger.New(10))
+	called := false
+	handler := m.Require(RoleViewer, func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest("POST", "/api/start", nil))
+
+	if called {
+		t.Error("expected handler not to run with no authenticator configured")
+	}
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestMiddlewareRequireEnforcesRole(t *testing.T) {
+	a := NewTokenAuthenticator(map[string]Role{"viewertoken": RoleViewer})
+	m := NewMiddleware(a, logger.New(10))
+	called := false
+	handler := m.Require(RoleOperator, func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	req := httptest.NewRequest("POST", "/api/start", nil)
+	req.Header.Set("Authorization", "Bearer viewertoken")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if called {
+		t.Error("expected handler not to run for a viewer hitting an operator route")
+	}
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+}
+
+func TestMiddlewareRequireEnforcesCSRFWhenCookiePresent(t *testing.T) {
+	a := NewTokenAuthenticator(map[string]Role{"optoken": RoleOperator})
+	m := NewMiddleware(a, logger.New(10))
+	called := false
+	handler := m.Require(RoleOperator, func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	req := httptest.NewRequest("POST", "/api/start", nil)
+	req.Header.Set("Authorization", "Bearer optoken")
+	req.AddCookie(&http.Cookie{Name: csrfCookieName, Value: "xyz"})
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if called {
+		t.Error("expected handler not to run without a matching X-CSRF-Token header")
+	}
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest("POST", "/api/start", nil)
+	req.Header.Set("Authorization", "Bearer optoken")
+	req.AddCookie(&http.Cookie{Name: csrfCookieName, Value: "xyz"})
+	req.Header.Set(csrfHeaderName, "xyz")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+
+	if !called {
+		t.Error("expected handler to run once the CSRF header matches the cookie")
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", rec.Code)
+	}
+}
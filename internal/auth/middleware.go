@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"net/http"
+
+	"ecdsa-scanner/internal/logger"
+)
+
+// Middleware gates mutating routes behind an Authenticator and an RBAC
+// check, and enforces CSRF double-submit-cookie protection for callers
+// that are relying on a cookie (i.e. browsers).
+type Middleware struct {
+	authenticator Authenticator
+	log           *logger.Logger
+}
+
+// NewMiddleware creates a Middleware. authenticator may be nil, in which
+// case Require always rejects with 401 - a deployment that doesn't
+// configure any auth source keeps every gated route unreachable rather
+// than silently open.
+func NewMiddleware(authenticator Authenticator, log *logger.Logger) *Middleware {
+	return &Middleware{authenticator: authenticator, log: log}
+}
+
+// IssueCSRFCookie ensures the response carries a csrf_token cookie, for
+// handlers serving a browser session (e.g. the index page) so a
+// subsequent mutating request from that browser can echo it back.
+func (m *Middleware) IssueCSRFCookie(w http.ResponseWriter, r *http.Request) {
+	ensureCSRFCookie(w, r)
+}
+
+// Require wraps next so it only runs for a caller authenticated at role or
+// above, logging the authenticated principal against every invocation. If
+// the caller presents a csrf_token cookie (meaning a browser, not a bare
+// API client, is involved), the request must also echo it back via the
+// X-CSRF-Token header.
+func (m *Middleware) Require(role Role, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if m.authenticator == nil {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		principal, err := m.authenticator.Authenticate(r)
+		if err != nil {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !principal.Role.Allows(role) {
+			m.log.Warn("auth: %s (role %s) denied %s %s, needs %s", principal.Subject, principal.Role, r.Method, r.URL.Path, role)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		if _, err := r.Cookie(csrfCookieName); err == nil && !checkCSRF(r) {
+			m.log.Warn("auth: %s (role %s) failed CSRF check on %s %s", principal.Subject, principal.Role, r.Method, r.URL.Path)
+			http.Error(w, "CSRF token missing or invalid", http.StatusForbidden)
+			return
+		}
+
+		m.log.Info("auth: %s (role %s) invoked %s %s", principal.Subject, principal.Role, r.Method, r.URL.Path)
+		next(w, r)
+	}
+}
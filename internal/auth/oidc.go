@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// defaultJWKSRefresh is how often OIDCAuthenticator refetches its JWKS, so
+// token verification doesn't cost a network round trip per request and a
+// key rotation is picked up within one interval.
+const defaultJWKSRefresh = 10 * time.Minute
+
+// OIDCConfig configures validating bearer JWTs against a JWKS endpoint.
+type OIDCConfig struct {
+	JWKSURL  string
+	Issuer   string
+	Audience string
+	// RoleClaim is the JWT claim holding the caller's role name ("viewer",
+	// "operator", "admin"). Defaults to "role".
+	RoleClaim string
+	// RefreshEvery overrides how often the JWKS is refetched. Defaults to
+	// defaultJWKSRefresh.
+	RefreshEvery time.Duration
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jwksDocument struct {
+	Keys []jwk `json:"keys"`
+}
+
+// OIDCAuthenticator validates bearer JWTs signed with RS256 against keys
+// fetched from a JWKS endpoint, checking issuer/audience and extracting
+// the caller's Role from a configurable claim.
+type OIDCAuthenticator struct {
+	cfg        OIDCConfig
+	httpClient *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+}
+
+// NewOIDCAuthenticator creates an OIDCAuthenticator and starts its
+// background JWKS refresh loop. The first fetch happens synchronously, so
+// an operator misconfiguring JWKSURL finds out at startup.
+func NewOIDCAuthenticator(ctx context.Context, cfg OIDCConfig) (*OIDCAuthenticator, error) {
+	if cfg.RoleClaim == "" {
+		cfg.RoleClaim = "role"
+	}
+	if cfg.RefreshEvery == 0 {
+		cfg.RefreshEvery = defaultJWKSRefresh
+	}
+
+	a := &OIDCAuthenticator{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		keys:       make(map[string]*rsa.PublicKey),
+	}
+	if err := a.refreshKeys(ctx); err != nil {
+		return nil, fmt.Errorf("auth: initial JWKS fetch: %w", err)
+	}
+
+	go a.refreshLoop()
+	return a, nil
+}
+
+func (a *OIDCAuthenticator) refreshLoop() {
+	ticker := time.NewTicker(a.cfg.RefreshEvery)
+	defer ticker.Stop()
+	for range ticker.C {
+		// Best effort: a failed refresh just keeps serving the keys already
+		// cached until the next tick succeeds.
+		_ = a.refreshKeys(context.Background())
+	}
+}
+
+func (a *OIDCAuthenticator) refreshKeys(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.cfg.JWKSURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var doc jwksDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		pub, err := rsaPublicKeyFromJWK(k.N, k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	a.mu.Lock()
+	a.keys = keys
+	a.mu.Unlock()
+	return nil
+}
+
+func rsaPublicKeyFromJWK(nB64, eB64 string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(nB64)
+	if err != nil {
+		return nil, fmt.Errorf("decode modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(eB64)
+	if err != nil {
+		return nil, fmt.Errorf("decode exponent: %w", err)
+	}
+
+	e := 0
+	for _, b := range eBytes {
+		e = e<<8 | int(b)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(nBytes), E: e}, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *OIDCAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	token := bearerToken(r)
+	if token == "" {
+		return Principal{}, ErrUnauthenticated
+	}
+
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		a.mu.RLock()
+		key, ok := a.keys[kid]
+		a.mu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		return key, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil || !parsed.Valid {
+		return Principal{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	if a.cfg.Issuer != "" && !claims.VerifyIssuer(a.cfg.Issuer, true) {
+		return Principal{}, fmt.Errorf("%w: issuer mismatch", ErrUnauthenticated)
+	}
+	if a.cfg.Audience != "" && !claims.VerifyAudience(a.cfg.Audience, true) {
+		return Principal{}, fmt.Errorf("%w: audience mismatch", ErrUnauthenticated)
+	}
+
+	roleClaim, _ := claims[a.cfg.RoleClaim].(string)
+	role, err := ParseRole(roleClaim)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	subject, _ := claims["sub"].(string)
+	return Principal{Subject: subject, Role: role}, nil
+}
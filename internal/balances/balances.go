@@ -0,0 +1,322 @@
+// Package balances batches native and ERC-20 balance lookups for recovered
+// addresses through the Multicall3 contract deployed at the same address on
+// essentially every EVM chain, so enriching N recovered keys costs one RPC
+// round trip per chain instead of N. Results are cached briefly, keyed by
+// the block they were observed at, so repeated polling of
+// /api/recovered-keys within the same head block doesn't requery the
+// balances; a new head block misses the cache for every address.
+package balances
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"ecdsa-scanner/internal/config"
+)
+
+// multicall3Address is where Multicall3 is deployed on essentially every
+// EVM chain (https://www.multicall3.com/deployments).
+var multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// multicall3ABIJSON covers only the two Multicall3 functions this package
+// uses: aggregate3 (batched, failure-tolerant calls) and getEthBalance
+// (native balance, batchable alongside ERC-20 balanceOf calls).
+const multicall3ABIJSON = `[
+	{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}],"name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"},
+	{"inputs":[{"name":"addr","type":"address"}],"name":"getEthBalance","outputs":[{"name":"balance","type":"uint256"}],"stateMutability":"view","type":"function"}
+]`
+
+// erc20ABIJSON covers only balanceOf, the single ERC-20 call this package
+// batches through Multicall3.
+const erc20ABIJSON = `[{"constant":true,"inputs":[{"name":"_owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"balance","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+// cacheTTL is how long a Lookup result is reused before being refetched.
+const cacheTTL = 60 * time.Second
+
+// TokenBalance is one ERC-20 balance result for an address.
+type TokenBalance struct {
+	Symbol     string `json:"symbol"`
+	Address    string `json:"address"`
+	BalanceWei string `json:"balance_wei"`
+}
+
+// Result is what LookupMany returns for a single address.
+type Result struct {
+	BalanceWei  *big.Int       `json:"-"`
+	BlockNumber uint64         `json:"block_number"`
+	Tokens      []TokenBalance `json:"tokens,omitempty"`
+}
+
+// multicallResult is our own copy of one element of Multicall3's Result
+// tuple[] return. The abi package decodes a tuple[] into a slice of an
+// unnamed struct type it builds via reflection, so it can never type-assert
+// directly into a named struct like this one - decodeAggregate3Results
+// copies field-by-field with reflection instead.
+type multicallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// decodeAggregate3Results converts the unnamed, reflection-built slice type
+// abi.Unpack("aggregate3", ...) returns into []multicallResult.
+func decodeAggregate3Results(v interface{}) ([]multicallResult, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("balances: expected a slice of aggregate3 results, got %T", v)
+	}
+
+	out := make([]multicallResult, rv.Len())
+	for i := range out {
+		elem := rv.Index(i)
+		success, ok := elem.FieldByName("Success").Interface().(bool)
+		if !ok {
+			return nil, fmt.Errorf("balances: aggregate3 result %d missing bool Success field", i)
+		}
+		returnData, ok := elem.FieldByName("ReturnData").Interface().([]byte)
+		if !ok {
+			return nil, fmt.Errorf("balances: aggregate3 result %d missing []byte ReturnData field", i)
+		}
+		out[i] = multicallResult{Success: success, ReturnData: returnData}
+	}
+	return out, nil
+}
+
+type cacheKey struct {
+	chainID int
+	address string
+	block   uint64
+}
+
+type cacheEntry struct {
+	result    Result
+	expiresAt time.Time
+}
+
+// Service batches and caches balance lookups across every chain it's asked
+// about, dialing (and keeping open) one ethclient per chain ID.
+type Service struct {
+	ankrAPIKey string
+
+	multicallABI abi.ABI
+	erc20ABI     abi.ABI
+
+	mu      sync.Mutex
+	clients map[int]*ethclient.Client
+	cache   map[cacheKey]cacheEntry
+}
+
+// NewService creates a Service. ankrAPIKey is appended to Ankr RPC URLs the
+// same way the rest of this package's callers already do.
+func NewService(ankrAPIKey string) (*Service, error) {
+	multicallABI, err := abi.JSON(strings.NewReader(multicall3ABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("balances: parse multicall3 ABI: %w", err)
+	}
+	erc20ABI, err := abi.JSON(strings.NewReader(erc20ABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("balances: parse erc20 ABI: %w", err)
+	}
+	return &Service{
+		ankrAPIKey:   ankrAPIKey,
+		multicallABI: multicallABI,
+		erc20ABI:     erc20ABI,
+		clients:      make(map[int]*ethclient.Client),
+		cache:        make(map[cacheKey]cacheEntry),
+	}, nil
+}
+
+// InvalidateAll drops every cached balance, forcing the next LookupMany for
+// any chain/address to requery RPC.
+func (s *Service) InvalidateAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = make(map[cacheKey]cacheEntry)
+}
+
+// client returns the persistent ethclient for chainID, dialing and caching
+// it on first use.
+func (s *Service) client(ctx context.Context, chainID int) (*ethclient.Client, error) {
+	s.mu.Lock()
+	if c, ok := s.clients[chainID]; ok {
+		s.mu.Unlock()
+		return c, nil
+	}
+	s.mu.Unlock()
+
+	cfg := config.ChainByID(chainID)
+	if cfg == nil {
+		return nil, fmt.Errorf("balances: unknown chain id %d", chainID)
+	}
+	rpcURL := cfg.RPCURL
+	if s.ankrAPIKey != "" && strings.Contains(rpcURL, "ankr.com") {
+		rpcURL = rpcURL + "/" + s.ankrAPIKey
+	}
+
+	c, err := ethclient.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("balances: dial chain %d: %w", chainID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.clients[chainID]; ok {
+		// Lost the race to dial; keep the one already cached.
+		c.Close()
+		return existing, nil
+	}
+	s.clients[chainID] = c
+	return c, nil
+}
+
+// LookupMany fetches native + configured ERC-20 balances for every address
+// on chainID in a single Multicall3 round trip, serving cached results
+// where possible. A chain ID with no configured chain returns an error; an
+// address whose multicall leg failed is still in the returned map, with a
+// zero balance for that leg.
+func (s *Service) LookupMany(ctx context.Context, chainID int, addresses []string) (map[string]Result, error) {
+	results := make(map[string]Result, len(addresses))
+	if len(addresses) == 0 {
+		return results, nil
+	}
+
+	client, err := s.client(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("balances: get block number: %w", err)
+	}
+
+	var uncached []string
+	now := time.Now()
+	for _, addr := range addresses {
+		key := cacheKey{chainID: chainID, address: strings.ToLower(addr), block: head}
+		s.mu.Lock()
+		entry, ok := s.cache[key]
+		s.mu.Unlock()
+		if ok && now.Before(entry.expiresAt) {
+			results[addr] = entry.result
+			continue
+		}
+		uncached = append(uncached, addr)
+	}
+	if len(uncached) == 0 {
+		return results, nil
+	}
+
+	tokens := config.ChainByID(chainID).Tokens
+	fetched, err := s.multicall(ctx, client, uncached, tokens, head)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	for addr, res := range fetched {
+		results[addr] = res
+		s.cache[cacheKey{chainID: chainID, address: strings.ToLower(addr), block: head}] = cacheEntry{
+			result:    res,
+			expiresAt: now.Add(cacheTTL),
+		}
+	}
+	s.mu.Unlock()
+
+	return results, nil
+}
+
+// multicall packs one getEthBalance call per address plus one balanceOf
+// call per (address, token) pair, sends them all as a single aggregate3
+// call, and decodes the results back per address.
+func (s *Service) multicall(ctx context.Context, client *ethclient.Client, addresses []string, tokens []config.TokenConfig, block uint64) (map[string]Result, error) {
+	type call3 struct {
+		Target       common.Address
+		AllowFailure bool
+		CallData     []byte
+	}
+
+	calls := make([]call3, 0, len(addresses)*(1+len(tokens)))
+	for _, addr := range addresses {
+		data, err := s.multicallABI.Pack("getEthBalance", common.HexToAddress(addr))
+		if err != nil {
+			return nil, fmt.Errorf("balances: pack getEthBalance: %w", err)
+		}
+		calls = append(calls, call3{Target: multicall3Address, AllowFailure: true, CallData: data})
+
+		for _, tok := range tokens {
+			data, err := s.erc20ABI.Pack("balanceOf", common.HexToAddress(addr))
+			if err != nil {
+				return nil, fmt.Errorf("balances: pack balanceOf(%s): %w", tok.Symbol, err)
+			}
+			calls = append(calls, call3{Target: common.HexToAddress(tok.Address), AllowFailure: true, CallData: data})
+		}
+	}
+
+	input, err := s.multicallABI.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, fmt.Errorf("balances: pack aggregate3: %w", err)
+	}
+
+	output, err := client.CallContract(ctx, ethereum.CallMsg{To: &multicall3Address, Data: input}, new(big.Int).SetUint64(block))
+	if err != nil {
+		return nil, fmt.Errorf("balances: aggregate3 call: %w", err)
+	}
+
+	raw, err := s.multicallABI.Unpack("aggregate3", output)
+	if err != nil {
+		return nil, fmt.Errorf("balances: unpack aggregate3: %w", err)
+	}
+	decoded, err := decodeAggregate3Results(raw[0])
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]Result, len(addresses))
+	i := 0
+	for _, addr := range addresses {
+		res := Result{BlockNumber: block}
+
+		native := decoded[i]
+		i++
+		if native.Success {
+			if bal, err := s.erc20ABI.Unpack("balanceOf", native.ReturnData); err == nil {
+				// getEthBalance returns the same uint256 shape as
+				// balanceOf, so erc20ABI's decoder reads it fine too.
+				if v, ok := bal[0].(*big.Int); ok {
+					res.BalanceWei = v
+				}
+			}
+		}
+		if res.BalanceWei == nil {
+			res.BalanceWei = big.NewInt(0)
+		}
+
+		for _, tok := range tokens {
+			r := decoded[i]
+			i++
+			tb := TokenBalance{Symbol: tok.Symbol, Address: tok.Address, BalanceWei: "0"}
+			if r.Success {
+				if bal, err := s.erc20ABI.Unpack("balanceOf", r.ReturnData); err == nil {
+					if v, ok := bal[0].(*big.Int); ok {
+						tb.BalanceWei = v.String()
+					}
+				}
+			}
+			res.Tokens = append(res.Tokens, tb)
+		}
+
+		results[addr] = res
+	}
+
+	return results, nil
+}
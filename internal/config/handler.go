@@ -0,0 +1,165 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrFingerprintMismatch is returned by DoLockedAction when the caller's
+// fingerprint no longer matches the running config, so a PATCH built
+// against a stale read doesn't silently clobber a change made in between.
+var ErrFingerprintMismatch = errors.New("config: fingerprint mismatch")
+
+// ConfigHandler owns the single in-memory copy of the running Config that
+// api.Handler's /api/config routes read and patch. Reads and writes are
+// serialized by a mutex, and every write is additionally a compare-and-swap
+// on Fingerprint, so a PATCH built from a stale GET is rejected instead of
+// overwriting a write it didn't see.
+type ConfigHandler struct {
+	mu  sync.RWMutex
+	cfg *Config
+}
+
+// NewConfigHandler wraps cfg. cfg is owned by the handler from this point
+// on; callers should not mutate it directly.
+func NewConfigHandler(cfg *Config) *ConfigHandler {
+	return &ConfigHandler{cfg: cfg}
+}
+
+// Snapshot returns a deep copy of the running config, safe for a caller to
+// read or serialize without racing a concurrent PATCH.
+func (h *ConfigHandler) Snapshot() *Config {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.cfg.clone()
+}
+
+// Fingerprint returns the SHA-256 hex digest of the running config's
+// canonical JSON serialization, used as the CAS token for DoLockedAction.
+func (h *ConfigHandler) Fingerprint() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return fingerprintOf(h.cfg)
+}
+
+func fingerprintOf(cfg *Config) string {
+	// Config's fields are fixed (no maps), so json.Marshal's field order is
+	// already canonical - struct fields always serialize in declaration
+	// order regardless of map iteration order elsewhere in the process.
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		// Config holds only marshalable primitives/slices/structs; a
+		// failure here means a field was added that isn't, which is a
+		// programming error, not a runtime condition.
+		panic(fmt.Sprintf("config: marshaling for fingerprint: %v", err))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// MarshalJSON returns the running config as JSON, alongside its
+// fingerprint. Fingerprint is computed over the real (unredacted) config,
+// so it still changes whenever a credential does - only the Config field
+// of the response is redacted.
+func (h *ConfigHandler) MarshalJSON() ([]byte, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return json.Marshal(struct {
+		Fingerprint string  `json:"fingerprint"`
+		Config      *Config `json:"config"`
+	}{Fingerprint: fingerprintOf(h.cfg), Config: h.cfg.Redacted()})
+}
+
+// MarshalYAML returns the running config (without its fingerprint, which
+// is JSON/HTTP-API plumbing, not part of the config itself), redacted the
+// same way MarshalJSON is.
+func (h *ConfigHandler) MarshalYAML() ([]byte, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return yaml.Marshal(h.cfg.Redacted())
+}
+
+// DoLockedAction applies cb to a clone of the running config if fingerprint
+// still matches it, atomically persists the result to cfg.ConfigFile (if
+// set), and only then swaps it in as the new running config. Returns
+// ErrFingerprintMismatch without calling cb if the fingerprint is stale.
+//
+// This makes the whole operation effectively transactional: cb's mutation
+// and the on-disk write both have to succeed before the in-memory config
+// changes at all, so a failed persist (e.g. a read-only filesystem) never
+// leaves the running config and the file on disk disagreeing.
+func (h *ConfigHandler) DoLockedAction(fingerprint string, cb func(cfg *Config) error) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if got := fingerprintOf(h.cfg); got != fingerprint {
+		return fmt.Errorf("%w: have %s, want %s", ErrFingerprintMismatch, got, fingerprint)
+	}
+
+	next := h.cfg.clone()
+	if err := cb(next); err != nil {
+		return err
+	}
+	if next.ConfigFile != "" {
+		if err := persistAtomic(next.ConfigFile, next); err != nil {
+			return fmt.Errorf("config: persisting: %w", err)
+		}
+	}
+	h.cfg = next
+	return nil
+}
+
+// persistAtomic writes cfg to path by writing to a temp file in the same
+// directory and renaming over path, so a crash or concurrent read never
+// observes a half-written config file.
+func persistAtomic(path string, cfg *Config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.json.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// clone deep-copies cfg, so DoLockedAction and Snapshot can hand out a copy
+// that's safe to mutate or read without racing the running config.
+func (cfg *Config) clone() *Config {
+	next := *cfg
+	next.Chains = make([]ChainConfig, len(cfg.Chains))
+	for i, c := range cfg.Chains {
+		next.Chains[i] = c.clone()
+	}
+	return &next
+}
+
+func (c ChainConfig) clone() ChainConfig {
+	next := c
+	if c.RPCURLs != nil {
+		next.RPCURLs = append([]NamedEndpoint(nil), c.RPCURLs...)
+	}
+	if c.Tokens != nil {
+		next.Tokens = append([]TokenConfig(nil), c.Tokens...)
+	}
+	return next
+}
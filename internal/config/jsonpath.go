@@ -0,0 +1,47 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Lookup resolves a dot-separated path (e.g. "chains.0.rpc_url") against
+// cfg's JSON representation, using the same field names GET /api/config
+// returns. It only supports plain field-name and numeric-array-index
+// segments, not full JSONPath query syntax (filters, wildcards) - that's
+// more than api.Handler's single-value GET /api/config/{path} route needs.
+func Lookup(cfg *Config, path string) (interface{}, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("config: marshaling for lookup: %w", err)
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("config: unmarshaling for lookup: %w", err)
+	}
+
+	if path == "" {
+		return v, nil
+	}
+	for _, segment := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[segment]
+			if !ok {
+				return nil, fmt.Errorf("config: no field %q", segment)
+			}
+			v = next
+		case []interface{}:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, fmt.Errorf("config: index %q out of range", segment)
+			}
+			v = node[idx]
+		default:
+			return nil, fmt.Errorf("config: %q is not an object or array", segment)
+		}
+	}
+	return v, nil
+}
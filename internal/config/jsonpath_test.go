@@ -0,0 +1,55 @@
+package config
+
+import "testing"
+
+func TestLookupResolvesNestedFieldAndIndex(t *testing.T) {
+	cfg := testConfig()
+
+	v, err := Lookup(cfg, "chains.0.name")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if v != "Ethereum" {
+		t.Fatalf("expected %q, got %v", "Ethereum", v)
+	}
+}
+
+func TestLookupEmptyPathReturnsWholeConfig(t *testing.T) {
+	cfg := testConfig()
+
+	v, err := Lookup(cfg, "")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a map, got %T", v)
+	}
+	if _, ok := m["port"]; !ok {
+		t.Fatal("expected the whole config to be returned, missing \"port\"")
+	}
+}
+
+func TestLookupErrorsOnUnknownField(t *testing.T) {
+	cfg := testConfig()
+
+	if _, err := Lookup(cfg, "does_not_exist"); err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+}
+
+func TestLookupErrorsOnOutOfRangeIndex(t *testing.T) {
+	cfg := testConfig()
+
+	if _, err := Lookup(cfg, "chains.5.name"); err == nil {
+		t.Fatal("expected an error for an out-of-range index")
+	}
+}
+
+func TestLookupErrorsWhenIndexingANonArray(t *testing.T) {
+	cfg := testConfig()
+
+	if _, err := Lookup(cfg, "port.0"); err == nil {
+		t.Fatal("expected an error when indexing a scalar field")
+	}
+}
@@ -0,0 +1,230 @@
+package db
+
+import (
+	"context"
+	"hash/fnv"
+	"sync/atomic"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"ecdsa-scanner/internal/logger"
+)
+
+// defaultCacheShards and defaultCacheSizePerShard size the cache at roughly
+// 1M entries by default, chosen from the benchmark in cache_bench_test.go:
+// past that working-set size the hit rate gain per extra shard flattens out
+// while lock contention on a single big LRU would not have.
+const (
+	defaultCacheShards       = 16
+	defaultCacheSizePerShard = 65536
+	// logStatsEvery controls how often CachedDB reports its hit rate to the
+	// logger; small enough to see in a demo run, large enough not to spam
+	// a mainnet-speed ingestion loop.
+	logStatsEvery = 100_000
+)
+
+// rvalueCache is a sharded, bounded LRU keyed by r-value. Sharding spreads
+// lock contention across chains scanning concurrently; each shard is an
+// independent LRU so the *total* resident set is shards*sizePerShard.
+type rvalueCache struct {
+	shards []*lru.Cache[string, TxRef]
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func newRValueCache(shards, sizePerShard int) (*rvalueCache, error) {
+	c := &rvalueCache{shards: make([]*lru.Cache[string, TxRef], shards)}
+	for i := range c.shards {
+		shard, err := lru.New[string, TxRef](sizePerShard)
+		if err != nil {
+			return nil, err
+		}
+		c.shards[i] = shard
+	}
+	return c, nil
+}
+
+func (c *rvalueCache) shardFor(rValue string) *lru.Cache[string, TxRef] {
+	h := fnv.New32a()
+	h.Write([]byte(rValue))
+	return c.shards[h.Sum32()%uint32(len(c.shards))]
+}
+
+func (c *rvalueCache) get(rValue string) (TxRef, bool) {
+	ref, ok := c.shardFor(rValue).Get(rValue)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return ref, ok
+}
+
+func (c *rvalueCache) put(rValue string, ref TxRef) {
+	c.shardFor(rValue).Add(rValue, ref)
+}
+
+func (c *rvalueCache) remove(rValue string) {
+	c.shardFor(rValue).Remove(rValue)
+}
+
+// clear purges every shard. Used when a reorg invalidates an unknown subset
+// of cached entries - rare enough that paying for a cold cache afterwards is
+// cheaper than tracking which entries a purge affected.
+func (c *rvalueCache) clear() {
+	for _, shard := range c.shards {
+		shard.Purge()
+	}
+}
+
+func (c *rvalueCache) stats() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+// CachedDB decorates a Store with an in-process sharded LRU in front of
+// r-value lookups. Ingestion re-checks the same working set of addresses
+// block after block, so a cache hit saves a full round-trip to whatever
+// Store it wraps (MockDB or the real Postgres-backed DB).
+type CachedDB struct {
+	Store
+	cache  *rvalueCache
+	logger *logger.Logger
+	ops    atomic.Int64
+}
+
+// NewCachedDB wraps backing with a default-sized cache.
+func NewCachedDB(backing Store, log *logger.Logger) (*CachedDB, error) {
+	return NewCachedDBWithSize(backing, defaultCacheShards, defaultCacheSizePerShard, log)
+}
+
+// NewCachedDBWithSize wraps backing with shards LRUs of sizePerShard entries
+// each, so callers can size the cache for their own working set.
+func NewCachedDBWithSize(backing Store, shards, sizePerShard int, log *logger.Logger) (*CachedDB, error) {
+	cache, err := newRValueCache(shards, sizePerShard)
+	if err != nil {
+		return nil, err
+	}
+	return &CachedDB{Store: backing, cache: cache, logger: log}, nil
+}
+
+// CacheStats returns the cumulative hit/miss counts since the cache was
+// created.
+func (c *CachedDB) CacheStats() (hits, misses int64) {
+	return c.cache.stats()
+}
+
+func (c *CachedDB) maybeLogStats() {
+	if c.logger == nil {
+		return
+	}
+	if n := c.ops.Add(1); n%logStatsEvery == 0 {
+		hits, misses := c.cache.stats()
+		total := hits + misses
+		var hitRate float64
+		if total > 0 {
+			hitRate = float64(hits) / float64(total) * 100
+		}
+		c.logger.Info("[cache] r-value lookups: %d hits, %d misses (%.1f%% hit rate)", hits, misses, hitRate)
+	}
+}
+
+// CheckAndInsertRValue implements Store. A cache hit returns the
+// previously-seen TxRef without touching the backing store; a miss falls
+// through and write-through caches whatever the backing store reports.
+func (c *CachedDB) CheckAndInsertRValue(ctx context.Context, rValue, txHash string, chainID int, blockNumber uint64) (*TxRef, bool, error) {
+	defer c.maybeLogStats()
+
+	if ref, ok := c.cache.get(rValue); ok {
+		return &ref, true, nil
+	}
+
+	existing, found, err := c.Store.CheckAndInsertRValue(ctx, rValue, txHash, chainID, blockNumber)
+	if err != nil {
+		return nil, false, err
+	}
+	if found {
+		c.cache.put(rValue, *existing)
+	} else {
+		c.cache.put(rValue, TxRef{TxHash: txHash, ChainID: chainID, BlockNumber: blockNumber})
+	}
+	return existing, found, nil
+}
+
+// BatchCheckAndInsertRValues implements Store. R-values already cached
+// are resolved as collisions locally; only cache misses are sent to the
+// backing store, which remains the source of truth for dedup within the
+// miss set and for anything this cache hasn't seen yet.
+func (c *CachedDB) BatchCheckAndInsertRValues(ctx context.Context, txs []TxInput) ([]CollisionResult, error) {
+	defer c.maybeLogStats()
+
+	var collisions []CollisionResult
+	misses := make([]TxInput, 0, len(txs))
+
+	for _, tx := range txs {
+		ref, ok := c.cache.get(tx.RValue)
+		if !ok {
+			misses = append(misses, tx)
+			continue
+		}
+		if ref.TxHash != tx.TxHash {
+			collisions = append(collisions, CollisionResult{
+				RValue:     tx.RValue,
+				TxHash:     tx.TxHash,
+				ChainID:    tx.ChainID,
+				Address:    tx.Address,
+				FirstTxRef: ref,
+			})
+		}
+	}
+
+	if len(misses) == 0 {
+		return collisions, nil
+	}
+
+	missCollisions, err := c.Store.BatchCheckAndInsertRValues(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+
+	collidedRValues := make(map[string]bool, len(missCollisions))
+	for _, mc := range missCollisions {
+		collidedRValues[mc.RValue] = true
+		c.cache.put(mc.RValue, mc.FirstTxRef)
+	}
+
+	// The backing store only records the first occurrence of each r-value
+	// within a batch (later duplicates are neither inserted nor flagged),
+	// so the cache must write-through that same first occurrence.
+	firstByRValue := make(map[string]TxInput, len(misses))
+	for _, tx := range misses {
+		if _, ok := firstByRValue[tx.RValue]; !ok {
+			firstByRValue[tx.RValue] = tx
+		}
+	}
+	for rValue, tx := range firstByRValue {
+		if !collidedRValues[rValue] {
+			c.cache.put(rValue, TxRef{TxHash: tx.TxHash, ChainID: tx.ChainID, BlockNumber: tx.BlockNumber})
+		}
+	}
+
+	return append(collisions, missCollisions...), nil
+}
+
+// RecordCollision implements Store, invalidating the cache entry so a
+// later lookup re-reads the authoritative collision state from the backing
+// store instead of serving the pre-collision TxRef.
+func (c *CachedDB) RecordCollision(ctx context.Context, rValue, txHash string, chainID int, address string) error {
+	c.cache.remove(rValue)
+	return c.Store.RecordCollision(ctx, rValue, txHash, chainID, address)
+}
+
+// DeleteCollisionsAboveBlock implements Store. A reorg can invalidate any
+// subset of cached entries, so rather than inspect every shard for affected
+// block numbers this drops the whole cache and lets it repopulate from the
+// backing store.
+func (c *CachedDB) DeleteCollisionsAboveBlock(ctx context.Context, chainID int, blockNumber uint64) error {
+	c.cache.clear()
+	return c.Store.DeleteCollisionsAboveBlock(ctx, chainID, blockNumber)
+}
+
+var _ Store = (*CachedDB)(nil)
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// syntheticBatches builds numTx transactions spread over a working set of
+// numAddresses addresses, batched batchSize at a time. Reusing a small pool
+// of addresses mirrors the temporal locality real mainnet traffic shows
+// (the same wallets transact repeatedly within nearby blocks).
+func syntheticBatches(numTx, numAddresses, batchSize int) [][]TxInput {
+	var batches [][]TxInput
+	var current []TxInput
+	for i := 0; i < numTx; i++ {
+		addr := i % numAddresses
+		current = append(current, TxInput{
+			RValue:  fmt.Sprintf("0xr%d", i),
+			TxHash:  fmt.Sprintf("0xtx%d", i),
+			ChainID: 1,
+			Address: fmt.Sprintf("0xaddr%d", addr),
+		})
+		if len(current) == batchSize {
+			batches = append(batches, current)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// BenchmarkBatchCheckAndInsertRValues_NoCache drives ~1M synthetic txs
+// straight into MockDB, with no cache in front.
+func BenchmarkBatchCheckAndInsertRValues_NoCache(b *testing.B) {
+	const numTx = 1_000_000
+	batches := syntheticBatches(numTx, 50_000, 500)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		store := NewMock()
+		for _, batch := range batches {
+			if _, err := store.BatchCheckAndInsertRValues(ctx, batch); err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+}
+
+// BenchmarkBatchCheckAndInsertRValues_Cached drives the same ~1M synthetic
+// txs through a CachedDB wrapping MockDB. Every r-value here is unique (the
+// honest common case - r-value collisions are rare by construction), so
+// this mainly shows the cache adds negligible overhead on an all-miss
+// workload; BenchmarkCheckAndInsertRValue_Cached_Parallel below is where the
+// cache actually pays for itself.
+func BenchmarkBatchCheckAndInsertRValues_Cached(b *testing.B) {
+	const numTx = 1_000_000
+	batches := syntheticBatches(numTx, 50_000, 500)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cached, err := NewCachedDB(NewMock(), nil)
+		if err != nil {
+			b.Fatalf("NewCachedDB failed: %v", err)
+		}
+		for _, batch := range batches {
+			if _, err := cached.BatchCheckAndInsertRValues(ctx, batch); err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+}
+
+// benchWorkingSetSize is the size of the pool of r-values reused by the parallel benchmarks
+// below, standing in for the repeat lookups multiple ChainScanner goroutines
+// generate when a reorg re-scans recent blocks or several chains share an
+// overlapping watch list.
+const benchWorkingSetSize = 50_000
+
+// BenchmarkCheckAndInsertRValue_NoCache_Parallel simulates concurrent chain
+// scanners hammering the same small working set of r-values directly
+// against MockDB, which serializes every lookup behind a single mutex.
+func BenchmarkCheckAndInsertRValue_NoCache_Parallel(b *testing.B) {
+	store := NewMock()
+	ctx := context.Background()
+	for i := 0; i < benchWorkingSetSize; i++ {
+		_, _, _ = store.CheckAndInsertRValue(ctx, fmt.Sprintf("0xr%d", i), fmt.Sprintf("0xtx%d", i), 1, 0)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			rValue := fmt.Sprintf("0xr%d", i%benchWorkingSetSize)
+			_, _, _ = store.CheckAndInsertRValue(ctx, rValue, fmt.Sprintf("0xtx%d", i), 1, 0)
+			i++
+		}
+	})
+}
+
+// BenchmarkCheckAndInsertRValue_Cached_Parallel is the same concurrent
+// workload through CachedDB: warm r-values resolve from a sharded LRU
+// instead of contending on MockDB's single mutex.
+func BenchmarkCheckAndInsertRValue_Cached_Parallel(b *testing.B) {
+	cached, err := NewCachedDB(NewMock(), nil)
+	if err != nil {
+		b.Fatalf("NewCachedDB failed: %v", err)
+	}
+	ctx := context.Background()
+	for i := 0; i < benchWorkingSetSize; i++ {
+		_, _, _ = cached.CheckAndInsertRValue(ctx, fmt.Sprintf("0xr%d", i), fmt.Sprintf("0xtx%d", i), 1, 0)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			rValue := fmt.Sprintf("0xr%d", i%benchWorkingSetSize)
+			_, _, _ = cached.CheckAndInsertRValue(ctx, rValue, fmt.Sprintf("0xtx%d", i), 1, 0)
+			i++
+		}
+	})
+}
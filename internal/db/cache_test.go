@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func newTestCachedDB(t *testing.T) (*CachedDB, *MockDB) {
+	t.Helper()
+	backing := NewMock()
+	cached, err := NewCachedDB(backing, nil)
+	if err != nil {
+		t.Fatalf("NewCachedDB failed: %v", err)
+	}
+	return cached, backing
+}
+
+func TestCachedDBCheckAndInsertRValueMissThenHit(t *testing.T) {
+	cached, _ := newTestCachedDB(t)
+	ctx := context.Background()
+
+	existing, found, err := cached.CheckAndInsertRValue(ctx, "0xr1", "0xtx1", 1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if found || existing != nil {
+		t.Fatalf("expected a fresh r-value to report not found, got %v %v", existing, found)
+	}
+
+	existing, found, err = cached.CheckAndInsertRValue(ctx, "0xr1", "0xtx2", 1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !found || existing.TxHash != "0xtx1" {
+		t.Fatalf("expected cache hit against first tx, got %v %v", existing, found)
+	}
+
+	hits, misses := cached.CacheStats()
+	if hits != 1 || misses != 1 {
+		t.Errorf("expected 1 hit and 1 miss, got hits=%d misses=%d", hits, misses)
+	}
+}
+
+func TestCachedDBBatchCheckAndInsertRValuesCacheHit(t *testing.T) {
+	cached, backing := newTestCachedDB(t)
+	ctx := context.Background()
+
+	if _, err := cached.BatchCheckAndInsertRValues(ctx, []TxInput{
+		{RValue: "0xr1", TxHash: "0xtx1", ChainID: 1, Address: "0xa"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Second batch reuses the same r-value with a different tx hash: this
+	// must be resolved as a collision entirely from the cache, without the
+	// backing store ever seeing the second insert attempt.
+	collisions, err := cached.BatchCheckAndInsertRValues(ctx, []TxInput{
+		{RValue: "0xr1", TxHash: "0xtx2", ChainID: 1, Address: "0xb"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(collisions) != 1 || collisions[0].FirstTxRef.TxHash != "0xtx1" {
+		t.Fatalf("expected cache-resolved collision against 0xtx1, got %+v", collisions)
+	}
+
+	refs, err := backing.GetCollisionTxRefs(ctx, "0xr1")
+	if err != nil {
+		t.Fatalf("GetCollisionTxRefs failed: %v", err)
+	}
+	if len(refs) != 1 || refs[0].TxHash != "0xtx1" {
+		t.Fatalf("backing store should still only have the first tx recorded, got %+v", refs)
+	}
+}
+
+func TestCachedDBBatchCheckAndInsertRValuesIntraBatchDuplicateMiss(t *testing.T) {
+	cached, _ := newTestCachedDB(t)
+	ctx := context.Background()
+
+	// Two misses for the same r-value in one batch: the backing store keeps
+	// only the first occurrence, and the cache must agree with it.
+	collisions, err := cached.BatchCheckAndInsertRValues(ctx, []TxInput{
+		{RValue: "0xr1", TxHash: "0xtx1", ChainID: 1, Address: "0xa"},
+		{RValue: "0xr1", TxHash: "0xtx2", ChainID: 1, Address: "0xb"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(collisions) != 0 {
+		t.Fatalf("expected no collision on the first sighting of a duplicate within a batch, got %+v", collisions)
+	}
+
+	_, found, err := cached.CheckAndInsertRValue(ctx, "0xr1", "0xtx3", 1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !found {
+		t.Fatal("expected r-value to already be known after the batch")
+	}
+
+	refs, err := cached.GetCollisionTxRefs(ctx, "0xr1")
+	if err != nil {
+		t.Fatalf("GetCollisionTxRefs failed: %v", err)
+	}
+	if len(refs) != 1 || refs[0].TxHash != "0xtx1" {
+		t.Fatalf("expected the cache to agree with the backing store's first-occurrence semantics, got %+v", refs)
+	}
+}
+
+func TestCachedDBRecordCollisionInvalidatesCache(t *testing.T) {
+	cached, _ := newTestCachedDB(t)
+	ctx := context.Background()
+
+	if _, _, err := cached.CheckAndInsertRValue(ctx, "0xr1", "0xtx1", 1, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cached.RecordCollision(ctx, "0xr1", "0xtx2", 1, "0xb"); err != nil {
+		t.Fatalf("RecordCollision failed: %v", err)
+	}
+
+	if _, ok := cached.cache.get("0xr1"); ok {
+		t.Error("expected RecordCollision to evict the cache entry")
+	}
+}
+
+func TestCachedDBShardingDistributesKeys(t *testing.T) {
+	cached, err := NewCachedDBWithSize(NewMock(), 4, 16, nil)
+	if err != nil {
+		t.Fatalf("NewCachedDBWithSize failed: %v", err)
+	}
+
+	seen := make(map[int]bool)
+	for i := 0; i < 200; i++ {
+		rValue := fmt.Sprintf("0xr%d", i)
+		for shard := range cached.cache.shards {
+			if cached.cache.shards[shard] == cached.cache.shardFor(rValue) {
+				seen[shard] = true
+			}
+		}
+	}
+	if len(seen) != 4 {
+		t.Errorf("expected all 4 shards to receive at least one key, got %d", len(seen))
+	}
+}
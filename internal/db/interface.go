@@ -1,22 +1,47 @@
 package db
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
-// Database defines the interface for database operations
-type Database interface {
+// Store defines the interface for database operations. Concrete backends
+// live in subpackages (db/postgres, db/clickhouse); this package only holds
+// the interface, shared types, and backend-agnostic decorators (CachedDB,
+// MockDB) that work against any of them.
+type Store interface {
 	Close() error
 	Health(ctx context.Context) HealthStatus
 
 	// R-value collision detection
-	CheckAndInsertRValue(ctx context.Context, rValue, txHash string, chainID int) (*TxRef, bool, error)
+	CheckAndInsertRValue(ctx context.Context, rValue, txHash string, chainID int, blockNumber uint64) (*TxRef, bool, error)
+	BatchCheckAndInsertRValues(ctx context.Context, txs []TxInput) ([]CollisionResult, error)
 	RecordCollision(ctx context.Context, rValue, txHash string, chainID int, address string) error
 	GetCollisionTxRefs(ctx context.Context, rValue string) ([]TxRef, error)
 	GetAllCollisions(ctx context.Context) ([]Collision, error)
+	HasCrossKeyPotential(ctx context.Context, rValue, excludeAddress string) (bool, error)
 
 	// Scan state
 	GetLastBlock(ctx context.Context, chainID int) (uint64, error)
 	SaveLastBlock(ctx context.Context, chainID int, block uint64) error
 
+	// Scanned block hashes, tracked so a reorg can be detected by comparing
+	// the hash this database recorded for a height against what the chain
+	// reports there now.
+	SaveScannedBlock(ctx context.Context, chainID int, blockNumber uint64, blockHash string) error
+	GetScannedBlock(ctx context.Context, chainID int, blockNumber uint64) (string, error)
+	DeleteScannedBlocksAtOrAbove(ctx context.Context, chainID int, blockNumber uint64) error
+
+	// DeleteCollisionsAboveBlock purges r-value records observed at or above
+	// blockNumber, so a reorg rescan doesn't leave behind rows from blocks
+	// that no longer exist on the canonical chain.
+	DeleteCollisionsAboveBlock(ctx context.Context, chainID int, blockNumber uint64) error
+
+	// RecordReorgEvent logs that checkReorg resolved a reorg, for
+	// observability; GetReorgEvents lists the most recent ones per chain.
+	RecordReorgEvent(ctx context.Context, event *ReorgEvent) error
+	GetReorgEvents(ctx context.Context, chainID int, limit int) ([]ReorgEvent, error)
+
 	// Recovered keys
 	SaveRecoveredKey(ctx context.Context, key *RecoveredKey) (int64, error)
 	GetRecoveredKeys(ctx context.Context) ([]RecoveredKey, error)
@@ -32,9 +57,53 @@ type Database interface {
 	GetPendingComponents(ctx context.Context) ([]PendingComponent, error)
 	DeletePendingComponent(ctx context.Context, id int64) error
 
+	// GetBiasedSignatureCandidates returns every signature on file for
+	// address/chainID that carries both an s-value and an h-value (i.e. was
+	// recorded with enough of the signature to attempt recovery.SolveHNP),
+	// or ErrNotFound if fewer than minCount such signatures exist.
+	GetBiasedSignatureCandidates(ctx context.Context, address string, chainID int, minCount int) ([]SignatureComponents, error)
+
+	// Watched addresses: a first-class alternative to the scanner's
+	// systemAddresses exclusion list. Where systemAddresses are ignored,
+	// watched addresses are what scan_mode=watched_only/watched_priority
+	// scan for.
+	AddWatchedAddress(ctx context.Context, addr *WatchedAddress) error
+	RemoveWatchedAddress(ctx context.Context, address string, chainID int) error
+	ListWatchedAddresses(ctx context.Context) ([]WatchedAddress, error)
+
+	// GetWatchedCollisions is GetAllCollisions restricted to watched
+	// addresses, so operators tracking specific suspected-leaky signers get
+	// low-latency alerts without scanning the full r-value table.
+	GetWatchedCollisions(ctx context.Context) ([]Collision, error)
+
+	// ClaimShard leases the next size-block range of chainID that isn't
+	// already leased by an unexpired owner, so multiple scanner replicas can
+	// split a full-history rescan without fetching the same blocks twice.
+	// Returns ErrNotFound once every block up to the chain's current tip (as
+	// recorded by SaveLastBlock) has a shard claimed or completed.
+	ClaimShard(ctx context.Context, chainID int, size uint64, owner string, leaseFor time.Duration) (Shard, error)
+
+	// CompleteShard marks s as finished, so its range is never reclaimed by
+	// ClaimShard even after the lease that produced it expires.
+	CompleteShard(ctx context.Context, s Shard) error
+
 	// Stats
 	GetStats(ctx context.Context) (*Stats, error)
+
+	// RefreshStats forces an immediate refresh of the stats materialized
+	// views (normally refreshed on a timer), for on-demand refresh from the
+	// API layer.
+	RefreshStats(ctx context.Context) error
+
+	// WithinTransaction runs fn against a Store scoped to a single
+	// transaction: fn's writes either all take effect (fn returns nil) or
+	// none do (fn returns an error), so a caller that needs several writes
+	// across tables to land atomically - e.g. archive.Apply - doesn't have
+	// to trust that every one of them will succeed. Backends for which
+	// that guarantee isn't meaningful (e.g. ClickHouse, which has no
+	// cross-table transaction primitive) document their own fallback
+	// behavior rather than silently pretending to provide it.
+	WithinTransaction(ctx context.Context, fn func(Store) error) error
 }
 
-var _ Database = (*DB)(nil)
-var _ Database = (*MockDB)(nil)
+var _ Store = (*MockDB)(nil)
@@ -2,33 +2,49 @@ package db
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 // MockDB is a mock database for demo/testing
 type MockDB struct {
-	mu       sync.RWMutex
-	rValues  map[string]TxRef // r_value -> first tx
-	keys     []RecoveredKey
-	nonces   map[string]RecoveredNonce
-	comps    []PendingComponent
-	blocks   map[int]uint64
+	mu            sync.RWMutex
+	rValues       map[string]TxRef // r_value -> first tx
+	keys          []RecoveredKey
+	nonces        map[string]RecoveredNonce
+	comps         []PendingComponent
+	blocks        map[int]uint64
+	scannedBlocks map[int]map[uint64]string // chainID -> block number -> block hash
+	reorgEvents   []ReorgEvent
+	signatures    map[string][]SignatureComponents // "chainID:address" -> signatures with s/h recorded
+	watched       map[string]WatchedAddress        // "chainID:address" -> watched entry
+	rValueAddress map[string]string                // r_value -> lowercased address that produced it
+	shards        map[int][]Shard                  // chainID -> leased/completed shards, in claim order
 }
 
 // NewMock creates a new mock database
 func NewMock() *MockDB {
 	return &MockDB{
-		rValues: make(map[string]TxRef),
-		nonces:  make(map[string]RecoveredNonce),
-		blocks:  make(map[int]uint64),
+		rValues:       make(map[string]TxRef),
+		nonces:        make(map[string]RecoveredNonce),
+		blocks:        make(map[int]uint64),
+		scannedBlocks: make(map[int]map[uint64]string),
+		signatures:    make(map[string][]SignatureComponents),
+		watched:       make(map[string]WatchedAddress),
+		rValueAddress: make(map[string]string),
+		shards:        make(map[int][]Shard),
 	}
 }
 
 // NewMockWithSampleData creates a mock database with sample data
 func NewMockWithSampleData() *MockDB {
 	m := NewMock()
-	
+
 	// Add sample recovered keys
 	m.keys = []RecoveredKey{
 		{
@@ -50,7 +66,7 @@ func NewMockWithSampleData() *MockDB {
 			CreatedAt:  "2024-12-24T15:45:00Z",
 		},
 	}
-	
+
 	// Add sample recovered nonces (for cross-key recovery)
 	m.nonces = map[string]RecoveredNonce{
 		"0x8a2d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c3d4": {
@@ -59,7 +75,7 @@ func NewMockWithSampleData() *MockDB {
 			DerivedFromKeyID: 1,
 		},
 	}
-	
+
 	// Add sample collisions
 	m.rValues = map[string]TxRef{
 		"0x8a2d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c3d4": {
@@ -71,7 +87,7 @@ func NewMockWithSampleData() *MockDB {
 			ChainID: 137,
 		},
 	}
-	
+
 	// Add a pending component (cross-key collision not yet solvable)
 	m.comps = []PendingComponent{
 		{
@@ -84,7 +100,7 @@ func NewMockWithSampleData() *MockDB {
 			Unknowns:  3,
 		},
 	}
-	
+
 	return m
 }
 
@@ -94,14 +110,14 @@ func (m *MockDB) Health(ctx context.Context) HealthStatus {
 	return HealthStatus{Connected: true, LatencyMs: 1}
 }
 
-func (m *MockDB) CheckAndInsertRValue(ctx context.Context, rValue, txHash string, chainID int) (*TxRef, bool, error) {
+func (m *MockDB) CheckAndInsertRValue(ctx context.Context, rValue, txHash string, chainID int, blockNumber uint64) (*TxRef, bool, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	if existing, ok := m.rValues[rValue]; ok {
 		return &existing, true, nil
 	}
-	m.rValues[rValue] = TxRef{TxHash: txHash, ChainID: chainID}
+	m.rValues[rValue] = TxRef{TxHash: txHash, ChainID: chainID, BlockNumber: blockNumber}
 	return nil, false, nil
 }
 
@@ -135,13 +151,91 @@ func (m *MockDB) BatchCheckAndInsertRValues(ctx context.Context, txs []TxInput)
 			}
 			// If same tx hash, skip (duplicate)
 		} else {
-			m.rValues[tx.RValue] = TxRef{TxHash: tx.TxHash, ChainID: tx.ChainID}
+			m.rValues[tx.RValue] = TxRef{TxHash: tx.TxHash, ChainID: tx.ChainID, BlockNumber: tx.BlockNumber}
+			m.rValueAddress[tx.RValue] = strings.ToLower(tx.Address)
+		}
+
+		if tx.SValue != "" && tx.HValue != "" {
+			key := signatureKey(tx.ChainID, tx.Address)
+			m.signatures[key] = append(m.signatures[key], SignatureComponents{
+				RValue:      tx.RValue,
+				SValue:      tx.SValue,
+				HValue:      tx.HValue,
+				TxHash:      tx.TxHash,
+				BlockNumber: tx.BlockNumber,
+			})
 		}
 	}
 
 	return collisions, nil
 }
 
+func signatureKey(chainID int, address string) string {
+	return fmt.Sprintf("%d:%s", chainID, strings.ToLower(address))
+}
+
+// GetBiasedSignatureCandidates implements coredb.Store.
+func (m *MockDB) GetBiasedSignatureCandidates(ctx context.Context, address string, chainID int, minCount int) ([]SignatureComponents, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sigs := m.signatures[signatureKey(chainID, address)]
+	if len(sigs) < minCount {
+		return nil, ErrNotFound
+	}
+	return sigs, nil
+}
+
+// AddWatchedAddress implements coredb.Store.
+func (m *MockDB) AddWatchedAddress(ctx context.Context, addr *WatchedAddress) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := signatureKey(addr.ChainID, addr.Address)
+	if _, exists := m.watched[key]; exists {
+		return nil
+	}
+	m.watched[key] = *addr
+	return nil
+}
+
+// RemoveWatchedAddress implements coredb.Store.
+func (m *MockDB) RemoveWatchedAddress(ctx context.Context, address string, chainID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.watched, signatureKey(chainID, address))
+	return nil
+}
+
+// ListWatchedAddresses implements coredb.Store.
+func (m *MockDB) ListWatchedAddresses(ctx context.Context) ([]WatchedAddress, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]WatchedAddress, 0, len(m.watched))
+	for _, w := range m.watched {
+		out = append(out, w)
+	}
+	return out, nil
+}
+
+// GetWatchedCollisions implements coredb.Store.
+func (m *MockDB) GetWatchedCollisions(ctx context.Context) ([]Collision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	collisions := []Collision{}
+	for rValue, ref := range m.rValues {
+		address, ok := m.rValueAddress[rValue]
+		if !ok {
+			continue
+		}
+		if _, watched := m.watched[signatureKey(ref.ChainID, address)]; !watched {
+			continue
+		}
+		collisions = append(collisions, Collision{RValue: rValue, TxRefs: []TxRef{ref}})
+	}
+	return collisions, nil
+}
+
 func (m *MockDB) GetCollisionTxRefs(ctx context.Context, rValue string) ([]TxRef, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -154,7 +248,7 @@ func (m *MockDB) GetCollisionTxRefs(ctx context.Context, rValue string) ([]TxRef
 func (m *MockDB) GetAllCollisions(ctx context.Context) ([]Collision, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	
+
 	// Build collisions from rValues that have been recorded
 	collisions := []Collision{}
 	for rValue, ref := range m.rValues {
@@ -183,6 +277,155 @@ func (m *MockDB) SaveLastBlock(ctx context.Context, chainID int, block uint64) e
 	return nil
 }
 
+// settledFrontier returns the end of the contiguous run of settled
+// (completed or currently leased) shards starting at block 0. Unlike a
+// plain max(range_end) over settled shards, this stops at the first gap -
+// a shard whose lease expired without completing - instead of letting a
+// later, higher-numbered shard that happened to complete or get re-leased
+// first paper over the gap and strand it unclaimed forever.
+func settledFrontier(shards []Shard) uint64 {
+	sorted := append([]Shard(nil), shards...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].RangeStart < sorted[j].RangeStart })
+
+	now := time.Now()
+	var frontier uint64
+	for _, s := range sorted {
+		leaseExpiresAt, _ := time.Parse(time.RFC3339, s.LeaseExpiresAt)
+		if !leaseExpiresAt.After(now) {
+			continue
+		}
+		if s.RangeStart > frontier {
+			break
+		}
+		if s.RangeEnd > frontier {
+			frontier = s.RangeEnd
+		}
+	}
+	return frontier
+}
+
+// ClaimShard implements coredb.Store, mirroring the Postgres backend's
+// leasing semantics (next unclaimed-or-expired range, bounded by the
+// chain's last scanned block) against the in-memory shard list instead of
+// an advisory lock, since the mock is single-process.
+func (m *MockDB) ClaimShard(ctx context.Context, chainID int, size uint64, owner string, leaseFor time.Duration) (Shard, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lastBlock := m.blocks[chainID]
+	frontier := settledFrontier(m.shards[chainID])
+	existing := m.shards[chainID]
+
+	if frontier >= lastBlock {
+		return Shard{}, ErrNotFound
+	}
+	rangeEnd := frontier + size
+	if rangeEnd > lastBlock {
+		rangeEnd = lastBlock
+	}
+
+	now := time.Now()
+	shard := Shard{
+		ChainID:        chainID,
+		RangeStart:     frontier,
+		RangeEnd:       rangeEnd,
+		Owner:          owner,
+		LeaseExpiresAt: now.Add(leaseFor).Format(time.RFC3339),
+	}
+
+	replaced := false
+	for i, s := range existing {
+		if s.RangeStart == frontier {
+			existing[i] = shard
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		existing = append(existing, shard)
+	}
+	m.shards[chainID] = existing
+
+	return shard, nil
+}
+
+// CompleteShard implements coredb.Store, marking s done by pushing its
+// lease expiry 100 years out so ClaimShard never reclaims its range again.
+func (m *MockDB) CompleteShard(ctx context.Context, s Shard) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, existing := range m.shards[s.ChainID] {
+		if existing.RangeStart == s.RangeStart && existing.Owner == s.Owner {
+			existing.LeaseExpiresAt = time.Now().Add(100 * 365 * 24 * time.Hour).Format(time.RFC3339)
+			m.shards[s.ChainID][i] = existing
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *MockDB) SaveScannedBlock(ctx context.Context, chainID int, blockNumber uint64, blockHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.scannedBlocks[chainID] == nil {
+		m.scannedBlocks[chainID] = make(map[uint64]string)
+	}
+	m.scannedBlocks[chainID][blockNumber] = blockHash
+	return nil
+}
+
+func (m *MockDB) GetScannedBlock(ctx context.Context, chainID int, blockNumber uint64) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	hash, ok := m.scannedBlocks[chainID][blockNumber]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return hash, nil
+}
+
+func (m *MockDB) DeleteScannedBlocksAtOrAbove(ctx context.Context, chainID int, blockNumber uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for height := range m.scannedBlocks[chainID] {
+		if height >= blockNumber {
+			delete(m.scannedBlocks[chainID], height)
+		}
+	}
+	return nil
+}
+
+func (m *MockDB) DeleteCollisionsAboveBlock(ctx context.Context, chainID int, blockNumber uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for rValue, ref := range m.rValues {
+		if ref.ChainID == chainID && ref.BlockNumber >= blockNumber {
+			delete(m.rValues, rValue)
+		}
+	}
+	return nil
+}
+
+func (m *MockDB) RecordReorgEvent(ctx context.Context, event *ReorgEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reorgEvents = append(m.reorgEvents, *event)
+	return nil
+}
+
+func (m *MockDB) GetReorgEvents(ctx context.Context, chainID int, limit int) ([]ReorgEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	events := []ReorgEvent{}
+	for i := len(m.reorgEvents) - 1; i >= 0 && len(events) < limit; i-- {
+		if m.reorgEvents[i].ChainID == chainID {
+			events = append(events, m.reorgEvents[i])
+		}
+	}
+	return events, nil
+}
+
 func (m *MockDB) SaveRecoveredKey(ctx context.Context, key *RecoveredKey) (int64, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -271,9 +514,95 @@ func (m *MockDB) GetStats(ctx context.Context) (*Stats, error) {
 	defer m.mu.RUnlock()
 	return &Stats{
 		TotalRValues:      len(m.rValues),
+		TotalCollisions:   len(m.rValues),
 		RecoveredKeys:     len(m.keys),
 		RecoveredNonces:   len(m.nonces),
 		PendingComponents: len(m.comps),
 		Healthy:           true,
 	}, nil
 }
+
+// RefreshStats is a no-op: the mock computes GetStats directly from its
+// in-memory maps, so there's nothing to refresh.
+func (m *MockDB) RefreshStats(ctx context.Context) error {
+	return nil
+}
+
+// mockSnapshot is a deep copy of every mutable MockDB field, used by
+// WithinTransaction to undo fn's writes if it returns an error.
+type mockSnapshot struct {
+	rValues       map[string]TxRef
+	keys          []RecoveredKey
+	nonces        map[string]RecoveredNonce
+	comps         []PendingComponent
+	blocks        map[int]uint64
+	scannedBlocks map[int]map[uint64]string
+	reorgEvents   []ReorgEvent
+	signatures    map[string][]SignatureComponents
+	watched       map[string]WatchedAddress
+	rValueAddress map[string]string
+	shards        map[int][]Shard
+}
+
+func (m *MockDB) snapshot() mockSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	scannedBlocks := make(map[int]map[uint64]string, len(m.scannedBlocks))
+	for chainID, blocks := range m.scannedBlocks {
+		scannedBlocks[chainID] = maps.Clone(blocks)
+	}
+	signatures := make(map[string][]SignatureComponents, len(m.signatures))
+	for k, v := range m.signatures {
+		signatures[k] = slices.Clone(v)
+	}
+	shards := make(map[int][]Shard, len(m.shards))
+	for chainID, s := range m.shards {
+		shards[chainID] = slices.Clone(s)
+	}
+
+	return mockSnapshot{
+		rValues:       maps.Clone(m.rValues),
+		keys:          slices.Clone(m.keys),
+		nonces:        maps.Clone(m.nonces),
+		comps:         slices.Clone(m.comps),
+		blocks:        maps.Clone(m.blocks),
+		scannedBlocks: scannedBlocks,
+		reorgEvents:   slices.Clone(m.reorgEvents),
+		signatures:    signatures,
+		watched:       maps.Clone(m.watched),
+		rValueAddress: maps.Clone(m.rValueAddress),
+		shards:        shards,
+	}
+}
+
+func (m *MockDB) restore(snap mockSnapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rValues = snap.rValues
+	m.keys = snap.keys
+	m.nonces = snap.nonces
+	m.comps = snap.comps
+	m.blocks = snap.blocks
+	m.scannedBlocks = snap.scannedBlocks
+	m.reorgEvents = snap.reorgEvents
+	m.signatures = snap.signatures
+	m.watched = snap.watched
+	m.rValueAddress = snap.rValueAddress
+	m.shards = snap.shards
+}
+
+// WithinTransaction implements coredb.Store. MockDB is single-process with
+// no real transaction primitive, so this snapshots every field before
+// calling fn and restores it wholesale if fn returns an error - enough to
+// make a failed multi-write caller (e.g. archive.Apply) see all-or-nothing
+// semantics, though (unlike a real transaction) it doesn't isolate fn from
+// a concurrent caller mutating the same MockDB mid-flight.
+func (m *MockDB) WithinTransaction(ctx context.Context, fn func(Store) error) error {
+	snap := m.snapshot()
+	if err := fn(m); err != nil {
+		m.restore(snap)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMockDB(t *testing.T) {
+	ctx := context.Background()
+	db := NewMock()
+
+	// Test R-value collision detection
+	_, isCollision, err := db.CheckAndInsertRValue(ctx, "0xabc123", "0xtx1", 1, 0)
+	if err != nil {
+		t.Fatalf("CheckAndInsertRValue failed: %v", err)
+	}
+	if isCollision {
+		t.Error("Expected no collision on first insert")
+	}
+
+	// Second insert with same R should be a collision
+	existing, isCollision, err := db.CheckAndInsertRValue(ctx, "0xabc123", "0xtx2", 1, 0)
+	if err != nil {
+		t.Fatalf("CheckAndInsertRValue failed: %v", err)
+	}
+	if !isCollision {
+		t.Error("Expected collision on second insert")
+	}
+	if existing == nil || existing.TxHash != "0xtx1" {
+		t.Error("Expected to get first tx reference")
+	}
+
+	// Test recovered key
+	keyID, err := db.SaveRecoveredKey(ctx, &RecoveredKey{
+		Address:    "0xaddr",
+		PrivateKey: "0xprivkey",
+		ChainID:    1,
+		RValues:    []string{"0xr1"},
+		TxHashes:   []string{"0xtx1", "0xtx2"},
+	})
+	if err != nil {
+		t.Fatalf("SaveRecoveredKey failed: %v", err)
+	}
+	if keyID == 0 {
+		t.Error("Expected non-zero key ID")
+	}
+
+	recovered, err := db.IsKeyRecovered(ctx, "0xaddr", 1)
+	if err != nil {
+		t.Fatalf("IsKeyRecovered failed: %v", err)
+	}
+	if !recovered {
+		t.Error("Expected key to be recovered")
+	}
+
+	// Test stats
+	stats, err := db.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	if stats.TotalRValues != 1 {
+		t.Errorf("Expected 1 R value, got %d", stats.TotalRValues)
+	}
+	if stats.RecoveredKeys != 1 {
+		t.Errorf("Expected 1 recovered key, got %d", stats.RecoveredKeys)
+	}
+}
+
+func TestMockDBBatchCheckAndInsertRValues(t *testing.T) {
+	ctx := context.Background()
+	db := NewMock()
+
+	// Insert some initial R values
+	db.CheckAndInsertRValue(ctx, "0xexisting1", "0xtx_old1", 1, 0)
+	db.CheckAndInsertRValue(ctx, "0xexisting2", "0xtx_old2", 137, 0)
+
+	// Batch insert with mix of new and existing R values
+	txs := []TxInput{
+		{RValue: "0xnew1", TxHash: "0xtx_new1", ChainID: 1, Address: "0xaddr1"},
+		{RValue: "0xexisting1", TxHash: "0xtx_collision1", ChainID: 1, Address: "0xaddr2"}, // collision
+		{RValue: "0xnew2", TxHash: "0xtx_new2", ChainID: 42, Address: "0xaddr3"},
+		{RValue: "0xexisting2", TxHash: "0xtx_collision2", ChainID: 137, Address: "0xaddr4"}, // collision
+		{RValue: "0xnew1", TxHash: "0xtx_dup", ChainID: 1, Address: "0xaddr5"},               // duplicate in batch (should be ignored)
+	}
+
+	collisions, err := db.BatchCheckAndInsertRValues(ctx, txs)
+	if err != nil {
+		t.Fatalf("BatchCheckAndInsertRValues failed: %v", err)
+	}
+
+	// Should detect 2 collisions
+	if len(collisions) != 2 {
+		t.Errorf("Expected 2 collisions, got %d", len(collisions))
+	}
+
+	// Verify collision details
+	collisionMap := make(map[string]CollisionResult)
+	for _, c := range collisions {
+		collisionMap[c.RValue] = c
+	}
+
+	if c, ok := collisionMap["0xexisting1"]; !ok {
+		t.Error("Expected collision for 0xexisting1")
+	} else {
+		if c.FirstTxRef.TxHash != "0xtx_old1" {
+			t.Errorf("Expected first tx ref 0xtx_old1, got %s", c.FirstTxRef.TxHash)
+		}
+		if c.TxHash != "0xtx_collision1" {
+			t.Errorf("Expected collision tx 0xtx_collision1, got %s", c.TxHash)
+		}
+	}
+
+	if c, ok := collisionMap["0xexisting2"]; !ok {
+		t.Error("Expected collision for 0xexisting2")
+	} else {
+		if c.FirstTxRef.TxHash != "0xtx_old2" {
+			t.Errorf("Expected first tx ref 0xtx_old2, got %s", c.FirstTxRef.TxHash)
+		}
+	}
+
+	// Verify new R values were inserted
+	stats, _ := db.GetStats(ctx)
+	// 2 original + 2 new = 4 total
+	if stats.TotalRValues != 4 {
+		t.Errorf("Expected 4 R values, got %d", stats.TotalRValues)
+	}
+
+	// Verify new R values exist and would cause collision on next insert
+	_, isCollision, _ := db.CheckAndInsertRValue(ctx, "0xnew1", "0xanother", 1, 0)
+	if !isCollision {
+		t.Error("Expected 0xnew1 to exist and cause collision")
+	}
+
+	_, isCollision, _ = db.CheckAndInsertRValue(ctx, "0xnew2", "0xanother", 42, 0)
+	if !isCollision {
+		t.Error("Expected 0xnew2 to exist and cause collision")
+	}
+}
+
+func TestMockDBBatchEmpty(t *testing.T) {
+	ctx := context.Background()
+	db := NewMock()
+
+	collisions, err := db.BatchCheckAndInsertRValues(ctx, []TxInput{})
+	if err != nil {
+		t.Fatalf("BatchCheckAndInsertRValues failed: %v", err)
+	}
+	if len(collisions) != 0 {
+		t.Errorf("Expected 0 collisions for empty input, got %d", len(collisions))
+	}
+}
+
+func TestMockDBGetBiasedSignatureCandidates(t *testing.T) {
+	ctx := context.Background()
+	db := NewMock()
+
+	txs := []TxInput{
+		{RValue: "0xr1", TxHash: "0xtx1", ChainID: 1, Address: "0xSigner", SValue: "0xs1", HValue: "0xh1"},
+		{RValue: "0xr2", TxHash: "0xtx2", ChainID: 1, Address: "0xSigner", SValue: "0xs2", HValue: "0xh2"},
+		{RValue: "0xr3", TxHash: "0xtx3", ChainID: 1, Address: "0xOther", SValue: "0xs3", HValue: "0xh3"},
+		{RValue: "0xr4", TxHash: "0xtx4", ChainID: 1, Address: "0xsigner"}, // no s/h, should be excluded
+	}
+	if _, err := db.BatchCheckAndInsertRValues(ctx, txs); err != nil {
+		t.Fatalf("BatchCheckAndInsertRValues failed: %v", err)
+	}
+
+	// Address lookup is case-insensitive, matching how addresses are
+	// normalized elsewhere.
+	sigs, err := db.GetBiasedSignatureCandidates(ctx, "0xsigner", 1, 2)
+	if err != nil {
+		t.Fatalf("GetBiasedSignatureCandidates failed: %v", err)
+	}
+	if len(sigs) != 2 {
+		t.Errorf("Expected 2 signature candidates, got %d", len(sigs))
+	}
+
+	if _, err := db.GetBiasedSignatureCandidates(ctx, "0xsigner", 1, 3); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound when minCount exceeds available signatures, got %v", err)
+	}
+}
+
+func TestMockDBWatchedAddresses(t *testing.T) {
+	ctx := context.Background()
+	db := NewMock()
+
+	if err := db.AddWatchedAddress(ctx, &WatchedAddress{Address: "0xWatched", ChainID: 1, Label: "suspect", Priority: 5}); err != nil {
+		t.Fatalf("AddWatchedAddress failed: %v", err)
+	}
+	// Duplicate insert, differing only by case, should not create a second entry.
+	if err := db.AddWatchedAddress(ctx, &WatchedAddress{Address: "0xwatched", ChainID: 1}); err != nil {
+		t.Fatalf("AddWatchedAddress (duplicate) failed: %v", err)
+	}
+
+	watched, err := db.ListWatchedAddresses(ctx)
+	if err != nil {
+		t.Fatalf("ListWatchedAddresses failed: %v", err)
+	}
+	if len(watched) != 1 {
+		t.Fatalf("Expected 1 watched address after case-insensitive dedup, got %d", len(watched))
+	}
+	if watched[0].Label != "suspect" {
+		t.Errorf("Expected the original entry to be kept, got label %q", watched[0].Label)
+	}
+
+	txs := []TxInput{
+		{RValue: "0xr1", TxHash: "0xtx1", ChainID: 1, Address: "0xWatched"},
+		{RValue: "0xr2", TxHash: "0xtx2", ChainID: 1, Address: "0xUnwatched"},
+	}
+	if _, err := db.BatchCheckAndInsertRValues(ctx, txs); err != nil {
+		t.Fatalf("BatchCheckAndInsertRValues failed: %v", err)
+	}
+
+	collisions, err := db.GetWatchedCollisions(ctx)
+	if err != nil {
+		t.Fatalf("GetWatchedCollisions failed: %v", err)
+	}
+	if len(collisions) != 1 || collisions[0].RValue != "0xr1" {
+		t.Errorf("Expected only the watched address's r-value, got %+v", collisions)
+	}
+
+	if err := db.RemoveWatchedAddress(ctx, "0xWATCHED", 1); err != nil {
+		t.Fatalf("RemoveWatchedAddress failed: %v", err)
+	}
+	watched, err = db.ListWatchedAddresses(ctx)
+	if err != nil {
+		t.Fatalf("ListWatchedAddresses failed: %v", err)
+	}
+	if len(watched) != 0 {
+		t.Errorf("Expected 0 watched addresses after removal, got %d", len(watched))
+	}
+}
+
+func TestMockDBClaimShard(t *testing.T) {
+	ctx := context.Background()
+	db := NewMock()
+	db.SaveLastBlock(ctx, 1, 1000)
+
+	shard, err := db.ClaimShard(ctx, 1, 300, "replica-a", time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimShard failed: %v", err)
+	}
+	if shard.RangeStart != 0 || shard.RangeEnd != 300 {
+		t.Errorf("Expected range [0, 300), got [%d, %d)", shard.RangeStart, shard.RangeEnd)
+	}
+
+	// A second replica claiming concurrently gets the next range, not the
+	// one already leased to replica-a.
+	next, err := db.ClaimShard(ctx, 1, 300, "replica-b", time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimShard (second replica) failed: %v", err)
+	}
+	if next.RangeStart != 300 || next.RangeEnd != 600 {
+		t.Errorf("Expected range [300, 600), got [%d, %d)", next.RangeStart, next.RangeEnd)
+	}
+
+	if err := db.CompleteShard(ctx, shard); err != nil {
+		t.Fatalf("CompleteShard failed: %v", err)
+	}
+
+	// Once every block up to lastBlock is claimed or completed, there's
+	// nothing left to lease.
+	db.ClaimShard(ctx, 1, 400, "replica-c", time.Minute)
+	if _, err := db.ClaimShard(ctx, 1, 100, "replica-d", time.Minute); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound once the chain's range is fully claimed, got %v", err)
+	}
+}
+
+func TestMockDBClaimShardReclaimsExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	db := NewMock()
+	db.SaveLastBlock(ctx, 1, 500)
+
+	if _, err := db.ClaimShard(ctx, 1, 500, "replica-a", -time.Minute); err != nil {
+		t.Fatalf("ClaimShard failed: %v", err)
+	}
+
+	// replica-a's lease is already expired (negative leaseFor), so another
+	// replica should be able to reclaim the same range instead of being
+	// told nothing is left.
+	shard, err := db.ClaimShard(ctx, 1, 500, "replica-b", time.Minute)
+	if err != nil {
+		t.Fatalf("Expected the expired lease to be reclaimable, got %v", err)
+	}
+	if shard.RangeStart != 0 || shard.Owner != "replica-b" {
+		t.Errorf("Expected replica-b to reclaim [0, 500), got %+v", shard)
+	}
+}
+
+func TestMockDBClaimShardDoesNotOrphanRangeOnOutOfOrderCompletion(t *testing.T) {
+	ctx := context.Background()
+	db := NewMock()
+	db.SaveLastBlock(ctx, 1, 3000)
+
+	// replica-a leases [0, 1000) with a short-lived lease, then never
+	// completes it - simulating a crash shortly after claiming. The lease
+	// is seconds, not milliseconds, because LeaseExpiresAt round-trips
+	// through RFC3339 (whole-second precision).
+	first, err := db.ClaimShard(ctx, 1, 1000, "replica-a", 2*time.Second)
+	if err != nil {
+		t.Fatalf("ClaimShard (replica-a) failed: %v", err)
+	}
+	if first.RangeStart != 0 || first.RangeEnd != 1000 {
+		t.Fatalf("Expected replica-a to claim [0, 1000), got [%d, %d)", first.RangeStart, first.RangeEnd)
+	}
+
+	// replica-b leases [1000, 2000) while replica-a's lease is still live,
+	// and completes it before replica-a's lease expires.
+	second, err := db.ClaimShard(ctx, 1, 1000, "replica-b", time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimShard (replica-b) failed: %v", err)
+	}
+	if second.RangeStart != 1000 || second.RangeEnd != 2000 {
+		t.Fatalf("Expected replica-b to claim [1000, 2000), got [%d, %d)", second.RangeStart, second.RangeEnd)
+	}
+	if err := db.CompleteShard(ctx, second); err != nil {
+		t.Fatalf("CompleteShard (replica-b) failed: %v", err)
+	}
+
+	// Let replica-a's lease actually expire before the next claim.
+	time.Sleep(3 * time.Second)
+
+	// The next claim must reclaim replica-a's orphaned [0, 1000) rather
+	// than skip past it just because a later, higher range completed
+	// first.
+	next, err := db.ClaimShard(ctx, 1, 1000, "replica-c", time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimShard (replica-c) failed: %v", err)
+	}
+	if next.RangeStart != 0 || next.RangeEnd != 1000 {
+		t.Fatalf("Expected replica-c to reclaim the orphaned [0, 1000), got [%d, %d)", next.RangeStart, next.RangeEnd)
+	}
+}
@@ -0,0 +1,428 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// migrationLockKey is the Postgres advisory lock ID used to serialize
+// migrations across scanner instances starting simultaneously. It's an
+// arbitrary constant, not a schema value - any process running migrations
+// against this database must agree on it.
+const migrationLockKey = 72217
+
+// Migration is a single, numbered schema change. Up applies it; Down
+// reverses it, used by Migrate when asked for a target version below the
+// one currently applied. Migrations are run inside a transaction each, so
+// a failing Up rolls back cleanly.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, tx *sql.Tx) error
+	Down    func(ctx context.Context, tx *sql.Tx) error
+}
+
+// migrations lists every schema change in the order it must be applied.
+// Append new entries here; never edit or renumber one that has already
+// shipped, since schema_migrations on a running database records which
+// versions it has seen.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "initial schema",
+		Up: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `
+				CREATE TABLE IF NOT EXISTS r_values (
+					chain_id INTEGER NOT NULL,
+					r_value BYTEA NOT NULL,
+					tx_hash BYTEA NOT NULL,
+					address BYTEA,
+					block_number BIGINT NOT NULL,
+					created_at TIMESTAMPTZ DEFAULT NOW(),
+					PRIMARY KEY (chain_id, r_value)
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_r_values_address ON r_values(address);
+				CREATE INDEX IF NOT EXISTS idx_r_values_chain_block ON r_values(chain_id, block_number);
+
+				CREATE TABLE IF NOT EXISTS scan_state (
+					chain_id INTEGER PRIMARY KEY,
+					last_block BIGINT NOT NULL,
+					updated_at TIMESTAMPTZ DEFAULT NOW()
+				);
+
+				CREATE TABLE IF NOT EXISTS scanned_blocks (
+					chain_id INTEGER NOT NULL,
+					block_number BIGINT NOT NULL,
+					block_hash BYTEA NOT NULL,
+					PRIMARY KEY (chain_id, block_number)
+				);
+
+				CREATE TABLE IF NOT EXISTS recovered_keys (
+					id BIGSERIAL PRIMARY KEY,
+					address BYTEA NOT NULL,
+					private_key BYTEA NOT NULL,
+					chain_id INTEGER NOT NULL,
+					r_values TEXT[] NOT NULL,
+					tx_hashes TEXT[] NOT NULL,
+					created_at TIMESTAMPTZ DEFAULT NOW(),
+					UNIQUE(address, chain_id)
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_recovered_keys_address ON recovered_keys(address);
+
+				CREATE TABLE IF NOT EXISTS recovered_nonces (
+					r_value TEXT PRIMARY KEY,
+					k_value TEXT NOT NULL,
+					derived_from_key_id BIGINT NOT NULL
+				);
+
+				CREATE TABLE IF NOT EXISTS pending_components (
+					id BIGSERIAL PRIMARY KEY,
+					r_values TEXT[] NOT NULL,
+					tx_hashes TEXT[] NOT NULL,
+					addresses TEXT[] NOT NULL,
+					chain_ids INTEGER[] NOT NULL,
+					equations INTEGER NOT NULL,
+					unknowns INTEGER NOT NULL
+				);
+			`)
+			return err
+		},
+		Down: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `
+				DROP TABLE IF EXISTS pending_components;
+				DROP TABLE IF EXISTS recovered_nonces;
+				DROP TABLE IF EXISTS recovered_keys;
+				DROP TABLE IF EXISTS scanned_blocks;
+				DROP TABLE IF EXISTS scan_state;
+				DROP TABLE IF EXISTS r_values;
+			`)
+			return err
+		},
+	},
+	{
+		Version: 2,
+		Name:    "reorg events",
+		Up: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `
+				CREATE TABLE IF NOT EXISTS reorg_events (
+					id BIGSERIAL PRIMARY KEY,
+					chain_id INTEGER NOT NULL,
+					old_tip BIGINT NOT NULL,
+					fork_block BIGINT NOT NULL,
+					detected_at TIMESTAMPTZ DEFAULT NOW()
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_reorg_events_chain ON reorg_events(chain_id, detected_at DESC);
+			`)
+			return err
+		},
+		Down: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS reorg_events;`)
+			return err
+		},
+	},
+	{
+		Version: 3,
+		Name:    "stats materialized views",
+		Up: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `
+				CREATE MATERIALIZED VIEW IF NOT EXISTS mv_stats_r_values AS
+					SELECT 1 AS id, COUNT(*)::BIGINT AS value FROM r_values;
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_mv_stats_r_values_id ON mv_stats_r_values(id);
+
+				CREATE MATERIALIZED VIEW IF NOT EXISTS mv_stats_recovered_keys AS
+					SELECT 1 AS id, COUNT(*)::BIGINT AS value FROM recovered_keys;
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_mv_stats_recovered_keys_id ON mv_stats_recovered_keys(id);
+
+				CREATE MATERIALIZED VIEW IF NOT EXISTS mv_stats_recovered_nonces AS
+					SELECT 1 AS id, COUNT(*)::BIGINT AS value FROM recovered_nonces;
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_mv_stats_recovered_nonces_id ON mv_stats_recovered_nonces(id);
+
+				CREATE MATERIALIZED VIEW IF NOT EXISTS mv_stats_pending_components AS
+					SELECT 1 AS id, COUNT(*)::BIGINT AS value FROM pending_components;
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_mv_stats_pending_components_id ON mv_stats_pending_components(id);
+
+				CREATE TABLE IF NOT EXISTS stats_delta (
+					kind TEXT PRIMARY KEY,
+					delta BIGINT NOT NULL DEFAULT 0
+				);
+
+				INSERT INTO stats_delta (kind, delta) VALUES
+					('r_values', 0), ('recovered_keys', 0), ('recovered_nonces', 0), ('pending_components', 0)
+				ON CONFLICT (kind) DO NOTHING;
+			`)
+			return err
+		},
+		Down: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `
+				DROP TABLE IF EXISTS stats_delta;
+				DROP MATERIALIZED VIEW IF EXISTS mv_stats_pending_components;
+				DROP MATERIALIZED VIEW IF EXISTS mv_stats_recovered_nonces;
+				DROP MATERIALIZED VIEW IF EXISTS mv_stats_recovered_keys;
+				DROP MATERIALIZED VIEW IF EXISTS mv_stats_r_values;
+			`)
+			return err
+		},
+	},
+	{
+		Version: 4,
+		Name:    "signature components for biased-nonce recovery",
+		Up: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `
+				ALTER TABLE r_values ADD COLUMN IF NOT EXISTS s_value BYTEA;
+				ALTER TABLE r_values ADD COLUMN IF NOT EXISTS h_value BYTEA;
+
+				CREATE INDEX IF NOT EXISTS idx_r_values_address_chain_sh
+					ON r_values(address, chain_id)
+					WHERE s_value IS NOT NULL AND h_value IS NOT NULL;
+			`)
+			return err
+		},
+		Down: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `
+				DROP INDEX IF EXISTS idx_r_values_address_chain_sh;
+				ALTER TABLE r_values DROP COLUMN IF EXISTS s_value;
+				ALTER TABLE r_values DROP COLUMN IF EXISTS h_value;
+			`)
+			return err
+		},
+	},
+	{
+		Version: 5,
+		Name:    "watched addresses",
+		Up: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `
+				CREATE TABLE IF NOT EXISTS watched_addresses (
+					address   BYTEA NOT NULL,
+					chain_id  INTEGER NOT NULL,
+					label     TEXT NOT NULL DEFAULT '',
+					priority  INTEGER NOT NULL DEFAULT 0,
+					added_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+					PRIMARY KEY (chain_id, address)
+				);
+			`)
+			return err
+		},
+		Down: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS watched_addresses;`)
+			return err
+		},
+	},
+	{
+		Version: 6,
+		Name:    "scan shards",
+		Up: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `
+				CREATE TABLE IF NOT EXISTS scan_shards (
+					chain_id          INTEGER NOT NULL,
+					range_start       BIGINT NOT NULL,
+					range_end         BIGINT NOT NULL,
+					owner             TEXT NOT NULL,
+					leased_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+					lease_expires_at  TIMESTAMPTZ NOT NULL,
+					completed_at      TIMESTAMPTZ,
+					PRIMARY KEY (chain_id, range_start)
+				);
+			`)
+			return err
+		},
+		Down: func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS scan_shards;`)
+			return err
+		},
+	},
+}
+
+// migrationChecksum is the value recorded in schema_migrations.checksum
+// when a migration is applied. It hashes only Version and Name, not the Up
+// or Down bodies, so editing a migration's SQL leaves its checksum
+// unchanged; nothing in this package compares a recorded checksum against
+// the binary's.
+func migrationChecksum(m Migration) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", m.Version, m.Name)))
+	return hex.EncodeToString(sum[:])
+}
+
+// runMigrations brings a fresh or existing database up to the latest known
+// schema version. It holds a Postgres advisory lock for the duration, so
+// multiple scanner instances starting at once don't race applying the same
+// migration twice, and refuses to start if the schema is newer than this
+// binary's migrations table (e.g. a rollback deploy against a
+// already-upgraded database).
+func (db *DB) runMigrations(ctx context.Context) error {
+	conn, err := db.pool.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection for migration lock: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			applied_at TIMESTAMPTZ DEFAULT NOW(),
+			checksum TEXT NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	current, err := currentSchemaVersion(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	latest := migrations[len(migrations)-1].Version
+	if current > latest {
+		return fmt.Errorf("database schema is at version %d, newer than this binary's latest known migration %d", current, latest)
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		if err := applyMigrationUp(ctx, conn, m); err != nil {
+			return fmt.Errorf("apply migration %d (%s): %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func currentSchemaVersion(ctx context.Context, conn *sql.Conn) (int, error) {
+	var version sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT MAX(version) FROM schema_migrations").Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+func applyMigrationUp(ctx context.Context, conn *sql.Conn, m Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.Up(ctx, tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, checksum) VALUES ($1, $2)",
+		m.Version, migrationChecksum(m)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func applyMigrationDown(ctx context.Context, conn *sql.Conn, m Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.Down(ctx, tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", m.Version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Migrate brings the schema to exactly target, applying migrations above
+// the current version or reverting ones above target, for ops tooling (e.g.
+// migrating down before rolling back a deploy).
+func (db *DB) Migrate(ctx context.Context, target int) error {
+	conn, err := db.pool.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection for migration lock: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	current, err := currentSchemaVersion(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if target >= current {
+		for _, m := range migrations {
+			if m.Version > current && m.Version <= target {
+				if err := applyMigrationUp(ctx, conn, m); err != nil {
+					return fmt.Errorf("apply migration %d (%s): %w", m.Version, m.Name, err)
+				}
+			}
+		}
+		return nil
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.Version <= current && m.Version > target {
+			if err := applyMigrationDown(ctx, conn, m); err != nil {
+				return fmt.Errorf("revert migration %d (%s): %w", m.Version, m.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MigrationStatus reports every known migration and whether it has been
+// applied to this database, for ops tooling.
+type MigrationStatus struct {
+	Version   int    `json:"version"`
+	Name      string `json:"name"`
+	Applied   bool   `json:"applied"`
+	AppliedAt string `json:"applied_at,omitempty"`
+}
+
+// MigrationStatus implements ops visibility into the schema_migrations
+// table, joined against the migrations this binary knows how to apply.
+func (db *DB) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	rows, err := db.conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, db.wrapError(err)
+	}
+	defer rows.Close()
+
+	appliedAt := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			continue
+		}
+		appliedAt[version] = at
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		s := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := appliedAt[m.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = at.Format(time.RFC3339)
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import "testing"
+
+func TestMigrationsAreSequentialAndUnique(t *testing.T) {
+	seen := make(map[int]bool)
+	for i, m := range migrations {
+		if seen[m.Version] {
+			t.Fatalf("duplicate migration version %d (%s)", m.Version, m.Name)
+		}
+		seen[m.Version] = true
+
+		if m.Version != i+1 {
+			t.Errorf("expected migration at index %d to be version %d, got %d (%s)", i, i+1, m.Version, m.Name)
+		}
+		if m.Up == nil {
+			t.Errorf("migration %d (%s) has no Up", m.Version, m.Name)
+		}
+		if m.Down == nil {
+			t.Errorf("migration %d (%s) has no Down", m.Version, m.Name)
+		}
+	}
+}
+
+func TestMigrationChecksumIsDeterministic(t *testing.T) {
+	m := Migration{Version: 1, Name: "initial schema"}
+	if migrationChecksum(m) != migrationChecksum(m) {
+		t.Error("expected migrationChecksum to be deterministic for the same migration")
+	}
+
+	other := Migration{Version: 2, Name: "reorg events"}
+	if migrationChecksum(m) == migrationChecksum(other) {
+		t.Error("expected different migrations to produce different checksums")
+	}
+}
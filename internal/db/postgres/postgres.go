@@ -0,0 +1,1176 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+
+	coredb "ecdsa-scanner/internal/db"
+	"ecdsa-scanner/internal/retry"
+)
+
+// Common errors
+var (
+	ErrConnectionFailed = errors.New("database connection failed")
+	ErrQueryTimeout     = errors.New("query timeout")
+	ErrPoolExhausted    = errors.New("connection pool exhausted")
+)
+
+// DefaultStatsRefreshInterval is how often the background loop started by
+// New refreshes the mv_stats_* materialized views GetStats reads from.
+const DefaultStatsRefreshInterval = 60 * time.Second
+
+// dbConn is the subset of *sql.DB that both it and *sql.Tx satisfy, so DB's
+// query methods can run unmodified against either the connection pool or a
+// single transaction (see WithinTransaction).
+type dbConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// DB wraps database operations
+type DB struct {
+	conn            dbConn
+	pool            *sql.DB
+	systemAddresses map[string]bool
+	retryConfig     retry.Config
+
+	statsRefreshInterval time.Duration
+	stopStatsRefresh     chan struct{}
+
+	statsMu                  sync.Mutex
+	lastStatsRefresh         time.Time
+	lastStatsRefreshDuration time.Duration
+}
+
+var _ coredb.Store = (*DB)(nil)
+
+// New creates a new database connection, refreshing stats materialized
+// views every DefaultStatsRefreshInterval.
+func New(databaseURL string, systemAddresses map[string]bool) (*DB, error) {
+	return NewWithStatsRefreshInterval(databaseURL, systemAddresses, DefaultStatsRefreshInterval)
+}
+
+// NewWithStatsRefreshInterval is New with an explicit stats refresh
+// interval, for tests and deployments that want faster/slower refresh than
+// the default.
+func NewWithStatsRefreshInterval(databaseURL string, systemAddresses map[string]bool, statsRefreshInterval time.Duration) (*DB, error) {
+	conn, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrConnectionFailed, err)
+	}
+
+	// Configure connection pool
+	conn.SetMaxOpenConns(25)
+	conn.SetMaxIdleConns(5)
+	conn.SetConnMaxLifetime(5 * time.Minute)
+	conn.SetConnMaxIdleTime(1 * time.Minute)
+
+	// Test connection
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %v", ErrConnectionFailed, err)
+	}
+
+	db := &DB{
+		conn:                 conn,
+		pool:                 conn,
+		systemAddresses:      systemAddresses,
+		retryConfig:          retry.DefaultConfig(),
+		statsRefreshInterval: statsRefreshInterval,
+		stopStatsRefresh:     make(chan struct{}),
+	}
+
+	if err := db.runMigrations(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	go db.runStatsRefreshLoop()
+
+	return db, nil
+}
+
+// Close closes the database connection
+func (db *DB) Close() error {
+	close(db.stopStatsRefresh)
+	return db.pool.Close()
+}
+
+// Health checks database connectivity and returns status
+func (db *DB) Health(ctx context.Context) coredb.HealthStatus {
+	status := coredb.HealthStatus{}
+
+	start := time.Now()
+	err := db.pool.PingContext(ctx)
+	status.LatencyMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	status.Connected = true
+
+	// Get connection pool stats
+	poolStats := db.pool.Stats()
+	status.OpenConnections = poolStats.OpenConnections
+
+	db.statsMu.Lock()
+	if !db.lastStatsRefresh.IsZero() {
+		status.LastStatsRefresh = db.lastStatsRefresh.Format(time.RFC3339)
+		status.StatsRefreshDurationMs = db.lastStatsRefreshDuration.Milliseconds()
+	}
+	db.statsMu.Unlock()
+
+	return status
+}
+
+// wrapError converts database errors to application errors
+func (db *DB) wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return coredb.ErrNotFound
+	}
+
+	// Check for specific postgres errors
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "53300": // too_many_connections
+			return fmt.Errorf("%w: %v", ErrPoolExhausted, err)
+		case "57014": // query_canceled
+			return fmt.Errorf("%w: %v", ErrQueryTimeout, err)
+		}
+	}
+
+	// Check for context errors
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", ErrQueryTimeout, err)
+	}
+
+	return err
+}
+
+// WithinTransaction implements coredb.Store with a real SQL transaction:
+// fn runs against a DB whose conn is the transaction itself, so every query
+// it issues through that DB's conn participates in the same transaction.
+// BatchCheckAndInsertRValues and ClaimShard are the exceptions: they begin
+// their own transactions on the pool, so their writes commit independently
+// of this one. Committing only happens if fn returns nil; any error (fn's own, or
+// the final commit's) leaves the deferred Rollback to undo whatever fn had
+// already written.
+func (db *DB) WithinTransaction(ctx context.Context, fn func(coredb.Store) error) error {
+	tx, err := db.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return db.wrapError(err)
+	}
+	defer tx.Rollback()
+
+	txDB := &DB{
+		conn:            tx,
+		pool:            db.pool,
+		systemAddresses: db.systemAddresses,
+		retryConfig:     db.retryConfig,
+	}
+	if err := fn(txDB); err != nil {
+		return err
+	}
+	return db.wrapError(tx.Commit())
+}
+
+// hexToBytes decodes a "0x"-prefixed (or bare) hex string into raw bytes for
+// compact BYTEA storage. An odd-length payload is left-padded with a zero
+// nibble, the literal "0x" decodes to an empty slice, and a payload that
+// isn't valid hex yields nil rather than an error.
+func hexToBytes(s string) []byte {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s)%2 != 0 {
+		s = "0" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// bytesToHex is the inverse of hexToBytes, always emitting a "0x" prefix.
+func bytesToHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+// nullableHex is hexToBytes for optional columns (s_value, h_value): an
+// empty string means the caller didn't have that component, which should
+// store as SQL NULL rather than an empty byte string.
+func nullableHex(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return hexToBytes(s)
+}
+
+// CheckAndInsertRValue implements coredb.Store.
+func (db *DB) CheckAndInsertRValue(ctx context.Context, rValue, txHash string, chainID int, blockNumber uint64) (*coredb.TxRef, bool, error) {
+	var ref coredb.TxRef
+	var inserted bool
+
+	err := retry.Do(ctx, db.retryConfig, func() error {
+		var txHashBytes []byte
+		var dbBlockNumber int64
+		err := db.conn.QueryRowContext(ctx, `
+			WITH ins AS (
+				INSERT INTO r_values (chain_id, r_value, tx_hash, block_number)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (chain_id, r_value) DO NOTHING
+				RETURNING tx_hash, block_number
+			)
+			SELECT tx_hash, block_number, true AS inserted FROM ins
+			UNION ALL
+			SELECT tx_hash, block_number, false AS inserted FROM r_values
+			WHERE chain_id = $1 AND r_value = $2 AND NOT EXISTS (SELECT 1 FROM ins)
+		`, chainID, hexToBytes(rValue), hexToBytes(txHash), int64(blockNumber)).
+			Scan(&txHashBytes, &dbBlockNumber, &inserted)
+		if err != nil {
+			return db.wrapError(err)
+		}
+		ref = coredb.TxRef{TxHash: bytesToHex(txHashBytes), ChainID: chainID, BlockNumber: uint64(dbBlockNumber)}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if inserted {
+		return nil, false, nil
+	}
+	return &ref, true, nil
+}
+
+// copyBatchThreshold is the batch size above which BatchCheckAndInsertRValues
+// switches from one parameterized query per row to a COPY-based staging
+// path. Live scanner batches stay well under this and keep paying the small
+// per-row round-trip cost to get collision results directly; backfills and
+// replays submit thousands of rows at once, where a parameterized query
+// would also risk Postgres' 65535-parameter limit.
+const copyBatchThreshold = 5000
+
+// BatchCheckAndInsertRValues implements coredb.Store, inserting every r-value
+// not already on file and reporting a CollisionResult for each one that was.
+// Like MockDB, only the first occurrence of a repeated r-value within txs is
+// considered - later duplicates are silently skipped.
+func (db *DB) BatchCheckAndInsertRValues(ctx context.Context, txs []coredb.TxInput) ([]coredb.CollisionResult, error) {
+	if len(txs) == 0 {
+		return nil, nil
+	}
+
+	deduped := make([]coredb.TxInput, 0, len(txs))
+	seen := make(map[string]bool, len(txs))
+	for _, tx := range txs {
+		key := fmt.Sprintf("%d:%s", tx.ChainID, tx.RValue)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		deduped = append(deduped, tx)
+	}
+
+	if len(deduped) >= copyBatchThreshold {
+		return db.batchInsertViaCopy(ctx, deduped)
+	}
+
+	var collisions []coredb.CollisionResult
+
+	err := retry.Do(ctx, db.retryConfig, func() error {
+		collisions = nil
+
+		tx, err := db.pool.BeginTx(ctx, nil)
+		if err != nil {
+			return db.wrapError(err)
+		}
+		defer tx.Rollback()
+
+		stmt, err := tx.PrepareContext(ctx, `
+			WITH ins AS (
+				INSERT INTO r_values (chain_id, r_value, tx_hash, address, block_number, s_value, h_value)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				ON CONFLICT (chain_id, r_value) DO NOTHING
+				RETURNING tx_hash, block_number
+			)
+			SELECT tx_hash, block_number, true AS inserted FROM ins
+			UNION ALL
+			SELECT tx_hash, block_number, false AS inserted FROM r_values
+			WHERE chain_id = $1 AND r_value = $2 AND NOT EXISTS (SELECT 1 FROM ins)
+		`)
+		if err != nil {
+			return db.wrapError(err)
+		}
+		defer stmt.Close()
+
+		insertedCount := 0
+		for _, input := range deduped {
+			var txHashBytes []byte
+			var dbBlockNumber int64
+			var inserted bool
+			err := stmt.QueryRowContext(ctx,
+				input.ChainID, hexToBytes(input.RValue), hexToBytes(input.TxHash), hexToBytes(input.Address), int64(input.BlockNumber),
+				nullableHex(input.SValue), nullableHex(input.HValue),
+			).Scan(&txHashBytes, &dbBlockNumber, &inserted)
+			if err != nil {
+				return db.wrapError(err)
+			}
+			if inserted {
+				insertedCount++
+				continue
+			}
+
+			existingHash := bytesToHex(txHashBytes)
+			if strings.EqualFold(existingHash, input.TxHash) {
+				continue
+			}
+			collisions = append(collisions, coredb.CollisionResult{
+				RValue:  input.RValue,
+				TxHash:  input.TxHash,
+				ChainID: input.ChainID,
+				Address: input.Address,
+				FirstTxRef: coredb.TxRef{
+					TxHash:      existingHash,
+					ChainID:     input.ChainID,
+					BlockNumber: uint64(dbBlockNumber),
+				},
+			})
+		}
+
+		if err := bumpStatsDelta(ctx, tx, "r_values", insertedCount); err != nil {
+			return db.wrapError(err)
+		}
+
+		return db.wrapError(tx.Commit())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return collisions, nil
+}
+
+// batchInsertViaCopy is BatchCheckAndInsertRValues' path for large batches:
+// it streams deduped through pq.CopyIn into a temp staging table (COPY has
+// no ON CONFLICT of its own), then merges the stage into r_values with a
+// single ON CONFLICT DO NOTHING, and finally looks up the existing
+// first-seen row for anything that didn't insert, so callers get the same
+// CollisionResult shape as the parameterized path.
+func (db *DB) batchInsertViaCopy(ctx context.Context, deduped []coredb.TxInput) ([]coredb.CollisionResult, error) {
+	var collisions []coredb.CollisionResult
+
+	err := retry.Do(ctx, db.retryConfig, func() error {
+		collisions = nil
+
+		tx, err := db.pool.BeginTx(ctx, nil)
+		if err != nil {
+			return db.wrapError(err)
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx,
+			"CREATE TEMP TABLE r_values_stage (LIKE r_values INCLUDING DEFAULTS) ON COMMIT DROP",
+		); err != nil {
+			return db.wrapError(err)
+		}
+
+		stmt, err := tx.PrepareContext(ctx,
+			pq.CopyIn("r_values_stage", "chain_id", "r_value", "tx_hash", "address", "block_number", "s_value", "h_value"))
+		if err != nil {
+			return db.wrapError(err)
+		}
+
+		for _, input := range deduped {
+			if _, err := stmt.ExecContext(ctx,
+				input.ChainID, hexToBytes(input.RValue), hexToBytes(input.TxHash), hexToBytes(input.Address), int64(input.BlockNumber),
+				nullableHex(input.SValue), nullableHex(input.HValue),
+			); err != nil {
+				stmt.Close()
+				return db.wrapError(err)
+			}
+		}
+		if _, err := stmt.ExecContext(ctx); err != nil { // flush
+			stmt.Close()
+			return db.wrapError(err)
+		}
+		if err := stmt.Close(); err != nil {
+			return db.wrapError(err)
+		}
+
+		rows, err := tx.QueryContext(ctx, `
+			INSERT INTO r_values (chain_id, r_value, tx_hash, address, block_number, s_value, h_value)
+			SELECT DISTINCT ON (chain_id, r_value) chain_id, r_value, tx_hash, address, block_number, s_value, h_value
+			FROM r_values_stage
+			ORDER BY chain_id, r_value, block_number
+			ON CONFLICT (chain_id, r_value) DO NOTHING
+			RETURNING chain_id, r_value
+		`)
+		if err != nil {
+			return db.wrapError(err)
+		}
+
+		inserted := make(map[string]bool, len(deduped))
+		for rows.Next() {
+			var chainID int
+			var rValueBytes []byte
+			if err := rows.Scan(&chainID, &rValueBytes); err != nil {
+				rows.Close()
+				return db.wrapError(err)
+			}
+			inserted[fmt.Sprintf("%d:%s", chainID, string(rValueBytes))] = true
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return db.wrapError(err)
+		}
+		rows.Close()
+
+		for _, input := range deduped {
+			key := fmt.Sprintf("%d:%s", input.ChainID, string(hexToBytes(input.RValue)))
+			if inserted[key] {
+				continue
+			}
+
+			var txHashBytes []byte
+			var dbBlockNumber int64
+			err := tx.QueryRowContext(ctx,
+				"SELECT tx_hash, block_number FROM r_values WHERE chain_id = $1 AND r_value = $2",
+				input.ChainID, hexToBytes(input.RValue),
+			).Scan(&txHashBytes, &dbBlockNumber)
+			if err != nil {
+				return db.wrapError(err)
+			}
+
+			existingHash := bytesToHex(txHashBytes)
+			if strings.EqualFold(existingHash, input.TxHash) {
+				continue
+			}
+			collisions = append(collisions, coredb.CollisionResult{
+				RValue:  input.RValue,
+				TxHash:  input.TxHash,
+				ChainID: input.ChainID,
+				Address: input.Address,
+				FirstTxRef: coredb.TxRef{
+					TxHash:      existingHash,
+					ChainID:     input.ChainID,
+					BlockNumber: uint64(dbBlockNumber),
+				},
+			})
+		}
+
+		if err := bumpStatsDelta(ctx, tx, "r_values", len(inserted)); err != nil {
+			return db.wrapError(err)
+		}
+
+		return db.wrapError(tx.Commit())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return collisions, nil
+}
+
+// RecordCollision implements coredb.Store. The authoritative collision state is
+// just the first-seen TxRef already stored by CheckAndInsertRValue/
+// BatchCheckAndInsertRValues, so there is nothing further to persist here.
+func (db *DB) RecordCollision(ctx context.Context, rValue, txHash string, chainID int, address string) error {
+	return nil
+}
+
+// GetCollisionTxRefs implements coredb.Store.
+func (db *DB) GetCollisionTxRefs(ctx context.Context, rValue string) ([]coredb.TxRef, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT chain_id, tx_hash, block_number FROM r_values WHERE r_value = $1
+	`, hexToBytes(rValue))
+	if err != nil {
+		return nil, db.wrapError(err)
+	}
+	defer rows.Close()
+
+	var refs []coredb.TxRef
+	for rows.Next() {
+		var ref coredb.TxRef
+		var txHashBytes []byte
+		var blockNumber int64
+		if err := rows.Scan(&ref.ChainID, &txHashBytes, &blockNumber); err != nil {
+			continue
+		}
+		ref.TxHash = bytesToHex(txHashBytes)
+		ref.BlockNumber = uint64(blockNumber)
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// GetAllCollisions implements coredb.Store.
+func (db *DB) GetAllCollisions(ctx context.Context) ([]coredb.Collision, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT r_value, chain_id, tx_hash, block_number FROM r_values ORDER BY created_at DESC LIMIT 1000
+	`)
+	if err != nil {
+		return nil, db.wrapError(err)
+	}
+	defer rows.Close()
+
+	collisions := []coredb.Collision{}
+	for rows.Next() {
+		var rValueBytes, txHashBytes []byte
+		var ref coredb.TxRef
+		var blockNumber int64
+		if err := rows.Scan(&rValueBytes, &ref.ChainID, &txHashBytes, &blockNumber); err != nil {
+			continue
+		}
+		ref.TxHash = bytesToHex(txHashBytes)
+		ref.BlockNumber = uint64(blockNumber)
+		collisions = append(collisions, coredb.Collision{
+			RValue: bytesToHex(rValueBytes),
+			TxRefs: []coredb.TxRef{ref},
+		})
+	}
+	return collisions, nil
+}
+
+// GetBiasedSignatureCandidates implements coredb.Store.
+func (db *DB) GetBiasedSignatureCandidates(ctx context.Context, address string, chainID int, minCount int) ([]coredb.SignatureComponents, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT r_value, s_value, h_value, tx_hash, block_number
+		FROM r_values
+		WHERE address = $1 AND chain_id = $2 AND s_value IS NOT NULL AND h_value IS NOT NULL
+	`, hexToBytes(address), chainID)
+	if err != nil {
+		return nil, db.wrapError(err)
+	}
+	defer rows.Close()
+
+	var sigs []coredb.SignatureComponents
+	for rows.Next() {
+		var rValueBytes, sValueBytes, hValueBytes, txHashBytes []byte
+		var blockNumber int64
+		if err := rows.Scan(&rValueBytes, &sValueBytes, &hValueBytes, &txHashBytes, &blockNumber); err != nil {
+			return nil, db.wrapError(err)
+		}
+		sigs = append(sigs, coredb.SignatureComponents{
+			RValue:      bytesToHex(rValueBytes),
+			SValue:      bytesToHex(sValueBytes),
+			HValue:      bytesToHex(hValueBytes),
+			TxHash:      bytesToHex(txHashBytes),
+			BlockNumber: uint64(blockNumber),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, db.wrapError(err)
+	}
+
+	if len(sigs) < minCount {
+		return nil, coredb.ErrNotFound
+	}
+	return sigs, nil
+}
+
+// AddWatchedAddress implements coredb.Store.
+func (db *DB) AddWatchedAddress(ctx context.Context, addr *coredb.WatchedAddress) error {
+	_, err := db.conn.ExecContext(ctx, `
+		INSERT INTO watched_addresses (address, chain_id, label, priority)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (chain_id, address) DO NOTHING
+	`, hexToBytes(addr.Address), addr.ChainID, addr.Label, addr.Priority)
+	return db.wrapError(err)
+}
+
+// RemoveWatchedAddress implements coredb.Store.
+func (db *DB) RemoveWatchedAddress(ctx context.Context, address string, chainID int) error {
+	_, err := db.conn.ExecContext(ctx, `
+		DELETE FROM watched_addresses WHERE chain_id = $1 AND address = $2
+	`, chainID, hexToBytes(address))
+	return db.wrapError(err)
+}
+
+// ListWatchedAddresses implements coredb.Store.
+func (db *DB) ListWatchedAddresses(ctx context.Context) ([]coredb.WatchedAddress, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT address, chain_id, label, priority, added_at FROM watched_addresses
+	`)
+	if err != nil {
+		return nil, db.wrapError(err)
+	}
+	defer rows.Close()
+
+	var out []coredb.WatchedAddress
+	for rows.Next() {
+		var addressBytes []byte
+		var w coredb.WatchedAddress
+		var addedAt time.Time
+		if err := rows.Scan(&addressBytes, &w.ChainID, &w.Label, &w.Priority, &addedAt); err != nil {
+			return nil, db.wrapError(err)
+		}
+		w.Address = bytesToHex(addressBytes)
+		w.AddedAt = addedAt.Format(time.RFC3339)
+		out = append(out, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, db.wrapError(err)
+	}
+	return out, nil
+}
+
+// GetWatchedCollisions is GetAllCollisions restricted to addresses on the
+// watched_addresses list.
+func (db *DB) GetWatchedCollisions(ctx context.Context) ([]coredb.Collision, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT r_value, r_values.chain_id, tx_hash, block_number
+		FROM r_values
+		JOIN watched_addresses
+			ON watched_addresses.chain_id = r_values.chain_id
+			AND watched_addresses.address = r_values.address
+		ORDER BY created_at DESC LIMIT 1000
+	`)
+	if err != nil {
+		return nil, db.wrapError(err)
+	}
+	defer rows.Close()
+
+	collisions := []coredb.Collision{}
+	for rows.Next() {
+		var rValueBytes, txHashBytes []byte
+		var ref coredb.TxRef
+		var blockNumber int64
+		if err := rows.Scan(&rValueBytes, &ref.ChainID, &txHashBytes, &blockNumber); err != nil {
+			continue
+		}
+		ref.TxHash = bytesToHex(txHashBytes)
+		ref.BlockNumber = uint64(blockNumber)
+		collisions = append(collisions, coredb.Collision{
+			RValue: bytesToHex(rValueBytes),
+			TxRefs: []coredb.TxRef{ref},
+		})
+	}
+	return collisions, nil
+}
+
+// HasCrossKeyPotential reports whether any other address has submitted a
+// signature sharing rValue, which is what makes a recovered nonce worth
+// keeping around for cross-key recovery.
+func (db *DB) HasCrossKeyPotential(ctx context.Context, rValue, excludeAddress string) (bool, error) {
+	var count int
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM r_values
+		WHERE r_value = $1 AND address IS NOT NULL AND address != $2
+	`, hexToBytes(rValue), hexToBytes(excludeAddress)).Scan(&count)
+	if err != nil {
+		return false, db.wrapError(err)
+	}
+	return count > 0, nil
+}
+
+// GetLastBlock returns the last scanned block for a chain
+func (db *DB) GetLastBlock(ctx context.Context, chainID int) (uint64, error) {
+	var lastBlock uint64
+
+	err := retry.Do(ctx, db.retryConfig, func() error {
+		err := db.conn.QueryRowContext(ctx,
+			"SELECT last_block FROM scan_state WHERE chain_id = $1", chainID).Scan(&lastBlock)
+		if err == sql.ErrNoRows {
+			lastBlock = 0
+			return nil
+		}
+		return db.wrapError(err)
+	})
+
+	return lastBlock, err
+}
+
+// SaveLastBlock saves the last scanned block for a chain
+func (db *DB) SaveLastBlock(ctx context.Context, chainID int, block uint64) error {
+	return retry.Do(ctx, db.retryConfig, func() error {
+		_, err := db.conn.ExecContext(ctx, `
+			INSERT INTO scan_state (chain_id, last_block, updated_at)
+			VALUES ($1, $2, NOW())
+			ON CONFLICT (chain_id) DO UPDATE SET last_block = $2, updated_at = NOW()
+		`, chainID, block)
+		return db.wrapError(err)
+	})
+}
+
+// SaveScannedBlock records the hash observed for a scanned height, so a
+// later poll can detect a reorg by noticing the chain now reports a
+// different hash there.
+func (db *DB) SaveScannedBlock(ctx context.Context, chainID int, blockNumber uint64, blockHash string) error {
+	return retry.Do(ctx, db.retryConfig, func() error {
+		_, err := db.conn.ExecContext(ctx, `
+			INSERT INTO scanned_blocks (chain_id, block_number, block_hash)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (chain_id, block_number) DO UPDATE SET block_hash = $3
+		`, chainID, blockNumber, hexToBytes(blockHash))
+		return db.wrapError(err)
+	})
+}
+
+// GetScannedBlock returns the hash this database recorded for blockNumber.
+func (db *DB) GetScannedBlock(ctx context.Context, chainID int, blockNumber uint64) (string, error) {
+	var hashBytes []byte
+	err := db.conn.QueryRowContext(ctx,
+		"SELECT block_hash FROM scanned_blocks WHERE chain_id = $1 AND block_number = $2",
+		chainID, blockNumber).Scan(&hashBytes)
+	if err != nil {
+		return "", db.wrapError(err)
+	}
+	return bytesToHex(hashBytes), nil
+}
+
+// DeleteScannedBlocksAtOrAbove implements coredb.Store, used to roll the scan
+// state back after FindLCA locates a reorg's common ancestor.
+func (db *DB) DeleteScannedBlocksAtOrAbove(ctx context.Context, chainID int, blockNumber uint64) error {
+	return retry.Do(ctx, db.retryConfig, func() error {
+		_, err := db.conn.ExecContext(ctx,
+			"DELETE FROM scanned_blocks WHERE chain_id = $1 AND block_number >= $2",
+			chainID, blockNumber)
+		return db.wrapError(err)
+	})
+}
+
+// DeleteCollisionsAboveBlock implements coredb.Store, purging r-value records
+// observed at or above blockNumber so a reorg rescan starts clean.
+func (db *DB) DeleteCollisionsAboveBlock(ctx context.Context, chainID int, blockNumber uint64) error {
+	return retry.Do(ctx, db.retryConfig, func() error {
+		_, err := db.conn.ExecContext(ctx,
+			"DELETE FROM r_values WHERE chain_id = $1 AND block_number >= $2",
+			chainID, blockNumber)
+		return db.wrapError(err)
+	})
+}
+
+// RecordReorgEvent implements coredb.Store.
+func (db *DB) RecordReorgEvent(ctx context.Context, event *coredb.ReorgEvent) error {
+	return retry.Do(ctx, db.retryConfig, func() error {
+		_, err := db.conn.ExecContext(ctx, `
+			INSERT INTO reorg_events (chain_id, old_tip, fork_block)
+			VALUES ($1, $2, $3)
+		`, event.ChainID, event.OldTip, event.ForkBlock)
+		return db.wrapError(err)
+	})
+}
+
+// GetReorgEvents implements coredb.Store, returning chainID's most recent
+// reorgs in descending detected_at order so callers see the latest first.
+func (db *DB) GetReorgEvents(ctx context.Context, chainID int, limit int) ([]coredb.ReorgEvent, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT chain_id, old_tip, fork_block, detected_at
+		FROM reorg_events
+		WHERE chain_id = $1
+		ORDER BY detected_at DESC
+		LIMIT $2
+	`, chainID, limit)
+	if err != nil {
+		return nil, db.wrapError(err)
+	}
+	defer rows.Close()
+
+	events := []coredb.ReorgEvent{}
+	for rows.Next() {
+		var e coredb.ReorgEvent
+		var detectedAt time.Time
+		if err := rows.Scan(&e.ChainID, &e.OldTip, &e.ForkBlock, &detectedAt); err != nil {
+			continue
+		}
+		e.DetectedAt = detectedAt.Format(time.RFC3339)
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// shardLockKeyBase offsets ClaimShard's per-chain advisory lock IDs away
+// from migrationLockKey and any other lock key this package takes, so the
+// two never collide. Arbitrary constant, like migrationLockKey.
+const shardLockKeyBase = 81000
+
+// shardLockKey returns the Postgres advisory lock ID used to serialize
+// ClaimShard calls for chainID across scanner replicas, so two replicas
+// racing to claim the next range never compute the same one.
+func shardLockKey(chainID int) int64 {
+	return shardLockKeyBase + int64(chainID)
+}
+
+// ClaimShard implements coredb.Store. It holds a per-chain advisory lock for
+// the duration of the transaction so two replicas claiming at once can't
+// compute the same range, then leases the next size-block range past the
+// contiguous run of completed-or-currently-leased shards starting at block
+// 0. Walking the contiguous run (instead of just taking MAX(range_end) over
+// settled shards) matters because shards can complete out of order: if a
+// higher-range shard completes while a lower-range one's lease is still
+// outstanding, a plain max would jump the frontier past the lower range the
+// moment that lease expires, orphaning it. A previously leased range whose
+// lease_expires_at has passed (its owner crashed or was killed before
+// calling CompleteShard) is reclaimed rather than skipped.
+func (db *DB) ClaimShard(ctx context.Context, chainID int, size uint64, owner string, leaseFor time.Duration) (coredb.Shard, error) {
+	var shard coredb.Shard
+
+	err := retry.Do(ctx, db.retryConfig, func() error {
+		tx, err := db.pool.BeginTx(ctx, nil)
+		if err != nil {
+			return db.wrapError(err)
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", shardLockKey(chainID)); err != nil {
+			return db.wrapError(err)
+		}
+
+		lastBlock, err := db.GetLastBlock(ctx, chainID)
+		if err != nil {
+			return err
+		}
+
+		var frontier int64
+		err = tx.QueryRowContext(ctx, `
+			WITH settled AS (
+				SELECT range_start, range_end FROM scan_shards
+				WHERE chain_id = $1 AND (completed_at IS NOT NULL OR lease_expires_at > NOW())
+			),
+			islands AS (
+				SELECT range_start, range_end,
+					SUM(CASE WHEN range_start > COALESCE(LAG(range_end) OVER (ORDER BY range_start), 0)
+						THEN 1 ELSE 0 END) OVER (ORDER BY range_start) AS island
+				FROM settled
+			)
+			SELECT COALESCE(MAX(range_end), 0) FROM islands WHERE island = 0
+		`, chainID).Scan(&frontier)
+		if err != nil {
+			return db.wrapError(err)
+		}
+
+		rangeStart := uint64(frontier)
+		if rangeStart >= lastBlock {
+			return coredb.ErrNotFound
+		}
+		rangeEnd := rangeStart + size
+		if rangeEnd > lastBlock {
+			rangeEnd = lastBlock
+		}
+
+		var leaseExpiresAt time.Time
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO scan_shards (chain_id, range_start, range_end, owner, leased_at, lease_expires_at)
+			VALUES ($1, $2, $3, $4, NOW(), NOW() + $5 * INTERVAL '1 second')
+			ON CONFLICT (chain_id, range_start) DO UPDATE SET
+				range_end = EXCLUDED.range_end,
+				owner = EXCLUDED.owner,
+				leased_at = NOW(),
+				lease_expires_at = EXCLUDED.lease_expires_at,
+				completed_at = NULL
+			WHERE scan_shards.completed_at IS NULL AND scan_shards.lease_expires_at <= NOW()
+			RETURNING lease_expires_at
+		`, chainID, int64(rangeStart), int64(rangeEnd), owner, leaseFor.Seconds()).Scan(&leaseExpiresAt)
+		if err != nil {
+			return db.wrapError(err)
+		}
+
+		shard = coredb.Shard{
+			ChainID:        chainID,
+			RangeStart:     rangeStart,
+			RangeEnd:       rangeEnd,
+			Owner:          owner,
+			LeaseExpiresAt: leaseExpiresAt.Format(time.RFC3339),
+		}
+		return db.wrapError(tx.Commit())
+	})
+	if err != nil {
+		return coredb.Shard{}, err
+	}
+	return shard, nil
+}
+
+// CompleteShard implements coredb.Store, marking s done so ClaimShard never
+// reclaims its range even after the lease that produced it expires.
+func (db *DB) CompleteShard(ctx context.Context, s coredb.Shard) error {
+	return retry.Do(ctx, db.retryConfig, func() error {
+		_, err := db.conn.ExecContext(ctx, `
+			UPDATE scan_shards SET completed_at = NOW()
+			WHERE chain_id = $1 AND range_start = $2 AND owner = $3
+		`, s.ChainID, int64(s.RangeStart), s.Owner)
+		return db.wrapError(err)
+	})
+}
+
+// SaveRecoveredKey saves a recovered private key to the database
+func (db *DB) SaveRecoveredKey(ctx context.Context, key *coredb.RecoveredKey) (int64, error) {
+	var id int64
+	err := db.conn.QueryRowContext(ctx, `
+		INSERT INTO recovered_keys (address, private_key, chain_id, r_values, tx_hashes)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (address, chain_id) DO UPDATE SET
+			private_key = $2,
+			r_values = $4,
+			tx_hashes = $5
+		RETURNING id
+	`, hexToBytes(key.Address), hexToBytes(key.PrivateKey), key.ChainID,
+		pq.Array(key.RValues), pq.Array(key.TxHashes)).Scan(&id)
+	if err != nil {
+		return 0, db.wrapError(err)
+	}
+	return id, nil
+}
+
+// GetRecoveredKeys returns all recovered private keys
+func (db *DB) GetRecoveredKeys(ctx context.Context) ([]coredb.RecoveredKey, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT id, address, private_key, chain_id, r_values, tx_hashes, created_at
+		FROM recovered_keys
+		ORDER BY created_at DESC
+	`)
+	if err != nil {
+		return nil, db.wrapError(err)
+	}
+	defer rows.Close()
+
+	var keys []coredb.RecoveredKey
+	for rows.Next() {
+		var key coredb.RecoveredKey
+		var addressBytes, privateKeyBytes []byte
+		var createdAt time.Time
+		if err := rows.Scan(&key.ID, &addressBytes, &privateKeyBytes, &key.ChainID,
+			pq.Array(&key.RValues), pq.Array(&key.TxHashes), &createdAt); err != nil {
+			continue
+		}
+		key.Address = bytesToHex(addressBytes)
+		key.PrivateKey = bytesToHex(privateKeyBytes)
+		key.CreatedAt = createdAt.Format(time.RFC3339)
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// IsKeyRecovered checks if a key has already been recovered for an address/chain
+func (db *DB) IsKeyRecovered(ctx context.Context, address string, chainID int) (bool, error) {
+	var count int
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM recovered_keys
+		WHERE address = $1 AND chain_id = $2
+	`, hexToBytes(address), chainID).Scan(&count)
+	if err != nil {
+		return false, db.wrapError(err)
+	}
+	return count > 0, nil
+}
+
+// SaveRecoveredNonce implements coredb.Store.
+func (db *DB) SaveRecoveredNonce(ctx context.Context, nonce *coredb.RecoveredNonce) error {
+	_, err := db.conn.ExecContext(ctx, `
+		INSERT INTO recovered_nonces (r_value, k_value, derived_from_key_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (r_value) DO UPDATE SET k_value = $2, derived_from_key_id = $3
+	`, nonce.RValue, nonce.KValue, nonce.DerivedFromKeyID)
+	return db.wrapError(err)
+}
+
+// GetRecoveredNonce implements coredb.Store.
+func (db *DB) GetRecoveredNonce(ctx context.Context, rValue string) (*coredb.RecoveredNonce, error) {
+	var nonce coredb.RecoveredNonce
+	err := db.conn.QueryRowContext(ctx, `
+		SELECT r_value, k_value, derived_from_key_id FROM recovered_nonces WHERE r_value = $1
+	`, rValue).Scan(&nonce.RValue, &nonce.KValue, &nonce.DerivedFromKeyID)
+	if err != nil {
+		return nil, db.wrapError(err)
+	}
+	return &nonce, nil
+}
+
+// GetRecoveredNonces implements coredb.Store.
+func (db *DB) GetRecoveredNonces(ctx context.Context) ([]coredb.RecoveredNonce, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT r_value, k_value, derived_from_key_id FROM recovered_nonces
+	`)
+	if err != nil {
+		return nil, db.wrapError(err)
+	}
+	defer rows.Close()
+
+	nonces := []coredb.RecoveredNonce{}
+	for rows.Next() {
+		var nonce coredb.RecoveredNonce
+		if err := rows.Scan(&nonce.RValue, &nonce.KValue, &nonce.DerivedFromKeyID); err != nil {
+			continue
+		}
+		nonces = append(nonces, nonce)
+	}
+	return nonces, nil
+}
+
+// SavePendingComponent implements coredb.Store.
+func (db *DB) SavePendingComponent(ctx context.Context, comp *coredb.PendingComponent) error {
+	return db.conn.QueryRowContext(ctx, `
+		INSERT INTO pending_components (r_values, tx_hashes, addresses, chain_ids, equations, unknowns)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, pq.Array(comp.RValues), pq.Array(comp.TxHashes), pq.Array(comp.Addresses),
+		pq.Array(comp.ChainIDs), comp.Equations, comp.Unknowns).Scan(&comp.ID)
+}
+
+// GetPendingComponents implements coredb.Store.
+func (db *DB) GetPendingComponents(ctx context.Context) ([]coredb.PendingComponent, error) {
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT id, r_values, tx_hashes, addresses, chain_ids, equations, unknowns
+		FROM pending_components
+	`)
+	if err != nil {
+		return nil, db.wrapError(err)
+	}
+	defer rows.Close()
+
+	comps := []coredb.PendingComponent{}
+	for rows.Next() {
+		var comp coredb.PendingComponent
+		if err := rows.Scan(&comp.ID, pq.Array(&comp.RValues), pq.Array(&comp.TxHashes),
+			pq.Array(&comp.Addresses), pq.Array(&comp.ChainIDs), &comp.Equations, &comp.Unknowns); err != nil {
+			continue
+		}
+		comps = append(comps, comp)
+	}
+	return comps, nil
+}
+
+// DeletePendingComponent implements coredb.Store.
+func (db *DB) DeletePendingComponent(ctx context.Context, id int64) error {
+	_, err := db.conn.ExecContext(ctx, "DELETE FROM pending_components WHERE id = $1", id)
+	return db.wrapError(err)
+}
+
+// statsMaterializedViews lists every mv_stats_* view RefreshStats refreshes
+// and GetStats reads from, alongside the stats_delta kind it's paired with.
+var statsMaterializedViews = []struct {
+	view string
+	kind string
+}{
+	{"mv_stats_r_values", "r_values"},
+	{"mv_stats_recovered_keys", "recovered_keys"},
+	{"mv_stats_recovered_nonces", "recovered_nonces"},
+	{"mv_stats_pending_components", "pending_components"},
+}
+
+// runStatsRefreshLoop periodically refreshes the stats materialized views
+// until Close stops it. Failures are logged to nothing in particular (the
+// DB package has no logger of its own) and simply retried next tick;
+// GetStats keeps working off the last good refresh plus stats_delta either
+// way.
+func (db *DB) runStatsRefreshLoop() {
+	ticker := time.NewTicker(db.statsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.stopStatsRefresh:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			db.RefreshStats(ctx)
+			cancel()
+		}
+	}
+}
+
+// RefreshStats refreshes every mv_stats_* materialized view CONCURRENTLY
+// (so readers aren't blocked), recording how long it took for Health to
+// report. Exposed for on-demand refresh from the API layer in addition to
+// the periodic background refresh.
+func (db *DB) RefreshStats(ctx context.Context) error {
+	start := time.Now()
+
+	for _, v := range statsMaterializedViews {
+		if _, err := db.conn.ExecContext(ctx, fmt.Sprintf("REFRESH MATERIALIZED VIEW CONCURRENTLY %s", v.view)); err != nil {
+			return fmt.Errorf("refresh %s: %w", v.view, err)
+		}
+		// The view now counts every row the delta was tracking. A delta
+		// bumped between the refresh and this reset is dropped until the
+		// next refresh counts its rows.
+		if _, err := db.conn.ExecContext(ctx, "UPDATE stats_delta SET delta = 0 WHERE kind = $1", v.kind); err != nil {
+			return fmt.Errorf("reset stats_delta %s: %w", v.kind, err)
+		}
+	}
+
+	db.statsMu.Lock()
+	db.lastStatsRefresh = time.Now()
+	db.lastStatsRefreshDuration = time.Since(start)
+	db.statsMu.Unlock()
+
+	return nil
+}
+
+// bumpStatsDelta increments stats_delta for kind by delta inside tx, so
+// GetStats can report near-real-time counts between materialized view
+// refreshes. Only called from the r-value ingestion paths (the hot path
+// this exists for); recovered keys/nonces/pending components change orders
+// of magnitude less often, so the periodic refresh alone keeps those fresh
+// enough.
+func bumpStatsDelta(ctx context.Context, tx *sql.Tx, kind string, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO stats_delta (kind, delta) VALUES ($1, $2)
+		ON CONFLICT (kind) DO UPDATE SET delta = stats_delta.delta + $2
+	`, kind, delta)
+	return err
+}
+
+// statCount returns view's cached count plus any stats_delta recorded for
+// kind since the last refresh.
+func (db *DB) statCount(ctx context.Context, view, kind string) (int, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var value, delta int64
+	if err := db.conn.QueryRowContext(queryCtx, fmt.Sprintf("SELECT value FROM %s WHERE id = 1", view)).Scan(&value); err != nil {
+		return 0, db.wrapError(err)
+	}
+	if err := db.conn.QueryRowContext(queryCtx, "SELECT delta FROM stats_delta WHERE kind = $1", kind).Scan(&delta); err != nil {
+		return 0, db.wrapError(err)
+	}
+	return int(value + delta), nil
+}
+
+// GetStats returns aggregate counters for the dashboard and API, combining
+// each mv_stats_* materialized view with stats_delta so the numbers stay
+// close to real-time between RefreshStats runs.
+func (db *DB) GetStats(ctx context.Context) (*coredb.Stats, error) {
+	stats := &coredb.Stats{Healthy: true}
+
+	health := db.Health(ctx)
+	if !health.Connected {
+		stats.Healthy = false
+		return stats, fmt.Errorf("database unhealthy: %s", health.Error)
+	}
+
+	if v, err := db.statCount(ctx, "mv_stats_r_values", "r_values"); err == nil {
+		stats.TotalRValues = v
+		stats.TotalCollisions = v
+	}
+	if v, err := db.statCount(ctx, "mv_stats_recovered_keys", "recovered_keys"); err == nil {
+		stats.RecoveredKeys = v
+	}
+	if v, err := db.statCount(ctx, "mv_stats_recovered_nonces", "recovered_nonces"); err == nil {
+		stats.RecoveredNonces = v
+	}
+	if v, err := db.statCount(ctx, "mv_stats_pending_components", "pending_components"); err == nil {
+		stats.PendingComponents = v
+	}
+
+	return stats, nil
+}
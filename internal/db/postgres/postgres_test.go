@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestWrapError(t *testing.T) {
+	db := &DB{}
+
+	tests := []struct {
+		name        string
+		err         error
+		expectedNil bool
+	}{
+		{"nil error", nil, true},
+		{"generic error", errors.New("some error"), false},
+		{"context deadline", context.DeadlineExceeded, false},
+		{"sql no rows", sql.ErrNoRows, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := db.wrapError(tt.err)
+			if tt.expectedNil && result != nil {
+				t.Errorf("expected nil, got %v", result)
+			}
+			if !tt.expectedNil && result == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestHexConversion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0xabcd", "0xabcd"},
+		{"abcd", "0xabcd"},
+		{"0x", "0x"},
+	}
+
+	for _, tt := range tests {
+		b := hexToBytes(tt.input)
+		result := bytesToHex(b)
+		if result != tt.expected {
+			t.Errorf("hexToBytes/bytesToHex(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxReorgDepth bounds how far FindLCA will walk back before giving
+// up. 128 blocks covers every reorg seen in practice on the chains this
+// scanner watches; anything deeper needs a human to look at the chain.
+const DefaultMaxReorgDepth = 128
+
+// FindLCA walks backward from currentTip looking for the highest block
+// height where the hash this database recorded matches what lookup reports
+// the chain has there now. lookup is expected to hit the chain's RPC
+// endpoint for a given height; heights this database never scanned are
+// skipped rather than treated as a mismatch, so resuming from a cold cursor
+// doesn't get misread as a reorg.
+func FindLCA(ctx context.Context, database Store, chainID int, currentTip uint64, maxDepth uint64, lookup func(blockNumber uint64) (hash string, err error)) (uint64, error) {
+	for depth := uint64(0); depth <= maxDepth; depth++ {
+		if depth > currentTip {
+			break
+		}
+		height := currentTip - depth
+
+		stored, err := database.GetScannedBlock(ctx, chainID, height)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("get scanned block %d: %w", height, err)
+		}
+
+		onChain, err := lookup(height)
+		if err != nil {
+			return 0, fmt.Errorf("look up on-chain hash for block %d: %w", height, err)
+		}
+
+		if strings.EqualFold(stored, onChain) {
+			return height, nil
+		}
+	}
+
+	return 0, fmt.Errorf("reorg on chain %d exceeds max depth %d from tip %d", chainID, maxDepth, currentTip)
+}
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFindLCAWalksBackToMatchingHash(t *testing.T) {
+	ctx := context.Background()
+	mockDB := NewMock()
+
+	chain := map[uint64]string{
+		8: "0xaa", 9: "0xbb", 10: "0xcc-stale",
+	}
+	for height, hash := range chain {
+		if err := mockDB.SaveScannedBlock(ctx, 1, height, hash); err != nil {
+			t.Fatalf("SaveScannedBlock failed: %v", err)
+		}
+	}
+
+	onChain := map[uint64]string{
+		8: "0xaa", 9: "0xbb", 10: "0xcc-reorged",
+	}
+	lookup := func(height uint64) (string, error) {
+		return onChain[height], nil
+	}
+
+	lca, err := FindLCA(ctx, mockDB, 1, 10, DefaultMaxReorgDepth, lookup)
+	if err != nil {
+		t.Fatalf("FindLCA failed: %v", err)
+	}
+	if lca != 9 {
+		t.Errorf("expected LCA at block 9, got %d", lca)
+	}
+}
+
+func TestFindLCASkipsNeverScannedHeights(t *testing.T) {
+	ctx := context.Background()
+	mockDB := NewMock()
+
+	if err := mockDB.SaveScannedBlock(ctx, 1, 5, "0xmatch"); err != nil {
+		t.Fatalf("SaveScannedBlock failed: %v", err)
+	}
+
+	lookup := func(height uint64) (string, error) {
+		if height == 5 {
+			return "0xmatch", nil
+		}
+		return "0xdifferent", nil
+	}
+
+	lca, err := FindLCA(ctx, mockDB, 1, 7, DefaultMaxReorgDepth, lookup)
+	if err != nil {
+		t.Fatalf("FindLCA failed: %v", err)
+	}
+	if lca != 5 {
+		t.Errorf("expected FindLCA to skip unscanned heights 6 and 7 and land on 5, got %d", lca)
+	}
+}
+
+func TestFindLCAExceedsMaxDepth(t *testing.T) {
+	ctx := context.Background()
+	mockDB := NewMock()
+
+	lookup := func(height uint64) (string, error) {
+		return "0xnevermatches", nil
+	}
+
+	if _, err := FindLCA(ctx, mockDB, 1, 100, 5, lookup); err == nil {
+		t.Error("expected an error when no common ancestor is found within maxDepth")
+	}
+}
+
+func TestReorgEventsAreScopedToChainAndNewestFirst(t *testing.T) {
+	ctx := context.Background()
+	mockDB := NewMock()
+
+	events := []ReorgEvent{
+		{ChainID: 1, OldTip: 10, ForkBlock: 8},
+		{ChainID: 2, OldTip: 20, ForkBlock: 15},
+		{ChainID: 1, OldTip: 30, ForkBlock: 28},
+	}
+	for _, e := range events {
+		if err := mockDB.RecordReorgEvent(ctx, &e); err != nil {
+			t.Fatalf("RecordReorgEvent failed: %v", err)
+		}
+	}
+
+	got, err := mockDB.GetReorgEvents(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("GetReorgEvents failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events for chain 1, got %d", len(got))
+	}
+	if got[0].ForkBlock != 28 || got[1].ForkBlock != 8 {
+		t.Errorf("expected newest-first order [28, 8], got [%d, %d]", got[0].ForkBlock, got[1].ForkBlock)
+	}
+}
+
+func TestReorgEventsRespectsLimit(t *testing.T) {
+	ctx := context.Background()
+	mockDB := NewMock()
+
+	for i := uint64(0); i < 5; i++ {
+		if err := mockDB.RecordReorgEvent(ctx, &ReorgEvent{ChainID: 1, OldTip: i + 1, ForkBlock: i}); err != nil {
+			t.Fatalf("RecordReorgEvent failed: %v", err)
+		}
+	}
+
+	got, err := mockDB.GetReorgEvents(ctx, 1, 2)
+	if err != nil {
+		t.Fatalf("GetReorgEvents failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected limit to cap results at 2, got %d", len(got))
+	}
+}
@@ -0,0 +1,153 @@
+package db
+
+import "errors"
+
+// ErrNotFound is returned when a lookup finds no matching row.
+var ErrNotFound = errors.New("not found")
+
+// TxRef identifies the transaction an r-value was first observed in.
+type TxRef struct {
+	TxHash      string `json:"tx_hash"`
+	ChainID     int    `json:"chain_id"`
+	BlockNumber uint64 `json:"block_number"`
+}
+
+// TxInput is a single signature component submitted for collision checking.
+// SValue and HValue are optional: they let a caller that has already
+// decoded a transaction's full signature record it for later biased-nonce
+// (HNP) recovery via GetBiasedSignatureCandidates, but most callers only
+// have the r-value and can leave them empty.
+type TxInput struct {
+	RValue      string `json:"r_value"`
+	TxHash      string `json:"tx_hash"`
+	ChainID     int    `json:"chain_id"`
+	Address     string `json:"address"`
+	BlockNumber uint64 `json:"block_number"`
+	SValue      string `json:"s_value,omitempty"`
+	HValue      string `json:"h_value,omitempty"`
+}
+
+// SignatureComponents is one (r, s, h) signature triple recorded for an
+// address, as fed to recovery.SolveHNP once enough of them have built up for
+// a suspected leaky signer.
+type SignatureComponents struct {
+	RValue      string `json:"r_value"`
+	SValue      string `json:"s_value"`
+	HValue      string `json:"h_value"`
+	TxHash      string `json:"tx_hash"`
+	BlockNumber uint64 `json:"block_number"`
+}
+
+// WatchedAddress is an address an operator has asked to track explicitly,
+// independent of the scanner's systemAddresses exclusion set: the scanner
+// watches for it rather than ignoring it. Label is a free-form operator note
+// (e.g. "suspected leaky signer - ticket #412"); Priority lets
+// watched_priority mode order catch-up work when there's more than one.
+type WatchedAddress struct {
+	Address  string `json:"address"`
+	ChainID  int    `json:"chain_id"`
+	Label    string `json:"label"`
+	Priority int    `json:"priority"`
+	AddedAt  string `json:"added_at"`
+}
+
+// CollisionResult reports an r-value that was already on file when a new
+// TxInput for it came in.
+type CollisionResult struct {
+	RValue     string `json:"r_value"`
+	TxHash     string `json:"tx_hash"`
+	ChainID    int    `json:"chain_id"`
+	Address    string `json:"address"`
+	FirstTxRef TxRef  `json:"first_tx_ref"`
+}
+
+// Collision is the persisted view of an r-value that has collided, for the
+// API and UI to render.
+type Collision struct {
+	RValue string  `json:"r_value"`
+	TxRefs []TxRef `json:"tx_refs"`
+}
+
+// RecoveredKey represents a recovered private key.
+type RecoveredKey struct {
+	ID         int64    `json:"id"`
+	Address    string   `json:"address"`
+	PrivateKey string   `json:"private_key"`
+	ChainID    int      `json:"chain_id"`
+	ChainName  string   `json:"chain_name"`
+	RValues    []string `json:"r_values"`
+	TxHashes   []string `json:"tx_hashes"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// RecoveredNonce is a signature nonce derived while recovering a key, kept
+// around because it also solves every other signature sharing that r-value
+// (cross-key recovery).
+type RecoveredNonce struct {
+	RValue           string `json:"r_value"`
+	KValue           string `json:"k_value"`
+	DerivedFromKeyID int64  `json:"derived_from_key_id"`
+}
+
+// PendingComponent is a cross-key collision that isn't solvable yet: more
+// addresses are involved than equations recovered so far, so it's parked
+// until a matching nonce or another collision makes the linear system
+// determined.
+type PendingComponent struct {
+	ID        int64    `json:"id"`
+	RValues   []string `json:"r_values"`
+	TxHashes  []string `json:"tx_hashes"`
+	Addresses []string `json:"addresses"`
+	ChainIDs  []int    `json:"chain_ids"`
+	Equations int      `json:"equations"`
+	Unknowns  int      `json:"unknowns"`
+}
+
+// Stats holds aggregate counts surfaced on the dashboard and API.
+type Stats struct {
+	TotalRValues      int  `json:"total_r_values"`
+	TotalCollisions   int  `json:"total_collisions"`
+	RecoveredKeys     int  `json:"recovered_keys"`
+	RecoveredNonces   int  `json:"recovered_nonces"`
+	PendingComponents int  `json:"pending_components"`
+	Healthy           bool `json:"healthy"`
+}
+
+// ReorgEvent records a single detected-and-resolved chain reorg, purely for
+// observability (e.g. a dashboard widget or alerting). It's a log of what
+// FindLCA/checkReorg already did, not part of the repair path itself - see
+// DeleteScannedBlocksAtOrAbove and DeleteCollisionsAboveBlock for that.
+type ReorgEvent struct {
+	ChainID    int    `json:"chain_id"`
+	OldTip     uint64 `json:"old_tip"`
+	ForkBlock  uint64 `json:"fork_block"`
+	DetectedAt string `json:"detected_at"`
+}
+
+// Shard is a leased (chainID, rangeStart, rangeEnd] block range handed to one
+// scanner replica by ClaimShard, so multiple replicas can rescan the same
+// chain's history in parallel without two of them fetching the same blocks.
+// Owner identifies the leaseholder (for diagnostics and lease-theft
+// detection); LeaseExpiresAt lets an expired lease be reclaimed by another
+// replica if its owner crashed before calling CompleteShard.
+type Shard struct {
+	ChainID        int    `json:"chain_id"`
+	RangeStart     uint64 `json:"range_start"`
+	RangeEnd       uint64 `json:"range_end"`
+	Owner          string `json:"owner"`
+	LeaseExpiresAt string `json:"lease_expires_at"`
+}
+
+// HealthStatus represents database health.
+type HealthStatus struct {
+	Connected       bool   `json:"connected"`
+	LatencyMs       int64  `json:"latency_ms"`
+	OpenConnections int    `json:"open_connections"`
+	Error           string `json:"error,omitempty"`
+
+	// LastStatsRefresh and StatsRefreshDurationMs report when GetStats'
+	// materialized views were last refreshed and how long that took, so
+	// operators can tell how stale the stats_delta-adjusted counts are.
+	LastStatsRefresh       string `json:"last_stats_refresh,omitempty"`
+	StatsRefreshDurationMs int64  `json:"stats_refresh_duration_ms,omitempty"`
+}
@@ -0,0 +1,176 @@
+// Package events provides a typed, in-process event bus that fans out
+// scanner/recovery activity (new R-values, collisions, recovered
+// keys/nonces, chain lifecycle, RPC error thresholds) to live subscribers,
+// mirroring the push-interface pattern logger.Subscribe already uses for
+// log tailing.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Kind identifies what happened, so a subscriber can filter or render
+// without inspecting every field of Event.
+type Kind string
+
+const (
+	// KindRValueObserved fires when new R-values are recorded without a
+	// collision.
+	KindRValueObserved Kind = "r_value_observed"
+	// KindCollision fires whenever two transactions share an R-value,
+	// same-key or cross-key.
+	KindCollision Kind = "collision"
+	// KindKeyRecovered fires once a private key has been recovered.
+	KindKeyRecovered Kind = "key_recovered"
+	// KindNonceRecovered fires once a signature nonce has been derived,
+	// ahead of (or independent of) a full key recovery.
+	KindNonceRecovered Kind = "nonce_recovered"
+	// KindChainStarted fires when a chain scanner starts.
+	KindChainStarted Kind = "chain_started"
+	// KindChainStopped fires when a chain scanner stops.
+	KindChainStopped Kind = "chain_stopped"
+	// KindRPCErrorThreshold fires each time a chain's RPC error count
+	// crosses another multiple of the configured threshold.
+	KindRPCErrorThreshold Kind = "rpc_error_threshold"
+)
+
+// Event is a single item on the bus. ID and Timestamp are assigned by
+// Publish, so callers only need to fill in the fields relevant to Kind.
+type Event struct {
+	ID        uint64    `json:"id"`
+	Kind      Kind      `json:"kind"`
+	Timestamp time.Time `json:"timestamp"`
+	ChainID   int       `json:"chain_id,omitempty"`
+	ChainName string    `json:"chain_name,omitempty"`
+	Address   string    `json:"address,omitempty"`
+	RValue    string    `json:"r_value,omitempty"`
+	TxHash    string    `json:"tx_hash,omitempty"`
+	Count     int       `json:"count,omitempty"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// subscriberChanSize bounds each Subscribe() channel; once full, the oldest
+// buffered event is dropped to make room rather than blocking the publisher,
+// same policy as logger.Logger.publish.
+const subscriberChanSize = 256
+
+// backlogSize is how many past events Subscribe can replay for a caller
+// resuming from a Last-Event-ID, independent of any per-subscriber channel.
+const backlogSize = 1024
+
+// Bus fans out Events to subscribers and keeps a ring buffer of recent ones
+// so a reconnecting SSE client can resume from the last ID it saw instead of
+// missing whatever happened while it was disconnected.
+type Bus struct {
+	mu          sync.Mutex
+	nextID      uint64
+	backlog     []Event
+	backlogPos  int
+	backlogFull bool
+	subscribers map[int]chan Event
+	nextSubID   int
+}
+
+// NewBus creates an empty Bus.
+func NewBus() *Bus {
+	return &Bus{
+		backlog:     make([]Event, backlogSize),
+		subscribers: make(map[int]chan Event),
+	}
+}
+
+// Publish assigns e an ID and timestamp, records it in the resume backlog,
+// and fans it out to every current subscriber.
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	e.ID = b.nextID
+	e.Timestamp = time.Now()
+
+	b.backlog[b.backlogPos] = e
+	b.backlogPos = (b.backlogPos + 1) % len(b.backlog)
+	if b.backlogPos == 0 {
+		b.backlogFull = true
+	}
+
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+			// Subscriber is behind: drop its oldest buffered event to make
+			// room rather than block the publisher on a slow consumer.
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- e:
+			default:
+			}
+		}
+	}
+}
+
+// Subscribe returns a channel that first replays every backlogged event
+// after lastEventID (pass 0 for none), then receives every event published
+// from this point on, plus an unsubscribe function to stop and release it.
+// Replay fills the channel's subscriberChanSize buffer at most; backlogged
+// events beyond that are not replayed.
+func (b *Bus) Subscribe(lastEventID uint64) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberChanSize)
+
+	b.mu.Lock()
+	id := b.nextSubID
+	b.nextSubID++
+	b.subscribers[id] = ch
+	for _, e := range b.backlogSince(lastEventID) {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[id]; ok {
+			delete(b.subscribers, id)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// LastID returns the ID of the most recently published event, 0 if none
+// yet - the cursor a long-polling GET /api/collisions response hands back
+// for the next ?since=.
+func (b *Bus) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nextID
+}
+
+// backlogSince returns every buffered event with ID > lastEventID, oldest
+// first. Callers must hold b.mu.
+func (b *Bus) backlogSince(lastEventID uint64) []Event {
+	n := len(b.backlog)
+	start := 0
+	if !b.backlogFull {
+		n = b.backlogPos
+	} else {
+		start = b.backlogPos
+	}
+
+	result := make([]Event, 0, n)
+	for i := 0; i < n; i++ {
+		e := b.backlog[(start+i)%len(b.backlog)]
+		if e.ID > lastEventID {
+			result = append(result, e)
+		}
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBus_Subscribe_ReceivesNewEvents(t *testing.T) {
+	bus := NewBus()
+	ch, unsubscribe := bus.Subscribe(0)
+	defer unsubscribe()
+
+	bus.Publish(Event{Kind: KindCollision, RValue: "0xabc"})
+
+	select {
+	case ev := <-ch:
+		if ev.RValue != "0xabc" {
+			t.Errorf("expected RValue %q, got %q", "0xabc", ev.RValue)
+		}
+		if ev.ID != 1 {
+			t.Errorf("expected first published event to have ID 1, got %d", ev.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for published event")
+	}
+}
+
+func TestBus_Subscribe_ReplaysBacklogSinceLastEventID(t *testing.T) {
+	bus := NewBus()
+
+	bus.Publish(Event{Kind: KindChainStarted, ChainName: "ETH"})
+	bus.Publish(Event{Kind: KindChainStarted, ChainName: "BTC"})
+	bus.Publish(Event{Kind: KindChainStarted, ChainName: "BSC"})
+
+	ch, unsubscribe := bus.Subscribe(1)
+	defer unsubscribe()
+
+	var got []Event
+	for i := 0; i < 2; i++ {
+		select {
+		case ev := <-ch:
+			got = append(got, ev)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for replayed event %d", i)
+		}
+	}
+
+	if got[0].ChainName != "BTC" || got[1].ChainName != "BSC" {
+		t.Fatalf("expected replay of events after ID 1, got %+v", got)
+	}
+}
+
+func TestBus_Subscribe_DropsOldestWhenSubscriberFallsBehind(t *testing.T) {
+	bus := NewBus()
+	ch, unsubscribe := bus.Subscribe(0)
+	defer unsubscribe()
+
+	for i := 0; i < subscriberChanSize+10; i++ {
+		bus.Publish(Event{Kind: KindRValueObserved, Message: "message " + strconv.Itoa(i)})
+	}
+
+	if len(ch) != subscriberChanSize {
+		t.Fatalf("expected subscriber channel to be full at %d, got %d", subscriberChanSize, len(ch))
+	}
+
+	first := <-ch
+	if !strings.Contains(first.Message, "10") {
+		t.Errorf("expected oldest events to have been dropped, got %q first", first.Message)
+	}
+}
+
+func TestBus_LastID(t *testing.T) {
+	bus := NewBus()
+	if bus.LastID() != 0 {
+		t.Fatalf("expected LastID of a fresh bus to be 0, got %d", bus.LastID())
+	}
+
+	bus.Publish(Event{Kind: KindCollision})
+	bus.Publish(Event{Kind: KindCollision})
+
+	if bus.LastID() != 2 {
+		t.Fatalf("expected LastID 2 after two publishes, got %d", bus.LastID())
+	}
+}
+
+func TestBus_Subscribe_UnsubscribeStopsDelivery(t *testing.T) {
+	bus := NewBus()
+	ch, unsubscribe := bus.Subscribe(0)
+	unsubscribe()
+
+	bus.Publish(Event{Kind: KindChainStopped})
+
+	if _, ok := <-ch; ok {
+		t.Error("expected channel to be closed after unsubscribe")
+	}
+}
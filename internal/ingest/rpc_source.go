@@ -0,0 +1,85 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"ecdsa-scanner/internal/db"
+)
+
+// rpcTransaction is the subset of eth_getBlockByNumber's transaction fields
+// needed to extract an r-value.
+type rpcTransaction struct {
+	Hash string `json:"hash"`
+	From string `json:"from"`
+	R    string `json:"r"`
+}
+
+// rpcBlock is the subset of eth_getBlockByNumber's block fields needed to
+// walk transactions.
+type rpcBlock struct {
+	Number       string           `json:"number"`
+	Transactions []rpcTransaction `json:"transactions"`
+}
+
+// RPCSource backfills by replaying eth_getBlockByNumber one block at a time,
+// the same call the live scanner polls with. It's the fallback source for
+// chains without a statediff index.
+type RPCSource struct {
+	client          *rpc.Client
+	systemAddresses map[string]bool
+}
+
+// NewRPCSource creates an RPCSource that dials rpcURL once and reuses the
+// connection for the whole backfill.
+func NewRPCSource(rpcURL string, systemAddresses map[string]bool) (*RPCSource, error) {
+	client, err := rpc.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("dial rpc: %w", err)
+	}
+	return &RPCSource{client: client, systemAddresses: systemAddresses}, nil
+}
+
+// Name implements Source.
+func (s *RPCSource) Name() string { return "rpc" }
+
+// Backfill implements Source.
+func (s *RPCSource) Backfill(ctx context.Context, chainID int, fromBlock, toBlock uint64, onBatch func(TxBatch) error) error {
+	for blockNum := fromBlock; blockNum <= toBlock; blockNum++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		var block rpcBlock
+		blockNumHex := fmt.Sprintf("0x%x", blockNum)
+		if err := s.client.CallContext(ctx, &block, "eth_getBlockByNumber", blockNumHex, true); err != nil {
+			return fmt.Errorf("fetch block %d: %w", blockNum, err)
+		}
+
+		var txs []db.TxInput
+		for _, tx := range block.Transactions {
+			if tx.R == "" || tx.R == "0x0" || tx.From == "" {
+				continue
+			}
+			if s.systemAddresses[strings.ToLower(tx.From)] {
+				continue
+			}
+			txs = append(txs, db.TxInput{
+				RValue:  strings.ToLower(tx.R),
+				TxHash:  strings.ToLower(tx.Hash),
+				ChainID: chainID,
+				Address: strings.ToLower(tx.From),
+			})
+		}
+
+		if err := onBatch(TxBatch{BlockNumber: blockNum, Txs: txs}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+
+	"ecdsa-scanner/internal/db"
+)
+
+// StatediffSource backfills by reading an ipld-eth-db-compatible statediff
+// index directly out of Postgres instead of replaying eth_getBlockByNumber.
+// Walking transaction_cids by block_number is orders of magnitude faster
+// than an RPC-by-RPC historical scan, which is the whole point of using one
+// over RPCSource for a full-history backfill.
+type StatediffSource struct {
+	conn            *sql.DB
+	systemAddresses map[string]bool
+}
+
+// NewStatediffSource opens a connection to a statediff-indexed Postgres
+// database. databaseURL follows the same DSN format as postgres.New.
+func NewStatediffSource(databaseURL string, systemAddresses map[string]bool) (*StatediffSource, error) {
+	conn, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open statediff db: %w", err)
+	}
+	if err := conn.Ping(); err != nil {
+		return nil, fmt.Errorf("ping statediff db: %w", err)
+	}
+	return &StatediffSource{conn: conn, systemAddresses: systemAddresses}, nil
+}
+
+// Close releases the underlying connection pool.
+func (s *StatediffSource) Close() error {
+	return s.conn.Close()
+}
+
+// Name implements Source.
+func (s *StatediffSource) Name() string { return "statediff" }
+
+// Backfill implements Source. It walks transaction_cids in block_number
+// order, batching all rows for a block into a single TxBatch so the cursor
+// only ever advances on a block boundary.
+func (s *StatediffSource) Backfill(ctx context.Context, chainID int, fromBlock, toBlock uint64, onBatch func(TxBatch) error) error {
+	rows, err := s.conn.QueryContext(ctx, `
+		SELECT block_number, tx_hash, src, r, tx_type
+		FROM transaction_cids
+		WHERE block_number >= $1 AND block_number <= $2
+		ORDER BY block_number ASC`,
+		fromBlock, toBlock)
+	if err != nil {
+		return fmt.Errorf("query transaction_cids: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		currentBlock uint64
+		haveBlock    bool
+		batch        []db.TxInput
+	)
+	flush := func(blockNum uint64) error {
+		if err := onBatch(TxBatch{BlockNumber: blockNum, Txs: batch}); err != nil {
+			return err
+		}
+		batch = nil
+		return nil
+	}
+
+	for rows.Next() {
+		var (
+			blockNumber uint64
+			txHash      string
+			src         string
+			rValue      string
+			txType      int
+		)
+		if err := rows.Scan(&blockNumber, &txHash, &src, &rValue, &txType); err != nil {
+			return fmt.Errorf("scan transaction_cids row: %w", err)
+		}
+
+		if haveBlock && blockNumber != currentBlock {
+			if err := flush(currentBlock); err != nil {
+				return err
+			}
+		}
+		currentBlock = blockNumber
+		haveBlock = true
+
+		if rValue == "" || rValue == "0x0" || src == "" || s.systemAddresses[strings.ToLower(src)] {
+			continue
+		}
+		batch = append(batch, db.TxInput{
+			RValue:  strings.ToLower(rValue),
+			TxHash:  strings.ToLower(txHash),
+			ChainID: chainID,
+			Address: strings.ToLower(src),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate transaction_cids: %w", err)
+	}
+
+	if haveBlock {
+		if err := flush(currentBlock); err != nil {
+			return err
+		}
+	}
+	// Make sure the cursor reaches toBlock even if the index has no
+	// transactions in the tail of the range, so a resumed run doesn't
+	// re-scan empty blocks every time.
+	if !haveBlock || currentBlock < toBlock {
+		if err := onBatch(TxBatch{BlockNumber: toBlock}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BlobVersionedHashes looks up the EIP-4844 blob versioned hashes attached
+// to txHash, so a blob transaction pulled from the statediff index can have
+// its signing hash recomputed without an RPC round-trip back to the chain.
+func (s *StatediffSource) BlobVersionedHashes(ctx context.Context, txHash string) ([]string, error) {
+	rows, err := s.conn.QueryContext(ctx, `
+		SELECT blob_hash
+		FROM blob_versioned_hashes
+		WHERE tx_hash = $1
+		ORDER BY index ASC`,
+		txHash)
+	if err != nil {
+		return nil, fmt.Errorf("query blob_versioned_hashes: %w", err)
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("scan blob_versioned_hashes row: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate blob_versioned_hashes: %w", err)
+	}
+	return hashes, nil
+}
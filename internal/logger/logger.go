@@ -1,7 +1,9 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"sync"
 	"time"
@@ -27,11 +29,22 @@ func (l Level) String() string {
 	}
 }
 
-// Entry represents a single log entry
+// Field is a structured key/value pair attached to a log entry, e.g. chain
+// name, block number, or tx hash.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Entry represents a single log entry. ID is assigned by log(), so a
+// long-polling GET /api/logs?since=<id> can resume from exactly where it
+// left off instead of re-fetching the whole buffer every time.
 type Entry struct {
-	Timestamp string `json:"timestamp"`
-	Level     string `json:"level"`
-	Message   string `json:"message"`
+	ID        uint64         `json:"id"`
+	Timestamp string         `json:"timestamp"`
+	Level     string         `json:"level"`
+	Message   string         `json:"message"`
+	Fields    map[string]any `json:"fields,omitempty"`
 }
 
 // Buffer is a ring buffer for storing recent log messages
@@ -42,34 +55,155 @@ type Buffer struct {
 	pos     int
 }
 
+// subscriberChanSize bounds each Subscribe() channel; once full, the oldest
+// buffered entry is dropped to make room rather than blocking the logger.
+const subscriberChanSize = 256
+
+// core holds the state shared by a Logger and every child created via
+// With(), so setting the min level, JSON output, or subscribing applies
+// regardless of which child logged the entry.
+type core struct {
+	buffer *Buffer
+
+	mu          sync.Mutex
+	minLevel    Level
+	jsonOutput  io.Writer
+	subscribers map[int]chan Entry
+	nextSubID   int
+	lastID      uint64
+}
+
 // Logger wraps standard logging with a ring buffer
 type Logger struct {
-	buffer *Buffer
+	core   *core
+	fields map[string]any
 }
 
 // New creates a new Logger with the specified buffer size
 func New(bufferSize int) *Logger {
 	return &Logger{
-		buffer: &Buffer{
-			entries: make([]Entry, bufferSize),
-			size:    bufferSize,
+		core: &core{
+			buffer: &Buffer{
+				entries: make([]Entry, bufferSize),
+				size:    bufferSize,
+			},
+			subscribers: make(map[int]chan Entry),
 		},
 	}
 }
 
+// With returns a child logger that attaches fields to every entry it logs,
+// in addition to any fields already attached by a parent With() call. The
+// child shares this logger's buffer, subscribers, and level/output settings.
+func (l *Logger) With(fields ...Field) *Logger {
+	merged := make(map[string]any, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for _, f := range fields {
+		merged[f.Key] = f.Value
+	}
+	return &Logger{core: l.core, fields: merged}
+}
+
+// SetMinLevel drops entries below level before they reach the ring buffer,
+// JSON output, or subscribers.
+func (l *Logger) SetMinLevel(level Level) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	l.core.minLevel = level
+}
+
+// SetJSONOutput writes every entry at or above the min level to w as a JSON
+// line, for log aggregators. Pass nil to disable.
+func (l *Logger) SetJSONOutput(w io.Writer) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	l.core.jsonOutput = w
+}
+
+// Subscribe returns a channel that receives every entry logged from this
+// point on, and an unsubscribe function to stop and release it. The channel
+// is bounded; a subscriber that falls behind has its oldest buffered entry
+// dropped to make room for the newest one rather than blocking the logger.
+func (l *Logger) Subscribe() (<-chan Entry, func()) {
+	ch := make(chan Entry, subscriberChanSize)
+
+	l.core.mu.Lock()
+	id := l.core.nextSubID
+	l.core.nextSubID++
+	l.core.subscribers[id] = ch
+	l.core.mu.Unlock()
+
+	unsubscribe := func() {
+		l.core.mu.Lock()
+		defer l.core.mu.Unlock()
+		if _, ok := l.core.subscribers[id]; ok {
+			delete(l.core.subscribers, id)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
 func (l *Logger) log(level Level, format string, args ...interface{}) {
+	l.core.mu.Lock()
+	minLevel := l.core.minLevel
+	jsonOutput := l.core.jsonOutput
+
+	if level < minLevel {
+		l.core.mu.Unlock()
+		return
+	}
+	l.core.lastID++
+	id := l.core.lastID
+	l.core.mu.Unlock()
+
 	msg := fmt.Sprintf(format, args...)
 	fullMsg := fmt.Sprintf("[%s] %s", level.String(), msg)
 	log.Print(fullMsg)
 
-	l.buffer.mu.Lock()
-	l.buffer.entries[l.buffer.pos] = Entry{
+	entry := Entry{
+		ID:        id,
 		Timestamp: time.Now().Format("2006-01-02 15:04:05.000"),
 		Level:     level.String(),
 		Message:   msg,
+		Fields:    l.fields,
+	}
+
+	l.core.buffer.mu.Lock()
+	l.core.buffer.entries[l.core.buffer.pos] = entry
+	l.core.buffer.pos = (l.core.buffer.pos + 1) % l.core.buffer.size
+	l.core.buffer.mu.Unlock()
+
+	if jsonOutput != nil {
+		if line, err := json.Marshal(entry); err == nil {
+			fmt.Fprintln(jsonOutput, string(line))
+		}
+	}
+
+	l.publish(entry)
+}
+
+func (l *Logger) publish(entry Entry) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	for _, ch := range l.core.subscribers {
+		select {
+		case ch <- entry:
+		default:
+			// Subscriber is behind: drop its oldest buffered entry to make
+			// room rather than block logging on a slow consumer.
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- entry:
+			default:
+			}
+		}
 	}
-	l.buffer.pos = (l.buffer.pos + 1) % l.buffer.size
-	l.buffer.mu.Unlock()
 }
 
 // Info logs an informational message
@@ -94,15 +228,41 @@ func (l *Logger) Log(format string, args ...interface{}) {
 
 // GetEntries returns all log entries in chronological order
 func (l *Logger) GetEntries() []Entry {
-	l.buffer.mu.RLock()
-	defer l.buffer.mu.RUnlock()
-
-	result := make([]Entry, 0, l.buffer.size)
-	for i := 0; i < l.buffer.size; i++ {
-		idx := (l.buffer.pos + i) % l.buffer.size
-		if l.buffer.entries[idx].Timestamp != "" {
-			result = append(result, l.buffer.entries[idx])
+	l.core.buffer.mu.RLock()
+	defer l.core.buffer.mu.RUnlock()
+
+	result := make([]Entry, 0, l.core.buffer.size)
+	for i := 0; i < l.core.buffer.size; i++ {
+		idx := (l.core.buffer.pos + i) % l.core.buffer.size
+		if l.core.buffer.entries[idx].Timestamp != "" {
+			result = append(result, l.core.buffer.entries[idx])
+		}
+	}
+	return result
+}
+
+// EntriesSince returns every buffered entry with ID greater than since, in
+// chronological order, so a long-polling GET /api/logs?since=<id> can
+// resume from exactly where it left off instead of re-fetching the whole
+// buffer every time. since=0 behaves like GetEntries.
+func (l *Logger) EntriesSince(since uint64) []Entry {
+	l.core.buffer.mu.RLock()
+	defer l.core.buffer.mu.RUnlock()
+
+	result := make([]Entry, 0, l.core.buffer.size)
+	for i := 0; i < l.core.buffer.size; i++ {
+		idx := (l.core.buffer.pos + i) % l.core.buffer.size
+		if e := l.core.buffer.entries[idx]; e.Timestamp != "" && e.ID > since {
+			result = append(result, e)
 		}
 	}
 	return result
 }
+
+// LastID returns the ID of the most recently logged entry, 0 if none yet -
+// the cursor a GET /api/logs response hands back for the next ?since=.
+func (l *Logger) LastID() uint64 {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	return l.core.lastID
+}
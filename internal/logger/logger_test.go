@@ -1,8 +1,11 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestLogger_LogLevels(t *testing.T) {
@@ -93,6 +96,155 @@ func TestLogger_EmptyBuffer(t *testing.T) {
 	}
 }
 
+func TestLogger_With_AttachesFields(t *testing.T) {
+	log := New(10)
+	chainLog := log.With(Field{Key: "chain", Value: "ETH"}, Field{Key: "block", Value: 123})
+
+	chainLog.Info("scanned")
+
+	entries := log.GetEntries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Fields["chain"] != "ETH" || entries[0].Fields["block"] != 123 {
+		t.Errorf("expected fields to be attached, got %+v", entries[0].Fields)
+	}
+}
+
+func TestLogger_With_ChildInheritsParentFields(t *testing.T) {
+	log := New(10)
+	base := log.With(Field{Key: "chain", Value: "ETH"})
+	child := base.With(Field{Key: "block", Value: 5})
+
+	child.Info("scanned")
+
+	entries := log.GetEntries()
+	if entries[0].Fields["chain"] != "ETH" {
+		t.Errorf("expected child to inherit parent field, got %+v", entries[0].Fields)
+	}
+	if entries[0].Fields["block"] != 5 {
+		t.Errorf("expected child's own field to be set, got %+v", entries[0].Fields)
+	}
+}
+
+func TestLogger_SetMinLevel_DropsBelowThreshold(t *testing.T) {
+	log := New(10)
+	log.SetMinLevel(LevelWarn)
+
+	log.Info("should be dropped")
+	log.Warn("should be kept")
+	log.Error("should be kept")
+
+	entries := log.GetEntries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries after filtering, got %d", len(entries))
+	}
+	if entries[0].Level != "WARN" || entries[1].Level != "ERROR" {
+		t.Errorf("expected WARN and ERROR entries, got %+v", entries)
+	}
+}
+
+func TestLogger_SetJSONOutput_WritesJSONLines(t *testing.T) {
+	log := New(10)
+	var buf bytes.Buffer
+	log.SetJSONOutput(&buf)
+
+	log.Info("hello %s", "world")
+
+	var entry Entry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected valid JSON line, got %q: %v", buf.String(), err)
+	}
+	if entry.Message != "hello world" {
+		t.Errorf("expected message %q, got %q", "hello world", entry.Message)
+	}
+}
+
+func TestLogger_Subscribe_ReceivesNewEntries(t *testing.T) {
+	log := New(10)
+	ch, unsubscribe := log.Subscribe()
+	defer unsubscribe()
+
+	log.Info("subscribed message")
+
+	select {
+	case entry := <-ch:
+		if entry.Message != "subscribed message" {
+			t.Errorf("expected %q, got %q", "subscribed message", entry.Message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for subscribed entry")
+	}
+}
+
+func TestLogger_Subscribe_DropsOldestWhenSubscriberFallsBehind(t *testing.T) {
+	log := New(10000)
+	ch, unsubscribe := log.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < subscriberChanSize+10; i++ {
+		log.Info("message %d", i)
+	}
+
+	if len(ch) != subscriberChanSize {
+		t.Fatalf("expected subscriber channel to be full at %d, got %d", subscriberChanSize, len(ch))
+	}
+
+	first := <-ch
+	if !strings.Contains(first.Message, "10") {
+		t.Errorf("expected oldest entries to have been dropped, got %q first", first.Message)
+	}
+}
+
+func TestLogger_Subscribe_UnsubscribeStopsDelivery(t *testing.T) {
+	log := New(10)
+	ch, unsubscribe := log.Subscribe()
+	unsubscribe()
+
+	log.Info("after unsubscribe")
+
+	if _, ok := <-ch; ok {
+		t.Error("expected channel to be closed after unsubscribe")
+	}
+}
+
+func TestLogger_EntriesSince(t *testing.T) {
+	log := New(10)
+	log.Info("message 1")
+	log.Info("message 2")
+	log.Info("message 3")
+
+	all := log.GetEntries()
+	cursor := all[0].ID
+
+	since := log.EntriesSince(cursor)
+	if len(since) != 2 {
+		t.Fatalf("expected 2 entries after cursor, got %d", len(since))
+	}
+	if !strings.Contains(since[0].Message, "message 2") || !strings.Contains(since[1].Message, "message 3") {
+		t.Errorf("expected [message 2, message 3], got %+v", since)
+	}
+
+	if len(log.EntriesSince(log.LastID())) != 0 {
+		t.Error("expected no entries newer than LastID")
+	}
+}
+
+func TestLogger_LastID(t *testing.T) {
+	log := New(10)
+	if log.LastID() != 0 {
+		t.Fatalf("expected LastID of a fresh logger to be 0, got %d", log.LastID())
+	}
+
+	log.Info("message 1")
+	log.Info("message 2")
+
+	entries := log.GetEntries()
+	if log.LastID() != entries[len(entries)-1].ID {
+		t.Errorf("expected LastID to match the most recent entry's ID")
+	}
+}
+
 func TestLevel_String(t *testing.T) {
 	tests := []struct {
 		level    Level
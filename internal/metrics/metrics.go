@@ -0,0 +1,66 @@
+// Package metrics defines the small instrumentation surface the scanner
+// reports activity through, and a Prometheus-backed implementation of it.
+// The scanner depends only on the Metrics interface, so tests (and any
+// deployment that doesn't want Prometheus) can inject Noop instead. The db
+// package does not report through it: the database-health and
+// pending-component gauges are set by the api package from scanner stats.
+package metrics
+
+import "time"
+
+// Metrics is the instrumentation surface scanner.Scanner reports through;
+// no db.Store implementation calls it. Every method is safe to call
+// concurrently, matching how logger.Logger and events.Bus are used from
+// the same call sites.
+type Metrics interface {
+	// RValuesSeen adds n to the total R-values observed for chain, whether
+	// or not any of them collided.
+	RValuesSeen(chain string, n int)
+	// CollisionDetected adds 1 to the total collisions detected.
+	CollisionDetected()
+	// KeyRecovered adds 1 to the total private keys recovered.
+	KeyRecovered()
+	// NonceRecovered adds 1 to the total signature nonces derived.
+	NonceRecovered()
+	// RPCError adds 1 to chain's total RPC errors.
+	RPCError(chain string)
+	// BlockScanned adds 1 to chain's total blocks scanned.
+	BlockScanned(chain string)
+
+	// ObserveRPCLatency records how long a single RPC call to chain took.
+	ObserveRPCLatency(chain string, d time.Duration)
+	// ObserveBlockScanDuration records how long scanning one block of chain
+	// took, from fetch through collision-check.
+	ObserveBlockScanDuration(chain string, d time.Duration)
+
+	// SetPendingComponents sets the current count of unsolved cross-key
+	// pending components.
+	SetPendingComponents(n int)
+	// SetDatabaseHealthy sets whether the last health check succeeded.
+	SetDatabaseHealthy(healthy bool)
+	// SetChainRunning sets whether chain's scanner loop is currently running.
+	SetChainRunning(chain string, running bool)
+	// SetHeadLag sets chain's current head lag: the chain's latest known
+	// block minus the last block scanner has fully scanned.
+	SetHeadLag(chain string, lag int64)
+}
+
+// Noop implements Metrics by discarding everything, so callers that don't
+// care about metrics (most tests, or a deployment with Prometheus
+// disabled) don't have to special-case a nil Metrics.
+type Noop struct{}
+
+var _ Metrics = Noop{}
+
+func (Noop) RValuesSeen(chain string, n int)                        {}
+func (Noop) CollisionDetected()                                     {}
+func (Noop) KeyRecovered()                                          {}
+func (Noop) NonceRecovered()                                        {}
+func (Noop) RPCError(chain string)                                  {}
+func (Noop) BlockScanned(chain string)                              {}
+func (Noop) ObserveRPCLatency(chain string, d time.Duration)        {}
+func (Noop) ObserveBlockScanDuration(chain string, d time.Duration) {}
+func (Noop) SetPendingComponents(n int)                             {}
+func (Noop) SetDatabaseHealthy(healthy bool)                        {}
+func (Noop) SetChainRunning(chain string, running bool)             {}
+func (Noop) SetHeadLag(chain string, lag int64)                     {}
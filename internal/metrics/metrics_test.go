@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusHandlerExposesRegisteredMetrics(t *testing.T) {
+	p := NewPrometheus()
+
+	p.RValuesSeen("ETH", 3)
+	p.CollisionDetected()
+	p.KeyRecovered()
+	p.NonceRecovered()
+	p.RPCError("ETH")
+	p.BlockScanned("ETH")
+	p.ObserveRPCLatency("ETH", 50*time.Millisecond)
+	p.ObserveBlockScanDuration("ETH", 200*time.Millisecond)
+	p.SetPendingComponents(2)
+	p.SetDatabaseHealthy(true)
+	p.SetChainRunning("ETH", true)
+	p.SetHeadLag("ETH", 5)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"ecdsa_rvalues_seen_total",
+		"ecdsa_collisions_total 1",
+		"ecdsa_keys_recovered_total 1",
+		"ecdsa_nonces_recovered_total 1",
+		"ecdsa_rpc_errors_total",
+		"ecdsa_blocks_scanned_total",
+		"ecdsa_rpc_latency_seconds",
+		"ecdsa_block_scan_duration_seconds",
+		"ecdsa_pending_components 2",
+		"ecdsa_database_healthy 1",
+		`ecdsa_chain_running{chain="ETH"} 1`,
+		`ecdsa_head_lag_blocks{chain="ETH"} 5`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected /metrics output to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestPrometheusHandlerOmitsGoMetricsUnlessRequested(t *testing.T) {
+	p := NewPrometheus()
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, req)
+	if strings.Contains(rec.Body.String(), "go_goroutines") {
+		t.Error("expected Go runtime metrics to be omitted by default")
+	}
+
+	req = httptest.NewRequest("GET", "/metrics?go_metrics=1", nil)
+	rec = httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), "go_goroutines") {
+		t.Error("expected Go runtime metrics when go_metrics=1 is passed")
+	}
+}
@@ -0,0 +1,205 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric name this package registers, matching the
+// ecdsa_ prefix requests.jsonl's chunk6-2 asked for.
+const namespace = "ecdsa"
+
+// Prometheus implements Metrics on top of client_golang, registered against
+// a private registry rather than prometheus.DefaultRegisterer - an app
+// embedding this package shouldn't silently pick up whatever else also
+// called MustRegister against the global default. Go runtime/process
+// metrics are registered on a separate registry and only gathered when
+// Handler sees the go_metrics query param, so /metrics stays focused on
+// ecdsa_* series unless an operator explicitly asks for more.
+type Prometheus struct {
+	registry        *prometheus.Registry
+	runtimeRegistry *prometheus.Registry
+
+	rValuesSeen     *prometheus.CounterVec
+	collisions      prometheus.Counter
+	keysRecovered   prometheus.Counter
+	noncesRecovered prometheus.Counter
+	rpcErrors       *prometheus.CounterVec
+	blocksScanned   *prometheus.CounterVec
+	rpcLatency      *prometheus.HistogramVec
+	blockScanTime   *prometheus.HistogramVec
+	pendingComps    prometheus.Gauge
+	databaseHealthy prometheus.Gauge
+	chainRunning    *prometheus.GaugeVec
+	headLag         *prometheus.GaugeVec
+}
+
+var _ Metrics = (*Prometheus)(nil)
+
+// NewPrometheus builds a Prometheus collector with every metric registered
+// against its own private registry, ready to be mounted via Handler.
+func NewPrometheus() *Prometheus {
+	registry := prometheus.NewRegistry()
+
+	p := &Prometheus{
+		registry:        registry,
+		runtimeRegistry: prometheus.NewRegistry(),
+		rValuesSeen: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rvalues_seen_total",
+			Help:      "Total R-values observed, per chain.",
+		}, []string{"chain"}),
+		collisions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "collisions_total",
+			Help:      "Total R-value collisions detected, same-key or cross-key.",
+		}),
+		keysRecovered: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "keys_recovered_total",
+			Help:      "Total private keys recovered.",
+		}),
+		noncesRecovered: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "nonces_recovered_total",
+			Help:      "Total signature nonces derived.",
+		}),
+		rpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_errors_total",
+			Help:      "Total RPC errors, per chain.",
+		}, []string{"chain"}),
+		blocksScanned: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "blocks_scanned_total",
+			Help:      "Total blocks scanned, per chain.",
+		}, []string{"chain"}),
+		rpcLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_latency_seconds",
+			Help:      "RPC call latency, per chain.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"chain"}),
+		blockScanTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "block_scan_duration_seconds",
+			Help:      "Time to scan a single block (fetch through collision-check), per chain.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"chain"}),
+		pendingComps: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "pending_components",
+			Help:      "Current count of unsolved cross-key pending components.",
+		}),
+		databaseHealthy: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "database_healthy",
+			Help:      "1 if the last database health check succeeded, 0 otherwise.",
+		}),
+		chainRunning: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "chain_running",
+			Help:      "1 if the chain's scanner loop is currently running, 0 otherwise.",
+		}, []string{"chain"}),
+		headLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "head_lag_blocks",
+			Help:      "Chain's latest known block minus the last block fully scanned.",
+		}, []string{"chain"}),
+	}
+
+	registry.MustRegister(
+		p.rValuesSeen,
+		p.collisions,
+		p.keysRecovered,
+		p.noncesRecovered,
+		p.rpcErrors,
+		p.blocksScanned,
+		p.rpcLatency,
+		p.blockScanTime,
+		p.pendingComps,
+		p.databaseHealthy,
+		p.chainRunning,
+		p.headLag,
+	)
+	p.runtimeRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return p
+}
+
+func (p *Prometheus) RValuesSeen(chain string, n int) {
+	p.rValuesSeen.WithLabelValues(chain).Add(float64(n))
+}
+
+func (p *Prometheus) CollisionDetected() {
+	p.collisions.Inc()
+}
+
+func (p *Prometheus) KeyRecovered() {
+	p.keysRecovered.Inc()
+}
+
+func (p *Prometheus) NonceRecovered() {
+	p.noncesRecovered.Inc()
+}
+
+func (p *Prometheus) RPCError(chain string) {
+	p.rpcErrors.WithLabelValues(chain).Inc()
+}
+
+func (p *Prometheus) BlockScanned(chain string) {
+	p.blocksScanned.WithLabelValues(chain).Inc()
+}
+
+func (p *Prometheus) ObserveRPCLatency(chain string, d time.Duration) {
+	p.rpcLatency.WithLabelValues(chain).Observe(d.Seconds())
+}
+
+func (p *Prometheus) ObserveBlockScanDuration(chain string, d time.Duration) {
+	p.blockScanTime.WithLabelValues(chain).Observe(d.Seconds())
+}
+
+func (p *Prometheus) SetPendingComponents(n int) {
+	p.pendingComps.Set(float64(n))
+}
+
+func (p *Prometheus) SetDatabaseHealthy(healthy bool) {
+	if healthy {
+		p.databaseHealthy.Set(1)
+	} else {
+		p.databaseHealthy.Set(0)
+	}
+}
+
+func (p *Prometheus) SetChainRunning(chain string, running bool) {
+	if running {
+		p.chainRunning.WithLabelValues(chain).Set(1)
+	} else {
+		p.chainRunning.WithLabelValues(chain).Set(0)
+	}
+}
+
+func (p *Prometheus) SetHeadLag(chain string, lag int64) {
+	p.headLag.WithLabelValues(chain).Set(float64(lag))
+}
+
+// Handler returns the http.Handler /metrics should mount. It always
+// gathers the ecdsa_* application metrics; passing ?go_metrics=1 also
+// gathers the Go runtime and process metrics, for operators who want them
+// without forcing their cost and cardinality on every scrape.
+func (p *Prometheus) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gatherers := prometheus.Gatherers{p.registry}
+		if r.URL.Query().Get("go_metrics") == "1" {
+			gatherers = append(gatherers, p.runtimeRegistry)
+		}
+		promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DiscordConfig holds a webhook URL for a Discord channel.
+type DiscordConfig struct {
+	WebhookURL string
+}
+
+// DiscordTransport posts events to a Discord webhook.
+type DiscordTransport struct {
+	webhookURL string
+	client     *http.Client
+}
+
+func newDiscordTransport(cfg DiscordConfig) *DiscordTransport {
+	if cfg.WebhookURL == "" {
+		return nil
+	}
+	return &DiscordTransport{webhookURL: cfg.WebhookURL, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name implements Transport.
+func (t *DiscordTransport) Name() string { return "discord" }
+
+// Send implements Transport.
+func (t *DiscordTransport) Send(ctx context.Context, event Event) error {
+	title, body := event.render()
+	return postJSON(ctx, t.client, t.webhookURL, map[string]string{
+		"content": fmt.Sprintf("**%s**\n%s", title, body),
+	})
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+)
+
+// EmailConfig holds SMTP settings for EmailTransport. Username/Password are
+// optional; left empty, Send authenticates with no credentials (for relays
+// that allow anonymous submission on a trusted network).
+type EmailConfig struct {
+	SMTPHost string
+	SMTPPort string
+	From     string
+	To       string
+	Username string
+	Password string
+}
+
+// EmailTransport sends events as plain-text email via SMTP.
+type EmailTransport struct {
+	host, port, from, to, username, password string
+}
+
+func newEmailTransport(cfg EmailConfig) *EmailTransport {
+	if cfg.SMTPHost == "" || cfg.From == "" || cfg.To == "" {
+		return nil
+	}
+	port := cfg.SMTPPort
+	if port == "" {
+		port = "587"
+	}
+	return &EmailTransport{
+		host:     cfg.SMTPHost,
+		port:     port,
+		from:     cfg.From,
+		to:       cfg.To,
+		username: cfg.Username,
+		password: cfg.Password,
+	}
+}
+
+// Name implements Transport.
+func (t *EmailTransport) Name() string { return "email" }
+
+// Send implements Transport. The standard library's net/smtp predates
+// context support, so ctx isn't used for cancellation here - it's only part
+// of the signature to satisfy Transport.
+func (t *EmailTransport) Send(_ context.Context, event Event) error {
+	title, body := event.render()
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n", t.from, t.to, title, body)
+
+	var auth smtp.Auth
+	if t.username != "" {
+		auth = smtp.PlainAuth("", t.username, t.password, t.host)
+	}
+
+	addr := net.JoinHostPort(t.host, t.port)
+	if err := smtp.SendMail(addr, auth, t.from, []string{t.to}, []byte(msg)); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+// Package notify fans out scanner events to one or more outbound
+// notification transports (Pushover, Slack, Discord, Telegram, generic
+// webhooks, email), routed by event kind and severity.
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EventKind identifies what happened, so a Transport can render it and a
+// RoutingRule can match on it.
+type EventKind string
+
+const (
+	// EventCollision fires whenever two transactions share an R-value,
+	// same-key or cross-key.
+	EventCollision EventKind = "collision"
+	// EventKeyRecovered fires once a private key has actually been
+	// recovered from a collision.
+	EventKeyRecovered EventKind = "key_recovered"
+	// EventTest is used by SendTest to verify transports are reachable.
+	EventTest EventKind = "test"
+)
+
+// Severity orders events so a RoutingRule can set a minimum threshold (e.g.
+// "page on-call for critical, post warnings to a research channel").
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityCritical:
+		return "critical"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// ParseSeverity parses the case-insensitive names used in routing-rule
+// files. An empty string is treated as SeverityInfo.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "", "info":
+		return SeverityInfo, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "critical":
+		return SeverityCritical, nil
+	default:
+		return 0, fmt.Errorf("notify: unknown severity %q", s)
+	}
+}
+
+// UnmarshalText lets Severity be written as a name ("critical") in both the
+// JSON and YAML routing-rule formats RulesPath accepts.
+func (s *Severity) UnmarshalText(text []byte) error {
+	v, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText is the inverse of UnmarshalText.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Event is the structured record of something notification-worthy. It's
+// handed to every Transport a RoutingRule matches, so each can render it
+// however fits its destination instead of receiving a pre-formatted string.
+type Event struct {
+	Kind      EventKind `json:"event_kind"`
+	Severity  Severity  `json:"severity"`
+	ChainID   int       `json:"chain_id,omitempty"`
+	ChainName string    `json:"chain_name,omitempty"`
+	Address   string    `json:"address,omitempty"`
+	RValue    string    `json:"r_value,omitempty"`
+	TxCount   int       `json:"tx_count,omitempty"`
+	IsSameKey bool      `json:"is_same_key,omitempty"`
+	// Message is an extra human-readable line. render() uses it as the body
+	// for EventTest and any other kind without its own template; the
+	// collision and key_recovered bodies don't include it.
+	Message string `json:"message,omitempty"`
+}
+
+// render builds the title/body pair the text-based transports (Pushover,
+// Slack, Discord, Telegram, Email) display. WebhookTransport skips this and
+// POSTs the Event itself.
+func (e Event) render() (title, body string) {
+	switch e.Kind {
+	case EventKeyRecovered:
+		return "🔑 Private Key Recovered!", fmt.Sprintf("Address: %s\nChain: %s\nTransactions: %d",
+			shortenAddress(e.Address), e.ChainName, e.TxCount)
+	case EventCollision:
+		if e.IsSameKey {
+			return "⚠️ Same-Key Nonce Reuse Detected", fmt.Sprintf("Address: %s\nChain ID: %d\nR-value: %s",
+				shortenAddress(e.Address), e.ChainID, shortenHash(e.RValue))
+		}
+		return "🔄 Cross-Key R-Value Collision", fmt.Sprintf("Chain ID: %d\nR-value: %s",
+			e.ChainID, shortenHash(e.RValue))
+	default:
+		return "ecdsa-scanner notification", e.Message
+	}
+}
+
+// Transport delivers an Event to one destination (a Pushover account, a
+// Slack channel, an email address, ...).
+type Transport interface {
+	// Name identifies the transport in RoutingRule.Transports.
+	Name() string
+	Send(ctx context.Context, event Event) error
+}
+
+// RoutingRule routes events of at least MinSeverity - and, if EventKind is
+// set, of that specific kind - to a list of transports by name. Rules are
+// evaluated independently: an event can match more than one rule, and the
+// union of their transports all receive it once.
+type RoutingRule struct {
+	// EventKind restricts the rule to one kind of event. Left empty, the
+	// rule matches every kind.
+	EventKind   EventKind `json:"event_kind,omitempty" yaml:"event_kind,omitempty"`
+	MinSeverity Severity  `json:"min_severity" yaml:"min_severity"`
+	Transports  []string  `json:"transports" yaml:"transports"`
+}
+
+// Config configures New. A transport's config struct left at its zero value
+// means that transport isn't constructed at all - Notifier works fine with
+// zero transports, same as the old Pushover-only Notifier did when its
+// token/key were blank.
+type Config struct {
+	Pushover PushoverConfig
+	Slack    SlackConfig
+	Discord  DiscordConfig
+	Telegram TelegramConfig
+	Webhook  WebhookConfig
+	Email    EmailConfig
+
+	// RulesPath is an optional path to a YAML (default) or JSON (if the
+	// path ends in ".json") file of RoutingRules. Left empty, Notifier uses
+	// a single rule that sends every event to every configured transport,
+	// matching the pre-router behavior.
+	RulesPath string
+}
+
+// Notifier fans events out to a set of transports according to rules.
+type Notifier struct {
+	transports map[string]Transport
+	rules      []RoutingRule
+}
+
+// New builds a Notifier from cfg. It only fails if RulesPath is set but
+// can't be read or parsed; a Config with no transports configured at all is
+// valid and simply means IsEnabled reports false.
+func New(cfg Config) (*Notifier, error) {
+	n := &Notifier{transports: make(map[string]Transport)}
+
+	// Each newXTransport returns a nil *XTransport when its config is
+	// incomplete; the nil check has to happen on the concrete pointer here,
+	// before it's boxed into the Transport interface, or it'd never be nil.
+	if t := newPushoverTransport(cfg.Pushover); t != nil {
+		n.transports[t.Name()] = t
+	}
+	if t := newSlackTransport(cfg.Slack); t != nil {
+		n.transports[t.Name()] = t
+	}
+	if t := newDiscordTransport(cfg.Discord); t != nil {
+		n.transports[t.Name()] = t
+	}
+	if t := newTelegramTransport(cfg.Telegram); t != nil {
+		n.transports[t.Name()] = t
+	}
+	if t := newWebhookTransport(cfg.Webhook); t != nil {
+		n.transports[t.Name()] = t
+	}
+	if t := newEmailTransport(cfg.Email); t != nil {
+		n.transports[t.Name()] = t
+	}
+
+	if cfg.RulesPath == "" {
+		n.rules = n.defaultRules()
+	} else {
+		rules, err := loadRoutingRules(cfg.RulesPath)
+		if err != nil {
+			return nil, err
+		}
+		n.rules = rules
+	}
+
+	return n, nil
+}
+
+// defaultRules sends every event to every configured transport, which is
+// how the notifier behaved before RulesPath existed.
+func (n *Notifier) defaultRules() []RoutingRule {
+	names := make([]string, 0, len(n.transports))
+	for name := range n.transports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return []RoutingRule{{MinSeverity: SeverityInfo, Transports: names}}
+}
+
+func loadRoutingRules(path string) ([]RoutingRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("notify: read routing rules: %w", err)
+	}
+
+	var rules []RoutingRule
+	if strings.HasSuffix(path, ".json") {
+		err = json.Unmarshal(data, &rules)
+	} else {
+		err = yaml.Unmarshal(data, &rules)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("notify: parse routing rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// IsEnabled returns whether at least one transport is configured.
+func (n *Notifier) IsEnabled() bool {
+	return len(n.transports) > 0
+}
+
+// route sends event to every transport matched by at least one rule,
+// collecting rather than stopping on per-transport errors so one bad
+// destination doesn't suppress delivery to the others.
+func (n *Notifier) route(ctx context.Context, event Event) error {
+	seen := make(map[string]bool)
+	var errs []error
+
+	for _, rule := range n.rules {
+		if rule.EventKind != "" && rule.EventKind != event.Kind {
+			continue
+		}
+		if event.Severity < rule.MinSeverity {
+			continue
+		}
+		for _, name := range rule.Transports {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			t, ok := n.transports[name]
+			if !ok {
+				continue
+			}
+			if err := t.Send(ctx, event); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return fmt.Errorf("notify: %s", strings.Join(msgs, "; "))
+}
+
+// NotifyKeyRecovered sends a critical-severity notification for a recovered
+// private key.
+func (n *Notifier) NotifyKeyRecovered(address, chainName string, txCount int) error {
+	return n.route(context.Background(), Event{
+		Kind:      EventKeyRecovered,
+		Severity:  SeverityCritical,
+		Address:   address,
+		ChainName: chainName,
+		TxCount:   txCount,
+	})
+}
+
+// NotifyCollision sends a notification for an R-value collision. Same-key
+// reuse is critical severity (it's immediately actionable); a cross-key
+// collision without a known nonce yet is a warning.
+func (n *Notifier) NotifyCollision(rValue, address string, chainID int, isSameKey bool) error {
+	severity := SeverityWarning
+	if isSameKey {
+		severity = SeverityCritical
+	}
+	return n.route(context.Background(), Event{
+		Kind:      EventCollision,
+		Severity:  severity,
+		ChainID:   chainID,
+		Address:   address,
+		RValue:    rValue,
+		IsSameKey: isSameKey,
+	})
+}
+
+// SendTest routes an info-severity EventTest through the routing rules like
+// any other event, so an operator can verify their routing rules actually
+// reach each destination; a transport no rule matches doesn't receive it.
+func (n *Notifier) SendTest() error {
+	return n.route(context.Background(), Event{
+		Kind:     EventTest,
+		Severity: SeverityInfo,
+		Message:  "Test notification from ecdsa-scanner",
+	})
+}
+
+// shortenAddress returns a shortened address (0x1234...5678)
+func shortenAddress(addr string) string {
+	addr = strings.ToLower(addr)
+	if len(addr) > 14 {
+		return addr[:8] + "..." + addr[len(addr)-6:]
+	}
+	return addr
+}
+
+// shortenHash returns a shortened hash
+func shortenHash(hash string) string {
+	if len(hash) > 18 {
+		return hash[:18] + "..."
+	}
+	return hash
+}
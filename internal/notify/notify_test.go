@@ -0,0 +1,155 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeTransport records every Event it receives, for asserting routing
+// behavior without making real network calls.
+type fakeTransport struct {
+	name     string
+	received []Event
+	err      error
+}
+
+func (f *fakeTransport) Name() string { return f.name }
+
+func (f *fakeTransport) Send(_ context.Context, event Event) error {
+	f.received = append(f.received, event)
+	return f.err
+}
+
+func TestNotifierDisabledWithNoConfig(t *testing.T) {
+	n, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if n.IsEnabled() {
+		t.Error("Expected notifier to be disabled with no transports configured")
+	}
+	if err := n.NotifyCollision("0xabc", "0xaddr", 1, false); err != nil {
+		t.Errorf("Expected no error routing with zero transports, got: %v", err)
+	}
+}
+
+func TestNotifierEnabledWithPushover(t *testing.T) {
+	n, err := New(Config{Pushover: PushoverConfig{AppToken: "t", UserKey: "u"}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if !n.IsEnabled() {
+		t.Error("Expected notifier to be enabled with Pushover credentials")
+	}
+}
+
+func TestNotifierRoutesBySeverity(t *testing.T) {
+	critical := &fakeTransport{name: "critical-sink"}
+	everything := &fakeTransport{name: "everything-sink"}
+
+	n := &Notifier{
+		transports: map[string]Transport{
+			"critical-sink":   critical,
+			"everything-sink": everything,
+		},
+		rules: []RoutingRule{
+			{MinSeverity: SeverityCritical, Transports: []string{"critical-sink"}},
+			{MinSeverity: SeverityInfo, Transports: []string{"everything-sink"}},
+		},
+	}
+
+	if err := n.NotifyCollision("0xabc", "0xaddr", 1, false); err != nil { // warning severity
+		t.Fatalf("NotifyCollision failed: %v", err)
+	}
+	if err := n.NotifyCollision("0xdef", "0xaddr2", 1, true); err != nil { // critical severity
+		t.Fatalf("NotifyCollision failed: %v", err)
+	}
+
+	if len(critical.received) != 1 {
+		t.Errorf("Expected critical-sink to receive 1 event, got %d", len(critical.received))
+	}
+	if len(everything.received) != 2 {
+		t.Errorf("Expected everything-sink to receive 2 events, got %d", len(everything.received))
+	}
+}
+
+func TestNotifierRoutesByEventKind(t *testing.T) {
+	recoveries := &fakeTransport{name: "recoveries"}
+	n := &Notifier{
+		transports: map[string]Transport{"recoveries": recoveries},
+		rules:      []RoutingRule{{EventKind: EventKeyRecovered, MinSeverity: SeverityInfo, Transports: []string{"recoveries"}}},
+	}
+
+	if err := n.NotifyCollision("0xabc", "0xaddr", 1, false); err != nil {
+		t.Fatalf("NotifyCollision failed: %v", err)
+	}
+	if len(recoveries.received) != 0 {
+		t.Error("Expected the collision event not to match a key_recovered-only rule")
+	}
+
+	if err := n.NotifyKeyRecovered("0xaddr", "Ethereum", 2); err != nil {
+		t.Fatalf("NotifyKeyRecovered failed: %v", err)
+	}
+	if len(recoveries.received) != 1 {
+		t.Error("Expected the key_recovered event to match")
+	}
+}
+
+func TestNotifierCollectsTransportErrors(t *testing.T) {
+	failing := &fakeTransport{name: "failing", err: errors.New("boom")}
+	n := &Notifier{
+		transports: map[string]Transport{"failing": failing},
+		rules:      []RoutingRule{{MinSeverity: SeverityInfo, Transports: []string{"failing"}}},
+	}
+
+	if err := n.SendTest(); err == nil {
+		t.Error("Expected an error when a transport fails")
+	}
+}
+
+func TestLoadRoutingRulesYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	contents := `
+- event_kind: key_recovered
+  min_severity: critical
+  transports: [pushover]
+- min_severity: warning
+  transports: [slack, webhook]
+`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	rules, err := loadRoutingRules(path)
+	if err != nil {
+		t.Fatalf("loadRoutingRules failed: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+	if rules[0].EventKind != EventKeyRecovered || rules[0].MinSeverity != SeverityCritical {
+		t.Errorf("Unexpected first rule: %+v", rules[0])
+	}
+	if len(rules[1].Transports) != 2 {
+		t.Errorf("Expected 2 transports in second rule, got %+v", rules[1].Transports)
+	}
+}
+
+func TestLoadRoutingRulesJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	contents := `[{"min_severity": "info", "transports": ["email"]}]`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	rules, err := loadRoutingRules(path)
+	if err != nil {
+		t.Fatalf("loadRoutingRules failed: %v", err)
+	}
+	if len(rules) != 1 || rules[0].Transports[0] != "email" {
+		t.Errorf("Unexpected rules: %+v", rules)
+	}
+}
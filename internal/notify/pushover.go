@@ -1,6 +1,7 @@
 package notify
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -19,48 +20,44 @@ const (
 	PriorityEmergency = 2
 )
 
-// Notifier sends push notifications
-type Notifier struct {
-	appToken  string
-	userKey   string
-	enabled   bool
-	client    *http.Client
+// PushoverConfig holds Pushover credentials. Either field left empty means
+// the Pushover transport isn't constructed.
+type PushoverConfig struct {
+	AppToken string
+	UserKey  string
 }
 
-// New creates a new Pushover notifier
-// If appToken or userKey is empty, notifications are disabled
-func New(appToken, userKey string) *Notifier {
-	return &Notifier{
-		appToken: appToken,
-		userKey:  userKey,
-		enabled:  appToken != "" && userKey != "",
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-	}
+// PushoverTransport sends events as Pushover push notifications.
+type PushoverTransport struct {
+	appToken string
+	userKey  string
+	client   *http.Client
 }
 
-// IsEnabled returns whether notifications are enabled
-func (n *Notifier) IsEnabled() bool {
-	return n.enabled
+func newPushoverTransport(cfg PushoverConfig) *PushoverTransport {
+	if cfg.AppToken == "" || cfg.UserKey == "" {
+		return nil
+	}
+	return &PushoverTransport{
+		appToken: cfg.AppToken,
+		userKey:  cfg.UserKey,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
 }
 
-// Send sends a notification with normal priority
-func (n *Notifier) Send(title, message string) error {
-	return n.SendWithPriority(title, message, PriorityNormal)
-}
+// Name implements Transport.
+func (t *PushoverTransport) Name() string { return "pushover" }
 
-// SendWithPriority sends a notification with specified priority
-func (n *Notifier) SendWithPriority(title, message string, priority int) error {
-	if !n.enabled {
-		return nil
-	}
+// Send implements Transport.
+func (t *PushoverTransport) Send(ctx context.Context, event Event) error {
+	title, body := event.render()
+	priority := pushoverPriority(event.Severity)
 
 	data := url.Values{}
-	data.Set("token", n.appToken)
-	data.Set("user", n.userKey)
+	data.Set("token", t.appToken)
+	data.Set("user", t.userKey)
 	data.Set("title", title)
-	data.Set("message", message)
+	data.Set("message", body)
 	data.Set("priority", fmt.Sprintf("%d", priority))
 
 	// Emergency priority requires retry and expire parameters
@@ -69,7 +66,13 @@ func (n *Notifier) SendWithPriority(title, message string, priority int) error {
 		data.Set("expire", "3600")
 	}
 
-	resp, err := n.client.PostForm(pushoverAPI, data)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pushoverAPI, strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("pushover request failed: %w", err)
 	}
@@ -78,46 +81,20 @@ func (n *Notifier) SendWithPriority(title, message string, priority int) error {
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("pushover returned status %d", resp.StatusCode)
 	}
-
 	return nil
 }
 
-// NotifyKeyRecovered sends a high-priority notification for key recovery
-func (n *Notifier) NotifyKeyRecovered(address, chainName string, txCount int) error {
-	title := "🔑 Private Key Recovered!"
-	message := fmt.Sprintf("Address: %s\nChain: %s\nTransactions: %d",
-		shortenAddress(address), chainName, txCount)
-	return n.SendWithPriority(title, message, PriorityHigh)
-}
-
-// NotifyCollision sends a normal-priority notification for R-value collision
-func (n *Notifier) NotifyCollision(rValue, address string, chainID int, isSameKey bool) error {
-	var title, message string
-	if isSameKey {
-		title = "⚠️ Same-Key Nonce Reuse Detected"
-		message = fmt.Sprintf("Address: %s\nChain ID: %d\nR-value: %s",
-			shortenAddress(address), chainID, shortenHash(rValue))
-	} else {
-		title = "🔄 Cross-Key R-Value Collision"
-		message = fmt.Sprintf("Chain ID: %d\nR-value: %s",
-			chainID, shortenHash(rValue))
-	}
-	return n.Send(title, message)
-}
-
-// shortenAddress returns a shortened address (0x1234...5678)
-func shortenAddress(addr string) string {
-	addr = strings.ToLower(addr)
-	if len(addr) > 14 {
-		return addr[:8] + "..." + addr[len(addr)-6:]
-	}
-	return addr
-}
-
-// shortenHash returns a shortened hash
-func shortenHash(hash string) string {
-	if len(hash) > 18 {
-		return hash[:18] + "..."
+// pushoverPriority maps a Severity onto a Pushover priority level. It
+// doesn't use PriorityEmergency for SeverityCritical: that priority repeats
+// the notification and requires acknowledgement, which is a per-destination
+// policy decision, not something Severity alone should trigger.
+func pushoverPriority(s Severity) int {
+	switch s {
+	case SeverityCritical:
+		return PriorityHigh
+	case SeverityWarning:
+		return PriorityNormal
+	default:
+		return PriorityLow
 	}
-	return hash
 }
@@ -2,22 +2,38 @@ package notify
 
 import "testing"
 
-func TestNotifierDisabledWhenNoCredentials(t *testing.T) {
-	n := New("", "")
-	if n.IsEnabled() {
-		t.Error("Expected notifier to be disabled with empty credentials")
+func TestPushoverTransportNilWhenNoCredentials(t *testing.T) {
+	if newPushoverTransport(PushoverConfig{}) != nil {
+		t.Error("Expected nil transport with empty credentials")
 	}
+	if newPushoverTransport(PushoverConfig{AppToken: "app-token"}) != nil {
+		t.Error("Expected nil transport with only an app token")
+	}
+}
 
-	// Should not error when disabled
-	if err := n.Send("test", "message"); err != nil {
-		t.Errorf("Expected no error when disabled, got: %v", err)
+func TestPushoverTransportConstructedWithCredentials(t *testing.T) {
+	tr := newPushoverTransport(PushoverConfig{AppToken: "app-token", UserKey: "user-key"})
+	if tr == nil {
+		t.Fatal("Expected a transport with both credentials set")
+	}
+	if tr.Name() != "pushover" {
+		t.Errorf("Expected name %q, got %q", "pushover", tr.Name())
 	}
 }
 
-func TestNotifierEnabledWithCredentials(t *testing.T) {
-	n := New("app-token", "user-key")
-	if !n.IsEnabled() {
-		t.Error("Expected notifier to be enabled with credentials")
+func TestPushoverPriority(t *testing.T) {
+	tests := []struct {
+		severity Severity
+		expected int
+	}{
+		{SeverityInfo, PriorityLow},
+		{SeverityWarning, PriorityNormal},
+		{SeverityCritical, PriorityHigh},
+	}
+	for _, tt := range tests {
+		if got := pushoverPriority(tt.severity); got != tt.expected {
+			t.Errorf("pushoverPriority(%v) = %d, want %d", tt.severity, got, tt.expected)
+		}
 	}
 }
 
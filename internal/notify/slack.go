@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SlackConfig holds an incoming-webhook URL for a Slack channel.
+type SlackConfig struct {
+	WebhookURL string
+}
+
+// SlackTransport posts events to a Slack incoming webhook.
+type SlackTransport struct {
+	webhookURL string
+	client     *http.Client
+}
+
+func newSlackTransport(cfg SlackConfig) *SlackTransport {
+	if cfg.WebhookURL == "" {
+		return nil
+	}
+	return &SlackTransport{webhookURL: cfg.WebhookURL, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name implements Transport.
+func (t *SlackTransport) Name() string { return "slack" }
+
+// Send implements Transport.
+func (t *SlackTransport) Send(ctx context.Context, event Event) error {
+	title, body := event.render()
+	return postJSON(ctx, t.client, t.webhookURL, map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", title, body),
+	})
+}
@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TelegramConfig holds a bot token and the chat to post to.
+type TelegramConfig struct {
+	BotToken string
+	ChatID   string
+}
+
+// TelegramTransport posts events via a Telegram bot's sendMessage API.
+type TelegramTransport struct {
+	botToken string
+	chatID   string
+	client   *http.Client
+}
+
+func newTelegramTransport(cfg TelegramConfig) *TelegramTransport {
+	if cfg.BotToken == "" || cfg.ChatID == "" {
+		return nil
+	}
+	return &TelegramTransport{botToken: cfg.BotToken, chatID: cfg.ChatID, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name implements Transport.
+func (t *TelegramTransport) Name() string { return "telegram" }
+
+// Send implements Transport.
+func (t *TelegramTransport) Send(ctx context.Context, event Event) error {
+	title, body := event.render()
+	api := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.botToken)
+	return postJSON(ctx, t.client, api, map[string]string{
+		"chat_id": t.chatID,
+		"text":    fmt.Sprintf("%s\n%s", title, body),
+	})
+}
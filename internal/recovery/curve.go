@@ -0,0 +1,63 @@
+package recovery
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Curve names a Weierstrass curve recovery's math can run over. The
+// nonce-reuse recovery, biased-nonce HNP lattice attack, and cross-key
+// LinearSystem solver never touch curve points directly - only the group
+// order n - so the same code already works for any elliptic.Curve, not
+// just secp256k1; Curve is what lets a caller pick which one.
+type Curve struct {
+	Name string
+	EC   elliptic.Curve
+}
+
+// N returns the curve's group order, the modulus every nonce-reuse
+// recovery and LinearSystem solve over this curve must reduce mod.
+func (c Curve) N() *big.Int {
+	return c.EC.Params().N
+}
+
+// BitLen returns the curve's group order's bit length, used to size a
+// private key's fixed-width byte encoding and to truncate an oversized
+// hash per TruncateHash.
+func (c Curve) BitLen() int {
+	return c.EC.Params().N.BitLen()
+}
+
+var (
+	// Secp256k1 is the curve Ethereum, Bitcoin, and most other chains this
+	// scanner targets sign with.
+	Secp256k1 = Curve{Name: "secp256k1", EC: crypto.S256()}
+	// P256, P384, and P521 are the NIST curves most commonly seen outside
+	// blockchains - TLS certificates and JWTs (ES256/384/512) - signed by
+	// the same r = (kG).x mod n, s = k^-1(z + rd) mod n construction, so a
+	// nonce reused there is recoverable the same way.
+	P256 = Curve{Name: "P-256", EC: elliptic.P256()}
+	P384 = Curve{Name: "P-384", EC: elliptic.P384()}
+	P521 = Curve{Name: "P-521", EC: elliptic.P521()}
+)
+
+// TruncateHash reduces hash to c's group order bit length per SEC1 4.1.3
+// step 5: when hash is longer than the order (e.g. SHA-384 over P-256),
+// z is the leftmost N.BitLen() bits of hash, interpreted as an integer.
+// Hashes no longer than the order are used whole, matching every existing
+// call site's plain new(big.Int).SetBytes(hash).
+func (c Curve) TruncateHash(hash []byte) *big.Int {
+	return truncateToBitLen(hash, c.BitLen())
+}
+
+// truncateToBitLen implements SEC1 4.1.3 step 5 / RFC 6979's bits2int: the
+// leftmost bitLen bits of data, interpreted as a big-endian integer.
+func truncateToBitLen(data []byte, bitLen int) *big.Int {
+	z := new(big.Int).SetBytes(data)
+	if excess := len(data)*8 - bitLen; excess > 0 {
+		z.Rsh(z, uint(excess))
+	}
+	return z
+}
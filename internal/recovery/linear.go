@@ -3,6 +3,7 @@ package recovery
 import (
 	"errors"
 	"math/big"
+	"strings"
 )
 
 // LinearSystem represents a system of linear equations over a finite field
@@ -128,6 +129,185 @@ func (ls *LinearSystem) Solve() (map[string]*big.Int, error) {
 	return result, nil
 }
 
+// SolvePartial reduces the system to reduced row-echelon form modulo n and
+// returns every variable that comes out fully isolated, even if the system
+// as a whole is under-determined. Unlike Solve, it never errors: a row whose
+// remaining entries are all zero mod n (the only non-invertible case, since
+// n is prime) is simply skipped rather than treated as a fatal "non-invertible
+// pivot" - the variables it would have pivoted on are just left unsolved.
+func (ls *LinearSystem) SolvePartial() map[string]*big.Int {
+	rows := len(ls.coeffs)
+	cols := len(ls.vars)
+	result := make(map[string]*big.Int)
+	if rows == 0 || cols == 0 {
+		return result
+	}
+
+	matrix := make([][]*big.Int, rows)
+	for i := range matrix {
+		matrix[i] = make([]*big.Int, cols+1)
+		for j := 0; j < cols; j++ {
+			matrix[i][j] = new(big.Int).Set(ls.coeffs[i][j])
+		}
+		matrix[i][cols] = new(big.Int).Set(ls.constants[i])
+	}
+
+	used := make([]bool, rows)
+	pivotRowOf := make([]int, cols)
+	for i := range pivotRowOf {
+		pivotRowOf[i] = -1
+	}
+
+	for col := 0; col < cols; col++ {
+		pivot := -1
+		var pivotInv *big.Int
+		for row := 0; row < rows; row++ {
+			if used[row] || matrix[row][col].Sign() == 0 {
+				continue
+			}
+			if inv := new(big.Int).ModInverse(matrix[row][col], ls.n); inv != nil {
+				pivot = row
+				pivotInv = inv
+				break
+			}
+			// Shares a factor with n - on secp256k1's prime order this can't
+			// actually happen for a nonzero residue, but treat it the same
+			// as a zero pivot and keep looking rather than erroring.
+		}
+		if pivot == -1 {
+			continue
+		}
+		used[pivot] = true
+		pivotRowOf[col] = pivot
+
+		for j := col; j <= cols; j++ {
+			matrix[pivot][j].Mul(matrix[pivot][j], pivotInv)
+			matrix[pivot][j].Mod(matrix[pivot][j], ls.n)
+		}
+
+		for row := 0; row < rows; row++ {
+			if row == pivot {
+				continue
+			}
+			if matrix[row][col].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Int).Set(matrix[row][col])
+			for j := col; j <= cols; j++ {
+				temp := new(big.Int).Mul(factor, matrix[pivot][j])
+				matrix[row][j].Sub(matrix[row][j], temp)
+				matrix[row][j].Mod(matrix[row][j], ls.n)
+			}
+		}
+	}
+
+	for col, row := range pivotRowOf {
+		if row == -1 {
+			continue
+		}
+		isolated := true
+		for c := 0; c < cols; c++ {
+			if c != col && matrix[row][c].Sign() != 0 {
+				isolated = false
+				break
+			}
+		}
+		if isolated {
+			result[ls.vars[col]] = new(big.Int).Set(matrix[row][cols])
+		}
+	}
+
+	return result
+}
+
+// ComponentSignature is one (r, s, z) triple pulled from a pending cross-key
+// collision component - the per-signature inputs to the equation
+// s*k - r*d = z (mod n), labeled with the RValue/Address its k/d variables
+// belong to.
+type ComponentSignature struct {
+	RValue  string
+	Address string
+	R, S, Z *big.Int
+}
+
+// SolveComponent builds the linear system for a pending component's
+// signatures, substitutes any already-known nonces/keys into it, and runs
+// SolvePartial to find whatever new nonces and keys that leaves solvable.
+// Signatures with r=0 or s=0 are malformed and rejected outright. known
+// nonces/keys are keyed the same way as ComponentSignature.RValue/Address.
+func SolveComponent(sigs []ComponentSignature, knownNonces, knownKeys map[string]*big.Int, n *big.Int) (nonces, keys map[string]*big.Int) {
+	ls := NewLinearSystem(n)
+	varIndex := make(map[string]int)
+	varOf := func(name string) int {
+		if idx, ok := varIndex[name]; ok {
+			return idx
+		}
+		idx := ls.AddVariable(name)
+		varIndex[name] = idx
+		return idx
+	}
+
+	valid := make([]ComponentSignature, 0, len(sigs))
+	for _, sig := range sigs {
+		if sig.R == nil || sig.S == nil || sig.Z == nil || sig.R.Sign() == 0 || sig.S.Sign() == 0 {
+			continue
+		}
+		valid = append(valid, sig)
+	}
+
+	// AddEquation sizes each row to the variable count at the time it's
+	// called, so every variable a row might reference has to exist before
+	// any row does - register them all up front in a first pass.
+	for _, sig := range valid {
+		if _, ok := knownNonces[sig.RValue]; !ok {
+			varOf("k:" + sig.RValue)
+		}
+		if _, ok := knownKeys[sig.Address]; !ok {
+			varOf("d:" + sig.Address)
+		}
+	}
+
+	for _, sig := range valid {
+		coeffs := make(map[int]*big.Int)
+		constant := new(big.Int).Set(sig.Z)
+
+		if k, ok := knownNonces[sig.RValue]; ok {
+			constant.Sub(constant, new(big.Int).Mul(sig.S, k))
+		} else {
+			coeffs[varOf("k:"+sig.RValue)] = sig.S
+		}
+
+		if d, ok := knownKeys[sig.Address]; ok {
+			constant.Add(constant, new(big.Int).Mul(sig.R, d))
+		} else {
+			coeffs[varOf("d:"+sig.Address)] = new(big.Int).Neg(sig.R)
+		}
+
+		if len(coeffs) == 0 {
+			// Both sides of this signature are already known; it has
+			// nothing left to teach the system.
+			continue
+		}
+		ls.AddEquation(coeffs, constant)
+	}
+
+	nonces = make(map[string]*big.Int)
+	keys = make(map[string]*big.Int)
+	if ls.NumVariables() == 0 {
+		return nonces, keys
+	}
+
+	for name, val := range ls.SolvePartial() {
+		switch {
+		case strings.HasPrefix(name, "k:"):
+			nonces[strings.TrimPrefix(name, "k:")] = val
+		case strings.HasPrefix(name, "d:"):
+			keys[strings.TrimPrefix(name, "d:")] = val
+		}
+	}
+	return nonces, keys
+}
+
 // CanSolve returns true if the system has enough equations
 func (ls *LinearSystem) CanSolve() bool {
 	return len(ls.coeffs) >= len(ls.vars)
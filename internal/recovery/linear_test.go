@@ -184,3 +184,76 @@ func TestPropertyCounts(t *testing.T) {
 		}
 	})
 }
+
+// Property: SolveComponent recovers a key once enough of the component's
+// other unknowns are already known, even when the raw signature set alone
+// is under-determined (3 signatures sharing 2 R-values, 4 unknowns).
+func TestPropertySolveComponentSubstitutesKnownValues(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
+		privKeyA := genPrivateKey(t, rnd)
+		privKeyB := genPrivateKey(t, rnd)
+		k1 := genNonce(t, rnd)
+		k2 := genNonce(t, rnd)
+
+		hash1 := genMessageHash(t, rnd)
+		hash2 := genMessageHash(t, rnd)
+		hash3 := genMessageHash(t, rnd)
+
+		r1, s1 := signWithNonce(privKeyA, hash1, k1)
+		_, s2 := signWithNonce(privKeyB, hash2, k1)
+		r2, s3 := signWithNonce(privKeyA, hash3, k2)
+
+		sigs := []ComponentSignature{
+			{RValue: "r1", Address: "a", R: r1, S: s1, Z: new(big.Int).SetBytes(hash1)},
+			{RValue: "r1", Address: "b", R: r1, S: s2, Z: new(big.Int).SetBytes(hash2)},
+			{RValue: "r2", Address: "a", R: r2, S: s3, Z: new(big.Int).SetBytes(hash3)},
+		}
+
+		// With nothing known, 3 equations and 4 unknowns (k1, k2, dA, dB)
+		// can't determine anything.
+		nonces, keys := SolveComponent(sigs, nil, nil, secp256k1N)
+		if len(nonces) != 0 || len(keys) != 0 {
+			t.Fatalf("expected an under-determined component to solve nothing, got nonces=%v keys=%v", nonces, keys)
+		}
+
+		// Once dA is already known (e.g. recovered elsewhere), the first r1
+		// equation has a single unknown (k1), and k1 then gives dB from the
+		// second r1 equation. (The r2 equation likewise gives k2.)
+		knownKeys := map[string]*big.Int{"a": privKeyA.D}
+		nonces2, keys2 := SolveComponent(sigs, nil, knownKeys, secp256k1N)
+		if nonces2["r1"] == nil || nonces2["r1"].Cmp(k1) != 0 {
+			t.Fatalf("expected k1 to be derived once dA is known, got %v", nonces2["r1"])
+		}
+		if keys2["b"] == nil || keys2["b"].Cmp(privKeyB.D) != 0 {
+			t.Fatalf("expected dB to be derived once dA and k1 are known, got %v", keys2["b"])
+		}
+	})
+}
+
+// Property: signatures with r=0 or s=0 are rejected rather than fed into
+// the linear system (a malformed signature can't carry a legitimate
+// nonce/key equation).
+func TestPropertySolveComponentRejectsDegenerateSignatures(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+		k := genNonce(t, rnd)
+		hash := genMessageHash(t, rnd)
+		r, s := signWithNonce(privKey, hash, k)
+
+		sigs := []ComponentSignature{
+			{RValue: "r", Address: "a", R: r, S: s, Z: new(big.Int).SetBytes(hash)},
+			{RValue: "zero-r", Address: "b", R: big.NewInt(0), S: s, Z: big.NewInt(1)},
+			{RValue: "zero-s", Address: "c", R: r, S: big.NewInt(0), Z: big.NewInt(1)},
+		}
+
+		// The two degenerate signatures must not introduce any variables;
+		// with only a single good signature and nothing known, nothing is
+		// solvable.
+		nonces, keys := SolveComponent(sigs, nil, nil, secp256k1N)
+		if len(nonces) != 0 || len(keys) != 0 {
+			t.Fatalf("expected degenerate signatures to be ignored, got nonces=%v keys=%v", nonces, keys)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package recovery
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// RecoverFromNonceReuse implements the ECDSA nonce-reuse private-key attack
+// generically over curve: given two signatures (r, s1) and (r, s2) over
+// digests z1, z2 produced with the same nonce k,
+//
+//	k = (z1 - z2) * (s1 - s2)^-1 mod n
+//	d = (s1*k - z1) * r^-1 mod n
+//
+// Both signatures must carry the same r (the same k was reused) - that's
+// the whole premise of the attack, so it's checked here rather than left
+// to the caller.
+func RecoverFromNonceReuse(curve Curve, z1, r1, s1, z2, r2, s2 *big.Int) (*big.Int, error) {
+	return recoverFromNonceReuseN(curve.N(), z1, r1, s1, z2, r2, s2)
+}
+
+// recoverFromNonceReuseN is RecoverFromNonceReuse's math over a bare
+// modulus, for callers (StreamingSolver) that only carry the curve order
+// rather than a full Curve.
+func recoverFromNonceReuseN(n, z1, r1, s1, z2, r2, s2 *big.Int) (*big.Int, error) {
+	if r1.Cmp(r2) != 0 {
+		return nil, errors.New("recovery: signatures have different r values")
+	}
+	if s1.Cmp(s2) == 0 {
+		return nil, errors.New("recovery: signatures have identical s values")
+	}
+
+	zDiff := new(big.Int).Mod(new(big.Int).Sub(z1, z2), n)
+	sDiff := new(big.Int).Mod(new(big.Int).Sub(s1, s2), n)
+	sDiffInv := new(big.Int).ModInverse(sDiff, n)
+	if sDiffInv == nil {
+		return nil, errors.New("recovery: failed to compute modular inverse of s difference")
+	}
+	k := new(big.Int).Mod(new(big.Int).Mul(zDiff, sDiffInv), n)
+
+	return recoverScalarFromNonceN(n, z1, r1, s1, k)
+}
+
+// recoverScalarFromNonce solves d = (s*k - z) * r^-1 mod n, the shared
+// second half of RecoverFromNonceReuse and RecoverWithKnownNonceForCurve.
+func recoverScalarFromNonce(curve Curve, z, r, s, k *big.Int) (*big.Int, error) {
+	return recoverScalarFromNonceN(curve.N(), z, r, s, k)
+}
+
+// recoverScalarFromNonceN is recoverScalarFromNonce over a bare modulus.
+func recoverScalarFromNonceN(n, z, r, s, k *big.Int) (*big.Int, error) {
+	rInv := new(big.Int).ModInverse(r, n)
+	if rInv == nil {
+		return nil, errors.New("recovery: failed to compute modular inverse of r")
+	}
+	d := new(big.Int).Mul(s, k)
+	d.Sub(d, z)
+	d.Mul(d, rInv)
+	d.Mod(d, n)
+	if d.Sign() < 0 {
+		d.Add(d, n)
+	}
+	return d, nil
+}
+
+// RecoverWithKnownNonceForCurve recovers the private key scalar for a
+// single signature (r, s) over digest z, given that its nonce k is already
+// known - typically derived via DeriveNonceForCurve from a sibling
+// signature that reused it (cross-key recovery).
+func RecoverWithKnownNonceForCurve(curve Curve, z, r, s, k *big.Int) (*big.Int, error) {
+	return recoverScalarFromNonce(curve, z, r, s, k)
+}
+
+// DeriveNonceForCurve recovers the nonce k used to produce signature (r, s)
+// over digest z, given the private key scalar d that produced it:
+// k = (z + r*d) * s^-1 mod n.
+func DeriveNonceForCurve(curve Curve, z, r, s, d *big.Int) *big.Int {
+	n := curve.N()
+	sInv := new(big.Int).ModInverse(s, n)
+	k := new(big.Int).Mul(r, d)
+	k.Add(k, z)
+	k.Mul(k, sInv)
+	k.Mod(k, n)
+	return k
+}
+
+// privateKeyFromScalar builds an *ecdsa.PrivateKey for d on curve.
+// secp256k1 goes through crypto.ToECDSA so it stays byte-for-byte
+// identical to every existing EVM call site; other curves derive the
+// public point directly since crypto.ToECDSA assumes secp256k1.
+func privateKeyFromScalar(curve Curve, d *big.Int) (*ecdsa.PrivateKey, error) {
+	byteLen := (curve.BitLen() + 7) / 8
+	dBytes := d.FillBytes(make([]byte, byteLen))
+
+	if curve.EC == crypto.S256() {
+		return crypto.ToECDSA(dBytes)
+	}
+
+	priv := new(ecdsa.PrivateKey)
+	priv.PublicKey.Curve = curve.EC
+	priv.D = new(big.Int).Set(d)
+	priv.PublicKey.X, priv.PublicKey.Y = curve.EC.ScalarBaseMult(dBytes)
+	if priv.PublicKey.X.Sign() == 0 && priv.PublicKey.Y.Sign() == 0 {
+		return nil, errors.New("recovery: scalar produces point at infinity")
+	}
+	return priv, nil
+}
+
+// privateKeyFromHex decodes a 0x-prefixed or bare hex private key into an
+// *ecdsa.PrivateKey on curve.
+func privateKeyFromHex(curve Curve, privateKeyHex string) (*ecdsa.PrivateKey, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	return privateKeyFromScalar(curve, new(big.Int).SetBytes(b))
+}
+
+// hexPrivateKey encodes d as a 0x-prefixed, curve-byte-length-padded hex
+// private key, the format every call site in this package already expects.
+func hexPrivateKey(curve Curve, d *big.Int) (string, error) {
+	priv, err := privateKeyFromScalar(curve, d)
+	if err != nil {
+		return "", err
+	}
+	if curve.EC == crypto.S256() {
+		return "0x" + hex.EncodeToString(crypto.FromECDSA(priv)), nil
+	}
+	byteLen := (curve.BitLen() + 7) / 8
+	return "0x" + hex.EncodeToString(priv.D.FillBytes(make([]byte, byteLen))), nil
+}
+
+// parseHexScalar decodes a 0x-prefixed or bare hex string into a *big.Int,
+// for nonce/scalar inputs (RecoverWithKnownNonce's kHex) that aren't
+// themselves full private keys.
+func parseHexScalar(s string) (*big.Int, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// RecoverFromSignatures is the Secp256k1/Ethereum-address convenience
+// wrapper over RecoverFromNonceReuse, for callers (the cross-key scanner
+// path) that already have z/r/s as *big.Int and just want the hex private
+// key back.
+func RecoverFromSignatures(z1, r1, s1, z2, r2, s2 *big.Int) (string, error) {
+	d, err := RecoverFromNonceReuse(Secp256k1, z1, r1, s1, z2, r2, s2)
+	if err != nil {
+		return "", err
+	}
+	return hexPrivateKey(Secp256k1, d)
+}
+
+// RecoverWithKnownNonce is the Secp256k1 convenience wrapper over
+// RecoverWithKnownNonceForCurve, taking the nonce as hex since that's how
+// it's threaded through the cross-key recovery pipeline (DeriveNonce's
+// output).
+func RecoverWithKnownNonce(z, r, s *big.Int, kHex string) (string, error) {
+	k, err := parseHexScalar(kHex)
+	if err != nil {
+		return "", err
+	}
+	d, err := RecoverWithKnownNonceForCurve(Secp256k1, z, r, s, k)
+	if err != nil {
+		return "", err
+	}
+	return hexPrivateKey(Secp256k1, d)
+}
+
+// DeriveNonce is the Secp256k1 convenience wrapper over
+// DeriveNonceForCurve, taking the already-recovered private key as hex and
+// returning the nonce as hex for RecoverWithKnownNonce to consume.
+func DeriveNonce(z, r, s *big.Int, privateKeyHex string) string {
+	priv, err := privateKeyFromHex(Secp256k1, privateKeyHex)
+	if err != nil {
+		return ""
+	}
+	k := DeriveNonceForCurve(Secp256k1, z, r, s, priv.D)
+	return "0x" + hex.EncodeToString(k.FillBytes(make([]byte, (Secp256k1.BitLen()+7)/8)))
+}
@@ -15,10 +15,18 @@ import (
 )
 
 var (
-	// secp256k1 curve order
-	secp256k1N, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	// secp256k1 curve order, kept for existing callers that referenced this
+	// package var directly; Secp256k1.N() is the same value and the form new
+	// code should prefer.
+	secp256k1N = Secp256k1.N()
 )
 
+// CurveOrder returns the secp256k1 group order n, the modulus every
+// LinearSystem built over recovered signatures must use.
+func CurveOrder() *big.Int {
+	return new(big.Int).Set(secp256k1N)
+}
+
 // RecoveredKey holds information about a recovered private key
 type RecoveredKey struct {
 	Address    string `json:"address"`
@@ -73,101 +81,36 @@ func RecoverPrivateKey(ctx context.Context, rpcURL string, txHash1, txHash2 stri
 		return nil, errors.New("transactions have identical signatures")
 	}
 
-	// Recover the private key using the nonce reuse attack
-	// k = (z1 - z2) / (s1 - s2) mod n
-	// d = (s1 * k - z1) / r mod n
-
-	z1 := new(big.Int).SetBytes(sig1.SigningHash)
-	z2 := new(big.Int).SetBytes(sig2.SigningHash)
-	s1 := sig1.S
-	s2 := sig2.S
-	r := sig1.R
-
-	// Calculate k = (z1 - z2) * (s1 - s2)^(-1) mod n
-	zDiff := new(big.Int).Sub(z1, z2)
-	zDiff.Mod(zDiff, secp256k1N)
-
-	sDiff := new(big.Int).Sub(s1, s2)
-	sDiff.Mod(sDiff, secp256k1N)
-
-	sDiffInv := new(big.Int).ModInverse(sDiff, secp256k1N)
-	if sDiffInv == nil {
-		return nil, errors.New("failed to compute modular inverse of s difference")
-	}
-
-	k := new(big.Int).Mul(zDiff, sDiffInv)
-	k.Mod(k, secp256k1N)
+	// Recover the private key using the nonce reuse attack. Unlike public-key
+	// recovery from a single signature, this has exactly one solution - no
+	// negated-k retry needed.
+	z1 := Secp256k1.TruncateHash(sig1.SigningHash)
+	z2 := Secp256k1.TruncateHash(sig2.SigningHash)
 
-	// Calculate d = (s1 * k - z1) * r^(-1) mod n
-	rInv := new(big.Int).ModInverse(r, secp256k1N)
-	if rInv == nil {
-		return nil, errors.New("failed to compute modular inverse of r")
+	d, err := RecoverFromNonceReuse(Secp256k1, z1, sig1.R, sig1.S, z2, sig2.R, sig2.S)
+	if err != nil {
+		return nil, err
 	}
 
-	d := new(big.Int).Mul(s1, k)
-	d.Sub(d, z1)
-	d.Mul(d, rInv)
-	d.Mod(d, secp256k1N)
-
-	// Handle negative results
-	if d.Sign() < 0 {
-		d.Add(d, secp256k1N)
+	privKeyHex, err := hexPrivateKey(Secp256k1, d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create private key: %w", err)
 	}
 
-	// Verify the recovered key
-	privKey, err := crypto.ToECDSA(d.Bytes())
+	privKey, err := privateKeyFromHex(Secp256k1, privKeyHex)
 	if err != nil {
-		// Try with negated k (there are two possible k values)
-		k.Sub(secp256k1N, k)
-		d = new(big.Int).Mul(s1, k)
-		d.Sub(d, z1)
-		d.Mul(d, rInv)
-		d.Mod(d, secp256k1N)
-		if d.Sign() < 0 {
-			d.Add(d, secp256k1N)
-		}
-		privKey, err = crypto.ToECDSA(d.Bytes())
-		if err != nil {
-			return nil, fmt.Errorf("failed to create private key: %w", err)
-		}
+		return nil, fmt.Errorf("failed to create private key: %w", err)
 	}
 
 	// Verify the address matches
-	recoveredAddr := crypto.PubkeyToAddress(privKey.PublicKey)
-	if !strings.EqualFold(recoveredAddr.Hex(), from1) {
-		// Try with negated k
-		k.Sub(secp256k1N, k)
-		d = new(big.Int).Mul(s1, k)
-		d.Sub(d, z1)
-		d.Mul(d, rInv)
-		d.Mod(d, secp256k1N)
-		if d.Sign() < 0 {
-			d.Add(d, secp256k1N)
-		}
-
-		// Pad to 32 bytes
-		dBytes := make([]byte, 32)
-		dBytesTmp := d.Bytes()
-		copy(dBytes[32-len(dBytesTmp):], dBytesTmp)
-
-		privKey, err = crypto.ToECDSA(dBytes)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create private key (attempt 2): %w", err)
-		}
-
-		recoveredAddr = crypto.PubkeyToAddress(privKey.PublicKey)
-		if !strings.EqualFold(recoveredAddr.Hex(), from1) {
-			return nil, fmt.Errorf("recovered address %s does not match sender %s", recoveredAddr.Hex(), from1)
-		}
+	if !(EthereumIdentity{}).Matches(&privKey.PublicKey, from1) {
+		recoveredAddr, _ := (EthereumIdentity{}).From(&privKey.PublicKey)
+		return nil, fmt.Errorf("recovered address %s does not match sender %s", recoveredAddr, from1)
 	}
 
-	// Format private key as hex
-	privKeyBytes := crypto.FromECDSA(privKey)
-	privKeyHex := hex.EncodeToString(privKeyBytes)
-
 	return &RecoveredKey{
 		Address:    from1,
-		PrivateKey: "0x" + privKeyHex,
+		PrivateKey: privKeyHex,
 		RValue:     "0x" + sig1.R.Text(16),
 		TxHash1:    txHash1,
 		TxHash2:    txHash2,
@@ -210,21 +153,21 @@ func getTxSignature(ctx context.Context, client *ethclient.Client, txHashStr str
 	}, from.Hex(), nil
 }
 
-// VerifyPrivateKey verifies that a private key corresponds to an address
-func VerifyPrivateKey(privateKeyHex, expectedAddress string) bool {
-	privKeyHex := strings.TrimPrefix(privateKeyHex, "0x")
-	privKeyBytes, err := hex.DecodeString(privKeyHex)
-	if err != nil {
-		return false
-	}
-
-	privKey, err := crypto.ToECDSA(privKeyBytes)
+// VerifyPrivateKeyForCurve verifies that a private key on curve corresponds
+// to expectedID under identity, the curve/identity-parameterized form
+// VerifyPrivateKey wraps for the Ethereum/secp256k1 case.
+func VerifyPrivateKeyForCurve(curve Curve, identity Identity, privateKeyHex, expectedID string) bool {
+	privKey, err := privateKeyFromHex(curve, privateKeyHex)
 	if err != nil {
 		return false
 	}
+	return identity.Matches(&privKey.PublicKey, expectedID)
+}
 
-	addr := crypto.PubkeyToAddress(privKey.PublicKey)
-	return strings.EqualFold(addr.Hex(), expectedAddress)
+// VerifyPrivateKey verifies that a private key corresponds to an Ethereum
+// address.
+func VerifyPrivateKey(privateKeyHex, expectedAddress string) bool {
+	return VerifyPrivateKeyForCurve(Secp256k1, EthereumIdentity{}, privateKeyHex, expectedAddress)
 }
 
 // GetPublicKey derives the public key from a private key
@@ -244,19 +187,18 @@ func GetPublicKey(privateKeyHex string) (string, error) {
 	return "0x" + hex.EncodeToString(pubKeyBytes), nil
 }
 
-// GetAddressFromPrivateKey derives the address from a private key
-func GetAddressFromPrivateKey(privateKeyHex string) (string, error) {
-	privKeyHex := strings.TrimPrefix(privateKeyHex, "0x")
-	privKeyBytes, err := hex.DecodeString(privKeyHex)
-	if err != nil {
-		return "", err
-	}
-
-	privKey, err := crypto.ToECDSA(privKeyBytes)
+// GetIdentityFromPrivateKey derives privateKeyHex's identity on curve under
+// identity - e.g. an Ethereum address, or a JWK/SPKI fingerprint for
+// non-EVM datasets. GetAddressFromPrivateKey is the Ethereum/secp256k1 case.
+func GetIdentityFromPrivateKey(curve Curve, identity Identity, privateKeyHex string) (string, error) {
+	privKey, err := privateKeyFromHex(curve, privateKeyHex)
 	if err != nil {
 		return "", err
 	}
+	return identity.From(&privKey.PublicKey)
+}
 
-	addr := crypto.PubkeyToAddress(privKey.PublicKey)
-	return addr.Hex(), nil
+// GetAddressFromPrivateKey derives the Ethereum address from a private key
+func GetAddressFromPrivateKey(privateKeyHex string) (string, error) {
+	return GetIdentityFromPrivateKey(Secp256k1, EthereumIdentity{}, privateKeyHex)
 }
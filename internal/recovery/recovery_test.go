@@ -2,8 +2,11 @@ package recovery
 
 import (
 	"crypto/ecdsa"
+	crand "crypto/rand"
 	"encoding/hex"
+	"io"
 	"math/big"
+	mrand "math/rand"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/crypto"
@@ -32,40 +35,56 @@ func signWithNonce(privKey *ecdsa.PrivateKey, hash []byte, k *big.Int) (*big.Int
 	return r, s
 }
 
-// genPrivateKey generates a random ECDSA private key
-func genPrivateKey(t *rapid.T) *ecdsa.PrivateKey {
-	key, err := crypto.GenerateKey()
+// testRandSource picks, via a rapid draw, between crypto/rand.Reader (real
+// entropy) and a seeded math/rand-backed reader - so a property test's run
+// exercises both, and a failure under the seeded reader can be replayed
+// from its printed seed alone, without rapid's failure-cache state file.
+func testRandSource(t *rapid.T) io.Reader {
+	if rapid.Bool().Draw(t, "use_seeded_rand") {
+		seed := rapid.Int64().Draw(t, "seed")
+		return mrand.New(mrand.NewSource(seed))
+	}
+	return crand.Reader
+}
+
+// genPrivateKey generates a random ECDSA private key, reading entropy from
+// rand.
+func genPrivateKey(t *rapid.T, rand io.Reader) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(crypto.S256(), rand)
 	if err != nil {
 		t.Fatal(err)
 	}
 	return key
 }
 
-// genNonce generates a random valid nonce (1 < k < n)
-func genNonce(t *rapid.T) *big.Int {
-	// Generate random bytes and reduce mod n
-	bytes := rapid.SliceOfN(rapid.Byte(), 32, 32).Draw(t, "nonce_bytes")
-	k := new(big.Int).SetBytes(bytes)
-	k.Mod(k, secp256k1N)
-	// Ensure k > 0
-	if k.Sign() == 0 {
-		k.SetInt64(1)
+// genNonce generates a random valid nonce (1 <= k < n), reading entropy
+// from rand.
+func genNonce(t *rapid.T, rand io.Reader) *big.Int {
+	k, err := randFieldElement(secp256k1N, rand)
+	if err != nil {
+		t.Fatal(err)
 	}
 	return k
 }
 
-// genMessageHash generates a random 32-byte message hash
-func genMessageHash(t *rapid.T) []byte {
-	return rapid.SliceOfN(rapid.Byte(), 32, 32).Draw(t, "hash")
+// genMessageHash generates a random 32-byte message hash, reading entropy
+// from rand.
+func genMessageHash(t *rapid.T, rand io.Reader) []byte {
+	buf := make([]byte, 32)
+	if _, err := io.ReadFull(rand, buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf
 }
 
 // Property: Recovering a private key from two signatures with the same nonce always works
 func TestPropertySameKeyRecovery(t *testing.T) {
 	rapid.Check(t, func(t *rapid.T) {
-		privKey := genPrivateKey(t)
-		k := genNonce(t)
-		hash1 := genMessageHash(t)
-		hash2 := genMessageHash(t)
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+		k := genNonce(t, rnd)
+		hash1 := genMessageHash(t, rnd)
+		hash2 := genMessageHash(t, rnd)
 
 		// Ensure different messages
 		if string(hash1) == string(hash2) {
@@ -103,9 +122,10 @@ func TestPropertySameKeyRecovery(t *testing.T) {
 // Property: Recovering with a known nonce always works
 func TestPropertyKnownNonceRecovery(t *testing.T) {
 	rapid.Check(t, func(t *rapid.T) {
-		privKey := genPrivateKey(t)
-		k := genNonce(t)
-		hash := genMessageHash(t)
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+		k := genNonce(t, rnd)
+		hash := genMessageHash(t, rnd)
 
 		r, s := signWithNonce(privKey, hash, k)
 		z := new(big.Int).SetBytes(hash)
@@ -127,9 +147,10 @@ func TestPropertyKnownNonceRecovery(t *testing.T) {
 // Property: Deriving nonce from signature and private key is correct
 func TestPropertyDeriveNonce(t *testing.T) {
 	rapid.Check(t, func(t *rapid.T) {
-		privKey := genPrivateKey(t)
-		k := genNonce(t)
-		hash := genMessageHash(t)
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+		k := genNonce(t, rnd)
+		hash := genMessageHash(t, rnd)
 
 		r, s := signWithNonce(privKey, hash, k)
 		z := new(big.Int).SetBytes(hash)
@@ -147,20 +168,21 @@ func TestPropertyDeriveNonce(t *testing.T) {
 // Property: Cross-key recovery works when nonce is shared
 func TestPropertyCrossKeyRecovery(t *testing.T) {
 	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
 		// Two different keys, two different nonces
 		// A signs two messages with k1 (allows recovery of A's key)
 		// A and B each sign one message with k2 (cross-key, allows recovery of B using known k2)
-		privKeyA := genPrivateKey(t)
-		privKeyB := genPrivateKey(t)
-		k1 := genNonce(t)
-		k2 := genNonce(t)
+		privKeyA := genPrivateKey(t, rnd)
+		privKeyB := genPrivateKey(t, rnd)
+		k1 := genNonce(t, rnd)
+		k2 := genNonce(t, rnd)
 
 		// Messages for A with k1
-		hashA1 := genMessageHash(t)
-		hashA2 := genMessageHash(t)
+		hashA1 := genMessageHash(t, rnd)
+		hashA2 := genMessageHash(t, rnd)
 		// Messages for A and B with k2
-		hashA3 := genMessageHash(t)
-		hashB := genMessageHash(t)
+		hashA3 := genMessageHash(t, rnd)
+		hashB := genMessageHash(t, rnd)
 
 		// Ensure different messages for key A with k1
 		if string(hashA1) == string(hashA2) {
@@ -227,18 +249,19 @@ func TestPropertyCrossKeyRecovery(t *testing.T) {
 // Property: Linear system solves multi-key multi-nonce scenarios
 func TestPropertyLinearSystemMultiKey(t *testing.T) {
 	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
 		// 3 keys, 2 shared nonces
-		privKeyA := genPrivateKey(t)
-		privKeyB := genPrivateKey(t)
-		privKeyC := genPrivateKey(t)
+		privKeyA := genPrivateKey(t, rnd)
+		privKeyB := genPrivateKey(t, rnd)
+		privKeyC := genPrivateKey(t, rnd)
 
-		k1 := genNonce(t)
-		k2 := genNonce(t)
+		k1 := genNonce(t, rnd)
+		k2 := genNonce(t, rnd)
 
 		// Generate 6 different message hashes
 		msgs := make([][]byte, 6)
 		for i := range msgs {
-			msgs[i] = genMessageHash(t)
+			msgs[i] = genMessageHash(t, rnd)
 			// Ensure uniqueness
 			msgs[i][0] = byte(i)
 		}
@@ -315,22 +338,23 @@ func TestPropertyLinearSystemMultiKey(t *testing.T) {
 // Property: Cyclic cross-key recovery (A-B share k1, B-C share k2, C-A share k3)
 func TestPropertyCyclicCrossKeyRecovery(t *testing.T) {
 	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
 		// Three keys, three nonces in a cycle:
 		// k1: A and B sign
 		// k2: B and C sign
 		// k3: C and A sign
-		privKeyA := genPrivateKey(t)
-		privKeyB := genPrivateKey(t)
-		privKeyC := genPrivateKey(t)
+		privKeyA := genPrivateKey(t, rnd)
+		privKeyB := genPrivateKey(t, rnd)
+		privKeyC := genPrivateKey(t, rnd)
 
-		k1 := genNonce(t)
-		k2 := genNonce(t)
-		k3 := genNonce(t)
+		k1 := genNonce(t, rnd)
+		k2 := genNonce(t, rnd)
+		k3 := genNonce(t, rnd)
 
 		// Generate 6 different message hashes (one per signature)
 		msgs := make([][]byte, 6)
 		for i := range msgs {
-			msgs[i] = genMessageHash(t)
+			msgs[i] = genMessageHash(t, rnd)
 			msgs[i][0] = byte(i) // Ensure uniqueness
 		}
 
@@ -418,9 +442,10 @@ func TestPropertyCyclicCrossKeyRecovery(t *testing.T) {
 // Property: Recovery fails with different R values
 func TestPropertyRecoveryFailsDifferentR(t *testing.T) {
 	rapid.Check(t, func(t *rapid.T) {
-		privKey := genPrivateKey(t)
-		k1 := genNonce(t)
-		k2 := genNonce(t)
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+		k1 := genNonce(t, rnd)
+		k2 := genNonce(t, rnd)
 
 		// Ensure different nonces
 		if k1.Cmp(k2) == 0 {
@@ -428,8 +453,8 @@ func TestPropertyRecoveryFailsDifferentR(t *testing.T) {
 			k2.Mod(k2, secp256k1N)
 		}
 
-		hash1 := genMessageHash(t)
-		hash2 := genMessageHash(t)
+		hash1 := genMessageHash(t, rnd)
+		hash2 := genMessageHash(t, rnd)
 
 		r1, s1 := signWithNonce(privKey, hash1, k1)
 		r2, s2 := signWithNonce(privKey, hash2, k2)
@@ -452,9 +477,10 @@ func TestPropertyRecoveryFailsDifferentR(t *testing.T) {
 // Property: Recovery fails with identical signatures
 func TestPropertyRecoveryFailsIdentical(t *testing.T) {
 	rapid.Check(t, func(t *rapid.T) {
-		privKey := genPrivateKey(t)
-		k := genNonce(t)
-		hash := genMessageHash(t)
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+		k := genNonce(t, rnd)
+		hash := genMessageHash(t, rnd)
 
 		r, s := signWithNonce(privKey, hash, k)
 		z := new(big.Int).SetBytes(hash)
@@ -469,7 +495,8 @@ func TestPropertyRecoveryFailsIdentical(t *testing.T) {
 // Property: VerifyPrivateKey correctly validates key-address pairs
 func TestPropertyVerifyPrivateKey(t *testing.T) {
 	rapid.Check(t, func(t *rapid.T) {
-		privKey := genPrivateKey(t)
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
 		privKeyHex := "0x" + hex.EncodeToString(crypto.FromECDSA(privKey))
 		expectedAddr := crypto.PubkeyToAddress(privKey.PublicKey).Hex()
 
@@ -489,7 +516,8 @@ func TestPropertyVerifyPrivateKey(t *testing.T) {
 // Property: GetAddressFromPrivateKey is consistent
 func TestPropertyGetAddress(t *testing.T) {
 	rapid.Check(t, func(t *rapid.T) {
-		privKey := genPrivateKey(t)
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
 		privKeyHex := "0x" + hex.EncodeToString(crypto.FromECDSA(privKey))
 		expectedAddr := crypto.PubkeyToAddress(privKey.PublicKey).Hex()
 
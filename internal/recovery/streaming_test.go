@@ -0,0 +1,131 @@
+package recovery
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"pgregory.net/rapid"
+)
+
+func drainSolved(solver *StreamingSolver) map[string]*big.Int {
+	out := make(map[string]*big.Int)
+	for {
+		select {
+		case sol := <-solver.Solved():
+			out[sol.ID] = sol.Value
+		default:
+			return out
+		}
+	}
+}
+
+// TestStreamingSolverSameSignerFastPath checks that two signatures from the
+// same signer sharing an R value resolve the instant the second arrives,
+// without needing a third signature to make the system square.
+func TestStreamingSolverSameSignerFastPath(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
+		privKey := genPrivateKey(t, rnd)
+		k := genNonce(t, rnd)
+		hash1 := genMessageHash(t, rnd)
+		hash2 := genMessageHash(t, rnd)
+		if string(hash1) == string(hash2) {
+			hash2[0] ^= 0xff
+		}
+
+		r1, s1 := signWithNonce(privKey, hash1, k)
+		r2, s2 := signWithNonce(privKey, hash2, k)
+		if s1.Cmp(s2) == 0 {
+			t.Skip("identical s values")
+		}
+
+		addr := crypto.PubkeyToAddress(privKey.PublicKey).Hex()
+		solver := NewStreamingSolver(secp256k1N, time.Hour)
+		solver.Ingest(Signature{RValue: "r", Signer: addr, Z: new(big.Int).SetBytes(hash1), R: r1, S: s1})
+		solver.Ingest(Signature{RValue: "r", Signer: addr, Z: new(big.Int).SetBytes(hash2), R: r2, S: s2})
+
+		sols := drainSolved(solver)
+		d, ok := sols[addr]
+		if !ok {
+			t.Fatalf("expected key for %s to resolve immediately", addr)
+		}
+		if d.Cmp(privKey.D) != 0 {
+			t.Fatalf("recovered key mismatch")
+		}
+	})
+}
+
+// TestStreamingSolverCrossKeyRecovery checks that a cross-key collision (A
+// and B sharing one nonce) resolves once a third signature - A reusing that
+// same nonce with herself - pins down A's key directly, cascading through
+// the echelon system to recover B's key and the shared nonce.
+func TestStreamingSolverCrossKeyRecovery(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		rnd := testRandSource(t)
+		privKeyA := genPrivateKey(t, rnd)
+		privKeyB := genPrivateKey(t, rnd)
+		k := genNonce(t, rnd)
+
+		hashShared := genMessageHash(t, rnd)
+		hashA2 := genMessageHash(t, rnd)
+		if string(hashShared) == string(hashA2) {
+			hashA2[0] ^= 0xff
+		}
+
+		rShared, sA := signWithNonce(privKeyA, hashShared, k)
+		_, sB := signWithNonce(privKeyB, hashShared, k)
+		_, sA2 := signWithNonce(privKeyA, hashA2, k)
+		if sA.Cmp(sA2) == 0 {
+			t.Skip("degenerate draw")
+		}
+
+		addrA := crypto.PubkeyToAddress(privKeyA.PublicKey).Hex()
+		addrB := crypto.PubkeyToAddress(privKeyB.PublicKey).Hex()
+
+		solver := NewStreamingSolver(secp256k1N, time.Hour)
+		// A and B share a nonce on "rShared" - neither key is solvable yet.
+		solver.Ingest(Signature{RValue: "rShared", Signer: addrA, Z: new(big.Int).SetBytes(hashShared), R: rShared, S: sA})
+		solver.Ingest(Signature{RValue: "rShared", Signer: addrB, Z: new(big.Int).SetBytes(hashShared), R: rShared, S: sB})
+		if sols := drainSolved(solver); len(sols) != 0 {
+			t.Fatalf("expected nothing solved yet, got %v", sols)
+		}
+
+		// A reuses the same nonce again - the same-signer fast path solves
+		// A directly, which should cascade into solving B's key and the
+		// shared nonce.
+		solver.Ingest(Signature{RValue: "rShared", Signer: addrA, Z: new(big.Int).SetBytes(hashA2), R: rShared, S: sA2})
+
+		sols := drainSolved(solver)
+		dA, ok := sols[addrA]
+		if !ok || dA.Cmp(privKeyA.D) != 0 {
+			t.Fatalf("expected key A to resolve, got %v", sols)
+		}
+		dB, ok := sols[addrB]
+		if !ok || dB.Cmp(privKeyB.D) != 0 {
+			t.Fatalf("expected cross-key recovery of B to cascade, got %v", sols)
+		}
+	})
+}
+
+// TestStreamingSolverEvictsLoneSignatures checks the bounded-memory TTL
+// eviction: a bucket with a single signature is dropped once stale, but a
+// bucket that already collided (2+ signatures) is kept.
+func TestStreamingSolverEvictsLoneSignatures(t *testing.T) {
+	solver := NewStreamingSolver(secp256k1N, time.Minute)
+	solver.Ingest(Signature{RValue: "lonely", Signer: "0xA", Z: big.NewInt(1), R: big.NewInt(2), S: big.NewInt(3)})
+	solver.Ingest(Signature{RValue: "paired", Signer: "0xA", Z: big.NewInt(1), R: big.NewInt(2), S: big.NewInt(3)})
+	solver.Ingest(Signature{RValue: "paired", Signer: "0xB", Z: big.NewInt(1), R: big.NewInt(2), S: big.NewInt(4)})
+
+	solver.Evict(time.Now().Add(2 * time.Minute))
+
+	solver.mu.Lock()
+	defer solver.mu.Unlock()
+	if _, ok := solver.buckets["lonely"]; ok {
+		t.Error("expected the lone-signature bucket to be evicted")
+	}
+	if _, ok := solver.buckets["paired"]; !ok {
+		t.Error("expected the collided bucket to survive eviction")
+	}
+}
@@ -5,15 +5,42 @@ import (
 	"errors"
 	"math"
 	"math/rand"
+	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// Strategy selects the backoff schedule Do/DoWithResult follows between
+// retries.
+type Strategy int
+
+const (
+	// StrategyExponential doubles the delay each attempt with 0-25% jitter.
+	// This is the original behavior and remains the default.
+	StrategyExponential Strategy = iota
+	// StrategyFullJitter picks a delay uniformly between 0 and the
+	// exponential cap for the current attempt (AWS's "full jitter").
+	StrategyFullJitter
+	// StrategyDecorrelatedJitter picks a delay uniformly between BaseDelay
+	// and 3x the previous delay (AWS's "decorrelated jitter"), which
+	// spreads out retries from many concurrent callers hitting the same
+	// endpoint better than a schedule tied only to attempt number.
+	StrategyDecorrelatedJitter
+)
+
 // Config holds retry configuration
 type Config struct {
 	MaxAttempts int
 	BaseDelay   time.Duration
 	MaxDelay    time.Duration
+	Strategy    Strategy
+	// Budget, if set, is consulted before every retry (not the initial
+	// attempt); once exhausted, Do/DoWithResult return the last error
+	// immediately instead of sleeping and trying again.
+	Budget *RetryBudget
 }
 
 // DefaultConfig returns sensible defaults
@@ -22,7 +49,272 @@ func DefaultConfig() Config {
 		MaxAttempts: 3,
 		BaseDelay:   500 * time.Millisecond,
 		MaxDelay:    30 * time.Second,
+		Strategy:    StrategyExponential,
+	}
+}
+
+// nextDelay computes the sleep before the next attempt under cfg.Strategy,
+// given the zero-based attempt number just failed and the delay the
+// previous attempt slept for (0 if this is the first retry).
+func nextDelay(cfg Config, attempt int, prevDelay time.Duration) time.Duration {
+	switch cfg.Strategy {
+	case StrategyFullJitter:
+		ceiling := cfg.BaseDelay * time.Duration(math.Pow(2, float64(attempt)))
+		if ceiling > cfg.MaxDelay {
+			ceiling = cfg.MaxDelay
+		}
+		if ceiling <= 0 {
+			return 0
+		}
+		return time.Duration(rand.Int63n(int64(ceiling)))
+
+	case StrategyDecorrelatedJitter:
+		if prevDelay == 0 {
+			prevDelay = cfg.BaseDelay
+		}
+		upper := prevDelay * 3
+		if upper <= cfg.BaseDelay {
+			return cfg.BaseDelay
+		}
+		delay := cfg.BaseDelay + time.Duration(rand.Int63n(int64(upper-cfg.BaseDelay)))
+		if delay > cfg.MaxDelay {
+			delay = cfg.MaxDelay
+		}
+		return delay
+
+	default: // StrategyExponential
+		delay := cfg.BaseDelay * time.Duration(math.Pow(2, float64(attempt)))
+		if delay > cfg.MaxDelay {
+			delay = cfg.MaxDelay
+		}
+		jitter := time.Duration(rand.Int63n(int64(delay/4) + 1))
+		return delay + jitter
+	}
+}
+
+// retryDelay is the sleep before the next attempt: the larger of the
+// strategy's computed backoff and any server-specified RetryAfter carried
+// by err (via RetryableError), capped at cfg.MaxDelay either way.
+func retryDelay(cfg Config, attempt int, prevDelay time.Duration, err error) time.Duration {
+	delay := nextDelay(cfg, attempt, prevDelay)
+
+	var re RetryableError
+	if errors.As(err, &re) {
+		if wait := re.RetryAfter(); wait > delay {
+			delay = wait
+		}
+	}
+
+	if cfg.MaxDelay > 0 && delay > cfg.MaxDelay {
+		delay = cfg.MaxDelay
+	}
+	return delay
+}
+
+// RetryBudget is a token-bucket limiter shared across many Do/DoWithResult
+// calls (typically all retries for a single chain), so a sustained upstream
+// outage can't make every scanner goroutine retry forever in lockstep with
+// the circuit breaker.
+type RetryBudget struct {
+	mu           sync.Mutex
+	tokens       float64
+	burst        float64
+	refillPerSec float64
+	last         time.Time
+
+	exhausted atomic.Uint64
+}
+
+// NewRetryBudget creates a budget that starts full with burst tokens and
+// refills at refillPerSecond tokens/sec, capped at burst.
+func NewRetryBudget(burst int, refillPerSecond float64) *RetryBudget {
+	return &RetryBudget{
+		tokens:       float64(burst),
+		burst:        float64(burst),
+		refillPerSec: refillPerSecond,
+		last:         time.Now(),
+	}
+}
+
+// Allow consumes one token if available. It returns false - incrementing
+// BudgetExhausted() - if the budget is empty.
+func (b *RetryBudget) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.refillPerSec
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		b.exhausted.Add(1)
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// BudgetExhausted returns how many times Allow() has refused a retry
+// because the budget was empty.
+func (b *RetryBudget) BudgetExhausted() uint64 {
+	return b.exhausted.Load()
+}
+
+// RetryableError is implemented by errors that carry a server-specified
+// retry delay - e.g. one parsed from a Retry-After or X-RateLimit-Reset
+// header by WrapHTTPError - so Do/DoWithResult can honor it instead of
+// guessing purely from cfg.Strategy.
+type RetryableError interface {
+	error
+	// RetryAfter returns how long the caller should wait before retrying,
+	// or 0 if the error doesn't specify one.
+	RetryAfter() time.Duration
+}
+
+// httpRetryError wraps an HTTP-derived error with a server-specified retry
+// delay, implementing RetryableError.
+type httpRetryError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *httpRetryError) Error() string             { return e.err.Error() }
+func (e *httpRetryError) Unwrap() error             { return e.err }
+func (e *httpRetryError) RetryAfter() time.Duration { return e.retryAfter }
+
+// WrapHTTPError inspects resp for a Retry-After header (delta-seconds or
+// HTTP-date form, per RFC 9110) or an X-RateLimit-Reset header (the
+// epoch-seconds convention used by GitHub, Alchemy, Infura and others) and,
+// if either is present, wraps err in a RetryableError reporting the
+// server-specified delay. If resp is nil, err is nil, or neither header is
+// present, err is returned unchanged.
+func WrapHTTPError(resp *http.Response, err error) error {
+	if resp == nil || err == nil {
+		return err
+	}
+
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		return &httpRetryError{err: err, retryAfter: d}
+	}
+	if d, ok := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset")); ok {
+		return &httpRetryError{err: err, retryAfter: d}
+	}
+	return err
+}
+
+// parseRetryAfter parses a Retry-After header value.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// parseRateLimitReset parses an X-RateLimit-Reset header as an
+// epoch-seconds timestamp of when the limit resets.
+func parseRateLimitReset(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if d := time.Until(time.Unix(secs, 0)); d > 0 {
+		return d, true
+	}
+	return 0, false
+}
+
+// IsRateLimited reports whether err represents a rate-limit response: either
+// it implements RetryableError with a positive RetryAfter, or its message
+// matches the rate-limit-ish patterns IsRetryable also checks. Callers that
+// want to avoid tripping a circuit breaker on "slow down" responses (as
+// opposed to "endpoint dead" ones) use this instead of IsRetryable.
+func IsRateLimited(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var re RetryableError
+	if errors.As(err, &re) && re.RetryAfter() > 0 {
+		return true
+	}
+
+	errStr := strings.ToLower(err.Error())
+	for _, pattern := range []string{"too many requests", "rate limit", "429"} {
+		if strings.Contains(errStr, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimiter is a per-key token bucket gating the rate of calls, not just
+// their retries: rpcpool keeps one per endpoint so a provider's 429 backs
+// off only that endpoint (via Cooldown), without stalling calls routed to a
+// different endpoint or chain.
+type RateLimiter struct {
+	mu           sync.Mutex
+	tokens       float64
+	burst        float64
+	refillPerSec float64
+	last         time.Time
+}
+
+// NewRateLimiter creates a limiter that starts full with burst tokens and
+// refills at refillPerSecond tokens/sec, capped at burst.
+func NewRateLimiter(burst int, refillPerSecond float64) *RateLimiter {
+	return &RateLimiter{
+		tokens:       float64(burst),
+		burst:        float64(burst),
+		refillPerSec: refillPerSecond,
+		last:         time.Now(),
+	}
+}
+
+// Allow consumes one token if available, refilling first for the time
+// elapsed since the last call.
+func (r *RateLimiter) Allow() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Seconds() * r.refillPerSec
+	if r.tokens > r.burst {
+		r.tokens = r.burst
 	}
+	r.last = now
+
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// Cooldown drains the bucket and moves last d into the future, so Allow refuses
+// every call for roughly the next d regardless of refill rate - used after
+// a 429 to make an endpoint back off without tripping its circuit breaker.
+func (r *RateLimiter) Cooldown(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = 0
+	r.last = time.Now().Add(d)
 }
 
 // IsRetryable determines if an error should be retried
@@ -59,9 +351,12 @@ func IsRetryable(err error) bool {
 	return false
 }
 
-// Do executes fn with retries using exponential backoff
+// Do executes fn with retries, sleeping between attempts according to
+// cfg.Strategy. If cfg.Budget is set and exhausted, Do returns the last
+// error immediately without sleeping or attempting again.
 func Do(ctx context.Context, cfg Config, fn func() error) error {
 	var lastErr error
+	var prevDelay time.Duration
 
 	for attempt := 0; attempt < cfg.MaxAttempts; attempt++ {
 		err := fn()
@@ -81,14 +376,12 @@ func Do(ctx context.Context, cfg Config, fn func() error) error {
 			break
 		}
 
-		// Calculate backoff with jitter
-		delay := cfg.BaseDelay * time.Duration(math.Pow(2, float64(attempt)))
-		if delay > cfg.MaxDelay {
-			delay = cfg.MaxDelay
+		if cfg.Budget != nil && !cfg.Budget.Allow() {
+			return err
 		}
-		// Add jitter (0-25% of delay)
-		jitter := time.Duration(rand.Int63n(int64(delay / 4)))
-		delay += jitter
+
+		delay := retryDelay(cfg, attempt, prevDelay, err)
+		prevDelay = delay
 
 		select {
 		case <-ctx.Done():
@@ -100,10 +393,12 @@ func Do(ctx context.Context, cfg Config, fn func() error) error {
 	return lastErr
 }
 
-// DoWithResult executes fn with retries and returns the result
+// DoWithResult executes fn with retries and returns the result, following
+// the same backoff/budget rules as Do.
 func DoWithResult[T any](ctx context.Context, cfg Config, fn func() (T, error)) (T, error) {
 	var result T
 	var lastErr error
+	var prevDelay time.Duration
 
 	for attempt := 0; attempt < cfg.MaxAttempts; attempt++ {
 		var err error
@@ -122,12 +417,12 @@ func DoWithResult[T any](ctx context.Context, cfg Config, fn func() (T, error))
 			break
 		}
 
-		delay := cfg.BaseDelay * time.Duration(math.Pow(2, float64(attempt)))
-		if delay > cfg.MaxDelay {
-			delay = cfg.MaxDelay
+		if cfg.Budget != nil && !cfg.Budget.Allow() {
+			return result, err
 		}
-		jitter := time.Duration(rand.Int63n(int64(delay / 4)))
-		delay += jitter
+
+		delay := retryDelay(cfg, attempt, prevDelay, err)
+		prevDelay = delay
 
 		select {
 		case <-ctx.Done():
@@ -142,55 +437,273 @@ func DoWithResult[T any](ctx context.Context, cfg Config, fn func() (T, error))
 // ErrCircuitOpen is returned when the circuit breaker is open
 var ErrCircuitOpen = errors.New("circuit breaker is open")
 
-// CircuitBreaker implements a simple circuit breaker pattern
+// breakerState is the CircuitBreaker's internal state machine: closed allows
+// everything, open allows nothing until resetAfter elapses, and half-open
+// allows a bounded number of probes to decide which way to go next.
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case stateOpen:
+		return "open"
+	case stateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker implements a circuit breaker with a half-open probing
+// state: rather than snapping straight from open back to closed once
+// resetAfter elapses, it admits a limited number of probe requests and only
+// closes once enough of them succeed, so a still-failing endpoint reopens
+// immediately instead of needing a fresh run at threshold failures.
 type CircuitBreaker struct {
+	mu          sync.Mutex
+	state       breakerState
 	failures    int
 	threshold   int
 	resetAfter  time.Duration
 	lastFailure time.Time
-	open        bool
+
+	maxProbes         int // concurrent probes allowed while half-open
+	probesInFlight    int
+	requiredSuccesses int // consecutive probe successes needed to close
+	probeSuccesses    int
+
+	// onStateChange, if set via SetOnStateChange, is invoked after every
+	// state transition with the old and new state names, so a metrics or
+	// UI layer can surface which endpoint just degraded or recovered
+	// without polling State() on a timer.
+	onStateChange func(from, to string)
 }
 
-// NewCircuitBreaker creates a new circuit breaker
+// NewCircuitBreaker creates a circuit breaker that, once half-open, closes
+// after a single successful probe (maxProbes=1, requiredSuccesses=1).
 func NewCircuitBreaker(threshold int, resetAfter time.Duration) *CircuitBreaker {
+	return NewCircuitBreakerWithProbes(threshold, resetAfter, 1, 1)
+}
+
+// NewCircuitBreakerWithProbes creates a circuit breaker with an explicit
+// half-open probe budget: at most maxProbes concurrent probes are admitted,
+// and requiredSuccesses consecutive probe successes are needed before the
+// circuit closes.
+func NewCircuitBreakerWithProbes(threshold int, resetAfter time.Duration, maxProbes, requiredSuccesses int) *CircuitBreaker {
 	return &CircuitBreaker{
-		threshold:  threshold,
-		resetAfter: resetAfter,
+		threshold:         threshold,
+		resetAfter:        resetAfter,
+		maxProbes:         maxProbes,
+		requiredSuccesses: requiredSuccesses,
 	}
 }
 
-// Allow checks if a request should be allowed
-func (cb *CircuitBreaker) Allow() bool {
-	if !cb.open {
-		return true
+// SetOnStateChange registers fn to be called after every state transition
+// (closed/open/half-open), with the old and new state names. fn runs
+// outside the breaker's lock, so it may safely call back into Allow,
+// RecordSuccess, RecordFailure, or State.
+func (cb *CircuitBreaker) SetOnStateChange(fn func(from, to string)) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.onStateChange = fn
+}
+
+// notifyStateChange invokes onStateChange if the state actually moved, and
+// must be called without cb.mu held.
+func (cb *CircuitBreaker) notifyStateChange(from, to breakerState) {
+	if from == to {
+		return
+	}
+	cb.mu.Lock()
+	fn := cb.onStateChange
+	cb.mu.Unlock()
+	if fn != nil {
+		fn(from.String(), to.String())
 	}
+}
 
-	// Check if we should try to reset
-	if time.Since(cb.lastFailure) > cb.resetAfter {
-		cb.open = false
-		cb.failures = 0
-		return true
+// Allow checks if a request should be allowed. While open it returns false
+// until resetAfter has elapsed, at which point it transitions to half-open
+// and admits up to maxProbes concurrent probes; further calls are refused
+// until one of those probes reports success or failure.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+
+	before := cb.state
+	var allowed bool
+	switch cb.state {
+	case stateClosed:
+		allowed = true
+	case stateOpen:
+		if time.Since(cb.lastFailure) <= cb.resetAfter {
+			allowed = false
+		} else {
+			cb.state = stateHalfOpen
+			cb.probesInFlight = 0
+			cb.probeSuccesses = 0
+			if cb.probesInFlight < cb.maxProbes {
+				cb.probesInFlight++
+				allowed = true
+			}
+		}
+	case stateHalfOpen:
+		if cb.probesInFlight < cb.maxProbes {
+			cb.probesInFlight++
+			allowed = true
+		}
 	}
+	after := cb.state
+	cb.mu.Unlock()
 
-	return false
+	cb.notifyStateChange(before, after)
+	return allowed
 }
 
-// RecordSuccess records a successful request
+// RecordSuccess records a successful request. While half-open it counts
+// toward requiredSuccesses before the circuit closes; a success recorded
+// while closed or open clears the failure count and closes the circuit.
 func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mu.Lock()
+
+	before := cb.state
+	if cb.state == stateHalfOpen {
+		if cb.probesInFlight > 0 {
+			cb.probesInFlight--
+		}
+		cb.probeSuccesses++
+		if cb.probeSuccesses >= cb.requiredSuccesses {
+			cb.state = stateClosed
+		}
+	} else {
+		cb.state = stateClosed
+	}
 	cb.failures = 0
-	cb.open = false
+	after := cb.state
+	cb.mu.Unlock()
+
+	cb.notifyStateChange(before, after)
 }
 
-// RecordFailure records a failed request
+// RecordFailure records a failed request. A failure while half-open trips
+// the circuit straight back to open and restarts the reset timer; a
+// failure while closed counts toward threshold.
 func (cb *CircuitBreaker) RecordFailure() {
-	cb.failures++
+	cb.mu.Lock()
+
+	before := cb.state
 	cb.lastFailure = time.Now()
+
+	if cb.state == stateHalfOpen {
+		if cb.probesInFlight > 0 {
+			cb.probesInFlight--
+		}
+		cb.state = stateOpen
+		after := cb.state
+		cb.mu.Unlock()
+		cb.notifyStateChange(before, after)
+		return
+	}
+
+	cb.failures++
 	if cb.failures >= cb.threshold {
-		cb.open = true
+		cb.state = stateOpen
 	}
+	after := cb.state
+	cb.mu.Unlock()
+
+	cb.notifyStateChange(before, after)
 }
 
-// IsOpen returns whether the circuit is open
+// IsOpen returns whether the circuit is fully open (not half-open).
 func (cb *CircuitBreaker) IsOpen() bool {
-	return cb.open
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state == stateOpen
+}
+
+// State returns the circuit's current state as "closed", "open", or
+// "half-open", for stats/health endpoints to surface.
+func (cb *CircuitBreaker) State() string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state.String()
+}
+
+// Breakers is a registry of CircuitBreakers keyed by endpoint URL, so
+// callers juggling several RPC providers (rpcpool's Pool, or anything else
+// that dials more than one endpoint) can keep one breaker per endpoint
+// without each maintaining its own map and constructor defaults.
+type Breakers struct {
+	threshold  int
+	resetAfter time.Duration
+
+	onStateChange func(endpoint, from, to string)
+
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+// NewBreakers creates a registry whose breakers all share the given
+// threshold/resetAfter, created lazily on first Get for a given endpoint.
+func NewBreakers(threshold int, resetAfter time.Duration) *Breakers {
+	return &Breakers{
+		threshold:  threshold,
+		resetAfter: resetAfter,
+		breakers:   make(map[string]*CircuitBreaker),
+	}
+}
+
+// SetOnStateChange registers fn to be called whenever any breaker in the
+// registry transitions state, with the endpoint key alongside the old and
+// new state names - the hook the API/UI layer uses to surface which
+// endpoint just degraded or recovered. It applies to breakers created
+// after this call as well as ones already present.
+func (b *Breakers) SetOnStateChange(fn func(endpoint, from, to string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.onStateChange = fn
+	for endpoint, cb := range b.breakers {
+		endpoint := endpoint
+		cb.SetOnStateChange(func(from, to string) { fn(endpoint, from, to) })
+	}
+}
+
+// Get returns the CircuitBreaker for endpoint, creating it (with the
+// registry's threshold/resetAfter and onStateChange hook, if set) on first
+// use.
+func (b *Breakers) Get(endpoint string) *CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if cb, ok := b.breakers[endpoint]; ok {
+		return cb
+	}
+
+	cb := NewCircuitBreaker(b.threshold, b.resetAfter)
+	if b.onStateChange != nil {
+		endpoint := endpoint
+		fn := b.onStateChange
+		cb.SetOnStateChange(func(from, to string) { fn(endpoint, from, to) })
+	}
+	b.breakers[endpoint] = cb
+	return cb
+}
+
+// States returns a point-in-time snapshot of every registered endpoint's
+// breaker state, keyed by endpoint URL.
+func (b *Breakers) States() map[string]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	states := make(map[string]string, len(b.breakers))
+	for endpoint, cb := range b.breakers {
+		states[endpoint] = cb.State()
+	}
+	return states
 }
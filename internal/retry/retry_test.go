@@ -3,6 +3,9 @@ package retry
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -190,6 +193,273 @@ func TestDoWithResult_RetryThenSuccess(t *testing.T) {
 	}
 }
 
+func TestNextDelay_FullJitterStaysWithinCeiling(t *testing.T) {
+	cfg := Config{BaseDelay: 10 * time.Millisecond, MaxDelay: 1 * time.Second, Strategy: StrategyFullJitter}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		delay := nextDelay(cfg, attempt, 0)
+		if delay < 0 || delay > cfg.MaxDelay {
+			t.Errorf("attempt %d: delay %v out of [0, %v]", attempt, delay, cfg.MaxDelay)
+		}
+	}
+}
+
+func TestNextDelay_DecorrelatedJitterGrowsFromPrevious(t *testing.T) {
+	cfg := Config{BaseDelay: 10 * time.Millisecond, MaxDelay: 1 * time.Second, Strategy: StrategyDecorrelatedJitter}
+
+	prev := time.Duration(0)
+	for i := 0; i < 5; i++ {
+		delay := nextDelay(cfg, i, prev)
+		if delay < cfg.BaseDelay || delay > cfg.MaxDelay {
+			t.Errorf("iteration %d: delay %v out of [%v, %v]", i, delay, cfg.BaseDelay, cfg.MaxDelay)
+		}
+		prev = delay
+	}
+}
+
+func TestNextDelay_DecorrelatedJitterCappedAtMaxDelay(t *testing.T) {
+	cfg := Config{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond, Strategy: StrategyDecorrelatedJitter}
+
+	prev := 40 * time.Millisecond
+	for i := 0; i < 20; i++ {
+		delay := nextDelay(cfg, 0, prev)
+		if delay > cfg.MaxDelay {
+			t.Fatalf("delay %v exceeded MaxDelay %v", delay, cfg.MaxDelay)
+		}
+		prev = delay
+	}
+}
+
+func TestDo_DecorrelatedJitterStrategy(t *testing.T) {
+	cfg := Config{
+		MaxAttempts: 3,
+		BaseDelay:   5 * time.Millisecond,
+		MaxDelay:    50 * time.Millisecond,
+		Strategy:    StrategyDecorrelatedJitter,
+	}
+
+	attempts := 0
+	err := Do(context.Background(), cfg, func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("timeout")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("expected no error after retries, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryBudget_AllowsUpToBurst(t *testing.T) {
+	budget := NewRetryBudget(2, 0)
+
+	if !budget.Allow() {
+		t.Error("expected first token to be allowed")
+	}
+	if !budget.Allow() {
+		t.Error("expected second token to be allowed")
+	}
+	if budget.Allow() {
+		t.Error("expected third token to be refused with no refill")
+	}
+	if budget.BudgetExhausted() != 1 {
+		t.Errorf("expected BudgetExhausted() to be 1, got %d", budget.BudgetExhausted())
+	}
+}
+
+func TestRetryBudget_RefillsOverTime(t *testing.T) {
+	budget := NewRetryBudget(1, 1000) // 1000 tokens/sec - refills fast
+
+	if !budget.Allow() {
+		t.Fatal("expected first token to be allowed")
+	}
+	if budget.Allow() {
+		t.Fatal("expected second token to be refused before refill")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if !budget.Allow() {
+		t.Error("expected a token to be available after refill")
+	}
+}
+
+func TestDo_StopsImmediatelyWhenBudgetExhausted(t *testing.T) {
+	budget := NewRetryBudget(0, 0)
+	cfg := Config{
+		MaxAttempts: 5,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    100 * time.Millisecond,
+		Budget:      budget,
+	}
+
+	attempts := 0
+	start := time.Now()
+	err := Do(context.Background(), cfg, func() error {
+		attempts++
+		return errors.New("timeout")
+	})
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Error("expected the underlying error to be returned")
+	}
+	if attempts != 1 {
+		t.Errorf("expected exactly 1 attempt before budget exhaustion stopped retries, got %d", attempts)
+	}
+	if elapsed > 5*time.Millisecond {
+		t.Errorf("expected Do to return immediately without sleeping, took %v", elapsed)
+	}
+	if budget.BudgetExhausted() != 1 {
+		t.Errorf("expected BudgetExhausted() to be 1, got %d", budget.BudgetExhausted())
+	}
+}
+
+func TestWrapHTTPError_ParsesRetryAfterSeconds(t *testing.T) {
+	resp := httptest.NewRecorder().Result()
+	resp.Header.Set("Retry-After", "30")
+
+	wrapped := WrapHTTPError(resp, errors.New("429 Too Many Requests"))
+
+	var re RetryableError
+	if !errors.As(wrapped, &re) {
+		t.Fatal("expected wrapped error to implement RetryableError")
+	}
+	if re.RetryAfter() != 30*time.Second {
+		t.Errorf("expected 30s, got %v", re.RetryAfter())
+	}
+}
+
+func TestWrapHTTPError_ParsesRetryAfterHTTPDate(t *testing.T) {
+	resp := httptest.NewRecorder().Result()
+	resp.Header.Set("Retry-After", time.Now().Add(10*time.Second).UTC().Format(http.TimeFormat))
+
+	wrapped := WrapHTTPError(resp, errors.New("429 Too Many Requests"))
+
+	var re RetryableError
+	if !errors.As(wrapped, &re) {
+		t.Fatal("expected wrapped error to implement RetryableError")
+	}
+	if re.RetryAfter() < 8*time.Second || re.RetryAfter() > 10*time.Second {
+		t.Errorf("expected ~10s, got %v", re.RetryAfter())
+	}
+}
+
+func TestWrapHTTPError_ParsesXRateLimitReset(t *testing.T) {
+	resp := httptest.NewRecorder().Result()
+	reset := time.Now().Add(20 * time.Second).Unix()
+	resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+
+	wrapped := WrapHTTPError(resp, errors.New("rate limit exceeded"))
+
+	var re RetryableError
+	if !errors.As(wrapped, &re) {
+		t.Fatal("expected wrapped error to implement RetryableError")
+	}
+	if re.RetryAfter() < 18*time.Second || re.RetryAfter() > 20*time.Second {
+		t.Errorf("expected ~20s, got %v", re.RetryAfter())
+	}
+}
+
+func TestWrapHTTPError_NoHeadersReturnsErrUnchanged(t *testing.T) {
+	resp := httptest.NewRecorder().Result()
+	original := errors.New("internal server error")
+
+	if got := WrapHTTPError(resp, original); got != original {
+		t.Errorf("expected unchanged error, got %v", got)
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	resp := httptest.NewRecorder().Result()
+	resp.Header.Set("Retry-After", "5")
+
+	if !IsRateLimited(WrapHTTPError(resp, errors.New("429"))) {
+		t.Error("expected wrapped 429 to be rate-limited")
+	}
+	if !IsRateLimited(errors.New("rate limit exceeded")) {
+		t.Error("expected plain rate-limit message to be rate-limited")
+	}
+	if IsRateLimited(errors.New("connection refused")) {
+		t.Error("expected non-rate-limit error to not be rate-limited")
+	}
+	if IsRateLimited(nil) {
+		t.Error("expected nil to not be rate-limited")
+	}
+}
+
+func TestDo_HonorsRetryAfterOverComputedBackoff(t *testing.T) {
+	resp := httptest.NewRecorder().Result()
+	resp.Header.Set("Retry-After", "1")
+	rateLimitErr := WrapHTTPError(resp, errors.New("429 Too Many Requests"))
+
+	cfg := Config{
+		MaxAttempts: 2,
+		BaseDelay:   1 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	}
+
+	start := time.Now()
+	attempts := 0
+	_ = Do(context.Background(), cfg, func() error {
+		attempts++
+		return rateLimitErr
+	})
+	elapsed := time.Since(start)
+
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+	if elapsed < 1*time.Second {
+		t.Errorf("expected Do to sleep for the server-specified Retry-After (~1s), only slept %v", elapsed)
+	}
+}
+
+func TestRetryDelay_CapsAtMaxDelay(t *testing.T) {
+	resp := httptest.NewRecorder().Result()
+	resp.Header.Set("Retry-After", "3600")
+	rateLimitErr := WrapHTTPError(resp, errors.New("429"))
+
+	cfg := Config{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+	if delay := retryDelay(cfg, 0, 0, rateLimitErr); delay != cfg.MaxDelay {
+		t.Errorf("expected delay capped at MaxDelay (%v), got %v", cfg.MaxDelay, delay)
+	}
+}
+
+func TestRateLimiter_AllowsUpToBurst(t *testing.T) {
+	limiter := NewRateLimiter(2, 0)
+
+	if !limiter.Allow() {
+		t.Error("expected first token to be allowed")
+	}
+	if !limiter.Allow() {
+		t.Error("expected second token to be allowed")
+	}
+	if limiter.Allow() {
+		t.Error("expected third token to be refused with no refill")
+	}
+}
+
+func TestRateLimiter_CooldownBlocksUntilElapsed(t *testing.T) {
+	limiter := NewRateLimiter(5, 1000) // refills fast once cooldown elapses
+
+	limiter.Cooldown(20 * time.Millisecond)
+	if limiter.Allow() {
+		t.Error("expected Allow to be refused immediately after Cooldown")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !limiter.Allow() {
+		t.Error("expected Allow to succeed once the cooldown elapsed")
+	}
+}
+
 func TestCircuitBreaker_AllowsWhenClosed(t *testing.T) {
 	cb := NewCircuitBreaker(3, time.Minute)
 
@@ -250,10 +520,149 @@ func TestCircuitBreaker_ResetsAfterTimeout(t *testing.T) {
 	time.Sleep(60 * time.Millisecond)
 
 	if !cb.Allow() {
-		t.Error("circuit should allow after reset timeout")
+		t.Error("circuit should admit a probe after reset timeout")
 	}
 
 	if cb.IsOpen() {
-		t.Error("circuit should be closed after allowing a request")
+		t.Error("circuit should not report open while probing")
+	}
+	if cb.State() != "half-open" {
+		t.Errorf("expected half-open state after reset timeout, got %s", cb.State())
+	}
+
+	cb.RecordSuccess()
+	if cb.State() != "closed" {
+		t.Errorf("expected closed state after a successful probe, got %s", cb.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAdmitsLimitedProbes(t *testing.T) {
+	cb := NewCircuitBreakerWithProbes(1, 20*time.Millisecond, 2, 1)
+
+	cb.RecordFailure()
+	time.Sleep(30 * time.Millisecond)
+
+	if !cb.Allow() {
+		t.Error("expected first probe to be admitted")
+	}
+	if !cb.Allow() {
+		t.Error("expected second probe to be admitted (maxProbes=2)")
+	}
+	if cb.Allow() {
+		t.Error("expected third concurrent probe to be refused")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenFailureReopens(t *testing.T) {
+	cb := NewCircuitBreaker(1, 20*time.Millisecond)
+
+	cb.RecordFailure()
+	time.Sleep(30 * time.Millisecond)
+
+	if !cb.Allow() {
+		t.Error("expected probe to be admitted")
+	}
+	cb.RecordFailure()
+
+	if cb.State() != "open" {
+		t.Errorf("expected a failed probe to reopen the circuit, got %s", cb.State())
+	}
+	if cb.Allow() {
+		t.Error("circuit should not allow immediately after reopening")
+	}
+}
+
+func TestCircuitBreaker_OnStateChangeFiresOnTransitions(t *testing.T) {
+	cb := NewCircuitBreaker(1, 20*time.Millisecond)
+
+	var transitions []string
+	cb.SetOnStateChange(func(from, to string) {
+		transitions = append(transitions, from+"->"+to)
+	})
+
+	cb.RecordFailure()
+	time.Sleep(30 * time.Millisecond)
+	cb.Allow()
+	cb.RecordSuccess()
+
+	want := []string{"closed->open", "open->half-open", "half-open->closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("expected transitions %v, got %v", want, transitions)
+	}
+	for i, w := range want {
+		if transitions[i] != w {
+			t.Errorf("transition %d: expected %q, got %q", i, w, transitions[i])
+		}
+	}
+}
+
+func TestBreakers_GetIsPerEndpoint(t *testing.T) {
+	reg := NewBreakers(1, time.Minute)
+
+	a := reg.Get("https://a.example")
+	b := reg.Get("https://b.example")
+
+	a.RecordFailure()
+
+	if !a.IsOpen() {
+		t.Error("expected endpoint a's breaker to be open")
+	}
+	if b.IsOpen() {
+		t.Error("expected endpoint b's breaker to be unaffected by endpoint a's failure")
+	}
+	if reg.Get("https://a.example") != a {
+		t.Error("expected Get to return the same breaker for a repeat endpoint")
+	}
+}
+
+func TestBreakers_SetOnStateChangeTagsEndpoint(t *testing.T) {
+	reg := NewBreakers(1, time.Minute)
+
+	type event struct{ endpoint, from, to string }
+	var events []event
+	reg.SetOnStateChange(func(endpoint, from, to string) {
+		events = append(events, event{endpoint, from, to})
+	})
+
+	reg.Get("https://a.example").RecordFailure()
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d: %+v", len(events), events)
+	}
+	if events[0].endpoint != "https://a.example" || events[0].from != "closed" || events[0].to != "open" {
+		t.Errorf("unexpected event: %+v", events[0])
+	}
+}
+
+func TestBreakers_StatesSnapshotsEveryEndpoint(t *testing.T) {
+	reg := NewBreakers(1, time.Minute)
+	reg.Get("https://a.example").RecordFailure()
+	reg.Get("https://b.example")
+
+	states := reg.States()
+	if states["https://a.example"] != "open" {
+		t.Errorf("expected a to be open, got %q", states["https://a.example"])
+	}
+	if states["https://b.example"] != "closed" {
+		t.Errorf("expected b to be closed, got %q", states["https://b.example"])
+	}
+}
+
+func TestCircuitBreaker_HalfOpenRequiresConsecutiveSuccesses(t *testing.T) {
+	cb := NewCircuitBreakerWithProbes(1, 20*time.Millisecond, 1, 2)
+
+	cb.RecordFailure()
+	time.Sleep(30 * time.Millisecond)
+
+	cb.Allow()
+	cb.RecordSuccess()
+	if cb.State() != "half-open" {
+		t.Errorf("expected to stay half-open after 1 of 2 required successes, got %s", cb.State())
+	}
+
+	cb.Allow()
+	cb.RecordSuccess()
+	if cb.State() != "closed" {
+		t.Errorf("expected to close after 2 consecutive successful probes, got %s", cb.State())
 	}
 }
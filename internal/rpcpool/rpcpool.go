@@ -0,0 +1,283 @@
+// Package rpcpool dials multiple RPC endpoints for a single chain and routes
+// calls to a healthy one, so a flaky or rate-limited provider doesn't stall
+// scanning for chains configured with a fallback endpoint.
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"ecdsa-scanner/internal/config"
+	"ecdsa-scanner/internal/retry"
+)
+
+// ErrAllEndpointsDown is returned when every endpoint's circuit breaker is
+// open and none are eligible to take a call.
+var ErrAllEndpointsDown = errors.New("rpcpool: all endpoints down")
+
+// circuitThreshold and circuitResetAfter size each endpoint's breaker:
+// three consecutive failures trips it, and it's given half a minute before
+// the pool tries that endpoint again.
+const (
+	circuitThreshold  = 3
+	circuitResetAfter = 30 * time.Second
+)
+
+// rateLimitBurst and rateLimitRefillPerSec size each endpoint's token
+// bucket; defaultRateLimitCooldown is how long an endpoint backs off after
+// a 429 with no server-specified Retry-After.
+const (
+	rateLimitBurst           = 20
+	rateLimitRefillPerSec    = 5
+	defaultRateLimitCooldown = 5 * time.Second
+)
+
+// SelectionPolicy controls how pick() chooses among healthy endpoints.
+type SelectionPolicy int
+
+const (
+	// RoundRobin cycles through healthy endpoints in order.
+	RoundRobin SelectionPolicy = iota
+	// LowestLatency always picks the healthy endpoint with the lowest
+	// observed average latency, falling back to round-robin among ties
+	// (including endpoints with no observations yet).
+	LowestLatency
+)
+
+// EndpointStats is the observability snapshot for a single endpoint,
+// returned by Pool.Stats().
+type EndpointStats struct {
+	Name         string `json:"name"`
+	Successes    int64  `json:"successes"`
+	Failures     int64  `json:"failures"`
+	Open         bool   `json:"open"`
+	AvgLatencyMs int64  `json:"avg_latency_ms"`
+}
+
+// conn is a single dialed endpoint with its own health tracking, so one
+// provider's rate limiting or downtime doesn't count against the others.
+type conn struct {
+	name    string
+	client  *rpc.Client
+	breaker *retry.CircuitBreaker
+	limiter *retry.RateLimiter
+
+	mu         sync.Mutex
+	successes  int64
+	failures   int64
+	avgLatency time.Duration
+}
+
+func (c *conn) record(start time.Time, err error) {
+	elapsed := time.Since(start)
+
+	c.mu.Lock()
+	if err == nil {
+		c.successes++
+		if c.avgLatency == 0 {
+			c.avgLatency = elapsed
+		} else {
+			// Exponential moving average (0.3 weight for new observation),
+			// matching ChainScanner's block-time estimator.
+			c.avgLatency = (c.avgLatency*7 + elapsed*3) / 10
+		}
+	} else {
+		c.failures++
+	}
+	c.mu.Unlock()
+
+	if err == nil {
+		c.breaker.RecordSuccess()
+		return
+	}
+
+	// A 429 means "slow down", not "endpoint dead": back this endpoint's
+	// rate limiter off instead of counting it toward the circuit breaker,
+	// so unrelated endpoints/chains aren't stalled by one provider's limit.
+	if retry.IsRateLimited(err) {
+		cooldown := defaultRateLimitCooldown
+		var re retry.RetryableError
+		if errors.As(err, &re) {
+			if wait := re.RetryAfter(); wait > 0 {
+				cooldown = wait
+			}
+		}
+		if c.limiter != nil {
+			c.limiter.Cooldown(cooldown)
+		}
+		return
+	}
+
+	c.breaker.RecordFailure()
+}
+
+func (c *conn) stats() EndpointStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return EndpointStats{
+		Name:         c.name,
+		Successes:    c.successes,
+		Failures:     c.failures,
+		Open:         c.breaker.IsOpen(),
+		AvgLatencyMs: c.avgLatency.Milliseconds(),
+	}
+}
+
+// Pool routes CallContext to one of a chain's configured RPC endpoints,
+// skipping any whose circuit breaker is currently open.
+type Pool struct {
+	chainName string
+	policy    SelectionPolicy
+	breakers  *retry.Breakers // one CircuitBreaker per endpoint URL
+
+	mu    sync.Mutex
+	conns []*conn
+	next  int // round-robin cursor
+}
+
+// New dials every endpoint and returns a Pool over the ones that connect.
+// An endpoint failing to dial is logged-worthy but not fatal here; New only
+// errors if none of them come up, mirroring how Scanner.New already treats
+// a single dial failure as a warning rather than a hard error.
+func New(chainName string, endpoints []config.NamedEndpoint, policy SelectionPolicy) (*Pool, error) {
+	p := &Pool{
+		chainName: chainName,
+		policy:    policy,
+		breakers:  retry.NewBreakers(circuitThreshold, circuitResetAfter),
+	}
+
+	var dialErrs []error
+	for _, ep := range endpoints {
+		client, err := rpc.Dial(ep.URL)
+		if err != nil {
+			dialErrs = append(dialErrs, fmt.Errorf("%s: %w", ep.Name, err))
+			continue
+		}
+		p.conns = append(p.conns, &conn{
+			name:    ep.Name,
+			client:  client,
+			breaker: p.breakers.Get(ep.URL),
+			limiter: retry.NewRateLimiter(rateLimitBurst, rateLimitRefillPerSec),
+		})
+	}
+
+	if len(p.conns) == 0 {
+		return nil, fmt.Errorf("rpcpool: no endpoints for %s connected: %w", chainName, errors.Join(dialErrs...))
+	}
+	return p, nil
+}
+
+// SetOnStateChange registers fn to be called whenever an endpoint's circuit
+// breaker changes state (e.g. closed -> open when it starts failing, or
+// half-open -> closed when a probe succeeds), so the API/UI layer can
+// surface which endpoint is currently degraded instead of only seeing the
+// pool-wide ErrAllEndpointsDown once every endpoint is down.
+func (p *Pool) SetOnStateChange(fn func(endpoint, from, to string)) {
+	p.breakers.SetOnStateChange(fn)
+}
+
+// pick returns the next endpoint to try under the pool's SelectionPolicy,
+// considering only endpoints whose breaker currently allows a call and
+// whose rate limiter has a token available (a nil limiter, as in tests that
+// build a conn directly, is treated as unlimited).
+func (p *Pool) pick() (*conn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var healthy []*conn
+	for _, c := range p.conns {
+		if c.breaker.Allow() && (c.limiter == nil || c.limiter.Allow()) {
+			healthy = append(healthy, c)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil, ErrAllEndpointsDown
+	}
+
+	if p.policy == LowestLatency {
+		var best []*conn
+		var bestLatency time.Duration
+		for _, c := range healthy {
+			c.mu.Lock()
+			cLatency := c.avgLatency
+			c.mu.Unlock()
+			switch {
+			case len(best) == 0 || (cLatency != 0 && (bestLatency == 0 || cLatency < bestLatency)):
+				best, bestLatency = []*conn{c}, cLatency
+			case cLatency == bestLatency:
+				best = append(best, c)
+			}
+		}
+		c := best[p.next%len(best)]
+		p.next++
+		return c, nil
+	}
+
+	c := healthy[p.next%len(healthy)]
+	p.next++
+	return c, nil
+}
+
+// CallContext dispatches method to a healthy endpoint, wrapping any error
+// with the endpoint's name so logs show which provider failed.
+func (p *Pool) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	c, err := p.pick()
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	callErr := c.client.CallContext(ctx, result, method, args...)
+	c.record(start, callErr)
+
+	if callErr != nil {
+		return fmt.Errorf("RPCClient returned error (%s): %w", c.name, callErr)
+	}
+	return nil
+}
+
+// BatchCallContext dispatches a batch of calls to a single healthy endpoint
+// in one round trip, same endpoint-selection and health-tracking behavior as
+// CallContext. Per-call failures are reported in each rpc.BatchElem's Error
+// field, not the returned error; the returned error is for transport-level
+// failures that mean the whole batch didn't get a response.
+func (p *Pool) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	c, err := p.pick()
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	callErr := c.client.BatchCallContext(ctx, batch)
+	c.record(start, callErr)
+
+	if callErr != nil {
+		return fmt.Errorf("RPCClient returned error (%s): %w", c.name, callErr)
+	}
+	return nil
+}
+
+// Close closes every underlying RPC client.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range p.conns {
+		c.client.Close()
+	}
+}
+
+// Stats returns a point-in-time snapshot of every endpoint's health.
+func (p *Pool) Stats() []EndpointStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make([]EndpointStats, 0, len(p.conns))
+	for _, c := range p.conns {
+		stats = append(stats, c.stats())
+	}
+	return stats
+}
@@ -0,0 +1,113 @@
+package rpcpool
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"ecdsa-scanner/internal/retry"
+)
+
+func newTestConn(name string) *conn {
+	return &conn{name: name, breaker: retry.NewCircuitBreaker(circuitThreshold, circuitResetAfter)}
+}
+
+func newTestConnWithLimiter(name string) *conn {
+	c := newTestConn(name)
+	c.limiter = retry.NewRateLimiter(rateLimitBurst, rateLimitRefillPerSec)
+	return c
+}
+
+func TestPickRoundRobinSkipsOpenBreaker(t *testing.T) {
+	a := newTestConn("a")
+	b := newTestConn("b")
+	for i := 0; i < circuitThreshold; i++ {
+		a.breaker.RecordFailure()
+	}
+
+	p := &Pool{chainName: "test", policy: RoundRobin, conns: []*conn{a, b}}
+
+	for i := 0; i < 3; i++ {
+		picked, err := p.pick()
+		if err != nil {
+			t.Fatalf("pick failed: %v", err)
+		}
+		if picked.name != "b" {
+			t.Errorf("expected only healthy endpoint b to be picked, got %s", picked.name)
+		}
+	}
+}
+
+func TestPickReturnsErrAllEndpointsDown(t *testing.T) {
+	a := newTestConn("a")
+	for i := 0; i < circuitThreshold; i++ {
+		a.breaker.RecordFailure()
+	}
+
+	p := &Pool{chainName: "test", policy: RoundRobin, conns: []*conn{a}}
+
+	if _, err := p.pick(); err != ErrAllEndpointsDown {
+		t.Errorf("expected ErrAllEndpointsDown, got %v", err)
+	}
+}
+
+func TestPickLowestLatencyPrefersFasterEndpoint(t *testing.T) {
+	fast := newTestConn("fast")
+	fast.avgLatency = 10 * time.Millisecond
+	slow := newTestConn("slow")
+	slow.avgLatency = 200 * time.Millisecond
+
+	p := &Pool{chainName: "test", policy: LowestLatency, conns: []*conn{slow, fast}}
+
+	picked, err := p.pick()
+	if err != nil {
+		t.Fatalf("pick failed: %v", err)
+	}
+	if picked.name != "fast" {
+		t.Errorf("expected the lower-latency endpoint to be picked, got %s", picked.name)
+	}
+}
+
+func TestRecordUpdatesStats(t *testing.T) {
+	c := newTestConn("a")
+	c.record(time.Now().Add(-50*time.Millisecond), nil)
+	c.record(time.Now().Add(-10*time.Millisecond), errAny)
+
+	stats := c.stats()
+	if stats.Successes != 1 || stats.Failures != 1 {
+		t.Errorf("expected 1 success and 1 failure, got %+v", stats)
+	}
+}
+
+func TestRecordRateLimitedErrorDoesNotTripBreaker(t *testing.T) {
+	c := newTestConnWithLimiter("a")
+
+	for i := 0; i < circuitThreshold; i++ {
+		c.record(time.Now(), errors.New("429 Too Many Requests"))
+	}
+
+	if c.breaker.IsOpen() {
+		t.Error("expected rate-limit errors to leave the circuit breaker closed")
+	}
+	if c.limiter.Allow() {
+		t.Error("expected the rate limiter to be in cooldown after a 429")
+	}
+}
+
+func TestRecordNonRateLimitErrorTripsBreaker(t *testing.T) {
+	c := newTestConnWithLimiter("a")
+
+	for i := 0; i < circuitThreshold; i++ {
+		c.record(time.Now(), errAny)
+	}
+
+	if !c.breaker.IsOpen() {
+		t.Error("expected a non-rate-limit error to trip the circuit breaker")
+	}
+}
+
+var errAny = &testError{"boom"}
+
+type testError struct{ msg string }
+
+func (e *testError) Error() string { return e.msg }
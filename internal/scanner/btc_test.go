@@ -0,0 +1,202 @@
+package scanner
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"ecdsa-scanner/internal/recovery"
+)
+
+// encodeDERSignature is the inverse of recovery.ParseDERSignature, used
+// here to build scriptSig/witness fixtures from an (r, s) pair.
+func encodeDERSignature(r, s *big.Int) []byte {
+	encodeInt := func(v *big.Int) []byte {
+		b := v.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0x00}, b...)
+		}
+		return append([]byte{0x02, byte(len(b))}, b...)
+	}
+	body := append(encodeInt(r), encodeInt(s)...)
+	return append([]byte{0x30, byte(len(body))}, body...)
+}
+
+func pushData(b []byte) []byte {
+	if len(b) > 0x4b {
+		panic("pushData: fixture push too long for a direct-push opcode")
+	}
+	return append([]byte{byte(len(b))}, b...)
+}
+
+// fakeP2PKHTx builds a single-input, single-output verbosity-2 block
+// transaction spending a P2PKH output, with its scriptSig left empty so a
+// test can fill in a signature over btcLegacySigHash's preimage.
+func fakeP2PKHTx(prevTxid string, hash160 []byte) btcRPCTx {
+	scriptPubKey := append(append([]byte{0x76, 0xa9, 0x14}, hash160...), 0x88, 0xac)
+	return btcRPCTx{
+		Txid:     "11" + prevTxid[2:], // distinct from the input's prevout txid
+		Version:  1,
+		Locktime: 0,
+		Vin: []btcRPCVin{{
+			Txid:     prevTxid,
+			Vout:     0,
+			Sequence: 0xffffffff,
+			Prevout: &btcRPCPrevout{
+				Value:        0.5,
+				ScriptPubKey: btcRPCScriptPubKey{Hex: hex.EncodeToString(scriptPubKey)},
+			},
+		}},
+		Vout: []btcRPCVout{{
+			Value:        0.49,
+			ScriptPubKey: btcRPCScriptPubKey{Hex: hex.EncodeToString(scriptPubKey)},
+		}},
+	}
+}
+
+func TestExtractBTCSignature_P2PKH(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	pubKey := crypto.CompressPubkey(&privKey.PublicKey)
+	hash160 := recovery.Hash160(pubKey)
+
+	tx := fakeP2PKHTx("00000000000000000000000000000000000000000000000000000000000001", hash160)
+	scriptPubKey, _ := hex.DecodeString(tx.Vin[0].Prevout.ScriptPubKey.Hex)
+
+	sigHash, err := btcLegacySigHash(tx, 0, scriptPubKey)
+	if err != nil {
+		t.Fatalf("btcLegacySigHash: %v", err)
+	}
+
+	r, s := signWithNonce(privKey, sigHash, big.NewInt(12345))
+	der := append(encodeDERSignature(r, s), sighashAll)
+	scriptSig := append(pushData(der), pushData(pubKey)...)
+	tx.Vin[0].ScriptSig = &struct {
+		Hex string `json:"hex"`
+	}{Hex: hex.EncodeToString(scriptSig)}
+
+	sig, address, err := extractBTCSignature(tx, 0)
+	if err != nil {
+		t.Fatalf("extractBTCSignature: %v", err)
+	}
+	if sig == nil {
+		t.Fatal("expected a signature, got nil")
+	}
+	if sig.R.Cmp(r) != 0 || sig.S.Cmp(s) != 0 {
+		t.Errorf("r/s mismatch: got (%s, %s), want (%s, %s)", sig.R, sig.S, r, s)
+	}
+	if want := recovery.EncodeP2PKHAddress(hash160); address != want {
+		t.Errorf("address = %s, want %s", address, want)
+	}
+}
+
+func TestExtractBTCSignature_P2WPKH(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	pubKey := crypto.CompressPubkey(&privKey.PublicKey)
+	hash160 := recovery.Hash160(pubKey)
+	scriptPubKey := append([]byte{0x00, 0x14}, hash160...)
+
+	tx := btcRPCTx{
+		Txid:     "2200000000000000000000000000000000000000000000000000000000000002",
+		Version:  2,
+		Locktime: 0,
+		Vin: []btcRPCVin{{
+			Txid:     "0000000000000000000000000000000000000000000000000000000000000003",
+			Vout:     1,
+			Sequence: 0xffffffff,
+			Prevout: &btcRPCPrevout{
+				Value:        1.0,
+				ScriptPubKey: btcRPCScriptPubKey{Hex: hex.EncodeToString(scriptPubKey)},
+			},
+		}},
+		Vout: []btcRPCVout{{
+			Value:        0.99,
+			ScriptPubKey: btcRPCScriptPubKey{Hex: hex.EncodeToString(scriptPubKey)},
+		}},
+	}
+
+	sigHash, err := btcBIP143SigHash(tx, 0, tx.Vin[0].Prevout.Value)
+	if err != nil {
+		t.Fatalf("btcBIP143SigHash: %v", err)
+	}
+
+	r, s := signWithNonce(privKey, sigHash, big.NewInt(98765))
+	der := append(encodeDERSignature(r, s), sighashAll)
+	tx.Vin[0].TxinWitness = []string{hex.EncodeToString(der), hex.EncodeToString(pubKey)}
+
+	sig, address, err := extractBTCSignature(tx, 0)
+	if err != nil {
+		t.Fatalf("extractBTCSignature: %v", err)
+	}
+	if sig == nil {
+		t.Fatal("expected a signature, got nil")
+	}
+	if sig.R.Cmp(r) != 0 || sig.S.Cmp(s) != 0 {
+		t.Errorf("r/s mismatch: got (%s, %s), want (%s, %s)", sig.R, sig.S, r, s)
+	}
+	wantAddr, err := recovery.EncodeSegwitAddress("bc", 0, hash160)
+	if err != nil {
+		t.Fatalf("EncodeSegwitAddress: %v", err)
+	}
+	if address != wantAddr {
+		t.Errorf("address = %s, want %s", address, wantAddr)
+	}
+}
+
+func TestExtractBTCSignature_UnknownScriptIsSkippedNotErrored(t *testing.T) {
+	// A P2SH scriptPubKey: not P2PKH or P2WPKH shaped, so extraction should
+	// report "nothing found" rather than an error.
+	scriptPubKey := append(append([]byte{0xa9, 0x14}, make([]byte, 20)...), 0x87)
+	tx := btcRPCTx{
+		Txid: "00000000000000000000000000000000000000000000000000000000000004",
+		Vin: []btcRPCVin{{
+			Txid: "0000000000000000000000000000000000000000000000000000000000000005",
+			Prevout: &btcRPCPrevout{
+				ScriptPubKey: btcRPCScriptPubKey{Hex: hex.EncodeToString(scriptPubKey)},
+			},
+		}},
+	}
+
+	sig, _, err := extractBTCSignature(tx, 0)
+	if err != nil {
+		t.Fatalf("expected no error for an unrecognized script, got %v", err)
+	}
+	if sig != nil {
+		t.Error("expected a nil signature for an unrecognized script")
+	}
+}
+
+func TestRecoverPrivateKeyBTC_NonceReuse(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	pubKey := crypto.CompressPubkey(&privKey.PublicKey)
+	hash160 := recovery.Hash160(pubKey)
+	expectedHash160 := hex.EncodeToString(hash160)
+
+	k := big.NewInt(424242)
+	hash1 := crypto.Keccak256([]byte("btc tx one"))
+	hash2 := crypto.Keccak256([]byte("btc tx two"))
+	r1, s1 := signWithNonce(privKey, hash1, k)
+	r2, s2 := signWithNonce(privKey, hash2, k)
+
+	recovered, err := recovery.RecoverPrivateKeyBTC(
+		recovery.BTCSignature{TxID: "tx1", Vin: 0, SigningHash: hash1, R: r1, S: s1},
+		recovery.BTCSignature{TxID: "tx2", Vin: 0, SigningHash: hash2, R: r2, S: s2},
+		expectedHash160,
+	)
+	if err != nil {
+		t.Fatalf("RecoverPrivateKeyBTC: %v", err)
+	}
+	if !recovery.VerifyBTCPrivateKey(recovered.PrivateKey, expectedHash160) {
+		t.Errorf("recovered private key %s does not match expected hash160 %s", recovered.PrivateKey, expectedHash160)
+	}
+}
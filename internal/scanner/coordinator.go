@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ecdsa-scanner/internal/db"
+)
+
+// ErrCoordinationDisabled is returned by noopCoordinator, the default
+// Coordinator every Scanner starts with: it means no shard coordination is
+// configured, and scanLoop's catch-up branch should fall back to scanning
+// the whole confirmed range itself.
+var ErrCoordinationDisabled = errors.New("scanner: coordination disabled")
+
+// Coordinator partitions catch-up work across scanner replicas scanning the
+// same chain, so a full-history rescan that would otherwise take weeks on
+// one node can run on several at once. It only decides which blocks a
+// replica fetches next; every replica still writes through the same
+// db.Store, so collision detection stays globally consistent regardless of
+// how many replicas are running.
+type Coordinator interface {
+	// ClaimShard leases up to size blocks of chainID's unclaimed (or
+	// expired-lease) history to this replica.
+	ClaimShard(ctx context.Context, chainID int, size uint64) (db.Shard, error)
+
+	// CompleteShard marks s as fully scanned, so it's never reclaimed by
+	// another replica even after the lease that produced it expires.
+	CompleteShard(ctx context.Context, s db.Shard) error
+}
+
+// noopCoordinator is the Coordinator every Scanner starts with. ClaimShard
+// always fails with ErrCoordinationDisabled, which scanLoop treats as "no
+// coordination configured" rather than an error worth logging - the
+// behavior every single-node deployment wants, unchanged from before
+// Coordinator existed.
+type noopCoordinator struct{}
+
+func (noopCoordinator) ClaimShard(ctx context.Context, chainID int, size uint64) (db.Shard, error) {
+	return db.Shard{}, ErrCoordinationDisabled
+}
+
+func (noopCoordinator) CompleteShard(ctx context.Context, s db.Shard) error {
+	return nil
+}
+
+// dbCoordinator adapts a db.Store's ClaimShard/CompleteShard to Coordinator,
+// fixing the owner identity and lease duration every claim made through it
+// uses.
+type dbCoordinator struct {
+	store    db.Store
+	owner    string
+	leaseFor time.Duration
+}
+
+// NewDBCoordinator returns a Coordinator backed by store, identifying every
+// shard this replica leases as owner and holding each lease for leaseFor
+// before it's eligible for another replica to reclaim. owner should be
+// stable for this replica's lifetime but unique across replicas (e.g. a
+// hostname or pod name), so CompleteShard and lease-expiry reclaiming both
+// work correctly.
+func NewDBCoordinator(store db.Store, owner string, leaseFor time.Duration) Coordinator {
+	return &dbCoordinator{store: store, owner: owner, leaseFor: leaseFor}
+}
+
+func (c *dbCoordinator) ClaimShard(ctx context.Context, chainID int, size uint64) (db.Shard, error) {
+	return c.store.ClaimShard(ctx, chainID, size, c.owner, c.leaseFor)
+}
+
+func (c *dbCoordinator) CompleteShard(ctx context.Context, s db.Shard) error {
+	return c.store.CompleteShard(ctx, s)
+}
+
+// SetCoordinator installs c as the shard coordinator scanLoop's catch-up
+// branch uses for every chain. The default, set by New, is a no-op that
+// always returns ErrCoordinationDisabled, so single-node deployments catch
+// up their full confirmed range exactly as before Coordinator existed.
+func (s *Scanner) SetCoordinator(c Coordinator) {
+	s.coordinator = c
+}
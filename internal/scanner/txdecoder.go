@@ -0,0 +1,196 @@
+package scanner
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// RawTx holds the fields needed to reconstruct a transaction's signing hash
+// without requiring a full *types.Transaction. This lets TxDecoder work
+// equally well against a freshly-fetched RPC transaction and against rows
+// sourced from something like a statediff/IPLD index.
+type RawTx struct {
+	Type                byte
+	ChainID             *big.Int
+	Nonce               uint64
+	GasPrice            *big.Int // legacy / access-list
+	GasTipCap           *big.Int // dynamic-fee / blob (maxPriorityFeePerGas)
+	GasFeeCap           *big.Int // dynamic-fee / blob (maxFeePerGas)
+	Gas                 uint64
+	To                  *common.Address
+	Value               *big.Int
+	Data                []byte
+	AccessList          types.AccessList
+	MaxFeePerBlobGas    *big.Int
+	BlobVersionedHashes []common.Hash
+}
+
+// rlp encodings of each tx type's fields, in signing order and excluding the
+// (v, r, s) signature fields. These mirror go-ethereum's own tx_*.go structs.
+type legacySigningData struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	Gas      uint64
+	To       *common.Address `rlp:"nil"`
+	Value    *big.Int
+	Data     []byte
+}
+
+type legacyEIP155SigningData struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	Gas      uint64
+	To       *common.Address `rlp:"nil"`
+	Value    *big.Int
+	Data     []byte
+	ChainID  *big.Int
+	Zero1    uint
+	Zero2    uint
+}
+
+type accessListSigningData struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasPrice   *big.Int
+	Gas        uint64
+	To         *common.Address `rlp:"nil"`
+	Value      *big.Int
+	Data       []byte
+	AccessList types.AccessList
+}
+
+type dynamicFeeSigningData struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         *common.Address `rlp:"nil"`
+	Value      *big.Int
+	Data       []byte
+	AccessList types.AccessList
+}
+
+type blobSigningData struct {
+	ChainID             *big.Int
+	Nonce               uint64
+	GasTipCap           *big.Int
+	GasFeeCap           *big.Int
+	Gas                 uint64
+	To                  common.Address
+	Value               *big.Int
+	Data                []byte
+	AccessList          types.AccessList
+	MaxFeePerBlobGas    *big.Int
+	BlobVersionedHashes []common.Hash
+}
+
+// TxDecoder computes the ECDSA signing hash ("z") for any transaction type
+// currently observed on Ethereum mainnet and its L2s: legacy (EIP-155),
+// access-list (0x01), dynamic-fee (0x02), and blob (0x03) transactions.
+type TxDecoder struct{}
+
+// NewTxDecoder creates a new TxDecoder.
+func NewTxDecoder() *TxDecoder {
+	return &TxDecoder{}
+}
+
+// SigningHash returns the hash that was signed to produce tx's (r, s).
+func (d *TxDecoder) SigningHash(tx RawTx) (*big.Int, error) {
+	switch tx.Type {
+	case types.LegacyTxType:
+		return d.legacySigningHash(tx)
+	case types.AccessListTxType:
+		return d.rlpTypedHash(tx.Type, accessListSigningData{
+			ChainID:    tx.ChainID,
+			Nonce:      tx.Nonce,
+			GasPrice:   tx.GasPrice,
+			Gas:        tx.Gas,
+			To:         tx.To,
+			Value:      tx.Value,
+			Data:       tx.Data,
+			AccessList: tx.AccessList,
+		})
+	case types.DynamicFeeTxType:
+		return d.rlpTypedHash(tx.Type, dynamicFeeSigningData{
+			ChainID:    tx.ChainID,
+			Nonce:      tx.Nonce,
+			GasTipCap:  tx.GasTipCap,
+			GasFeeCap:  tx.GasFeeCap,
+			Gas:        tx.Gas,
+			To:         tx.To,
+			Value:      tx.Value,
+			Data:       tx.Data,
+			AccessList: tx.AccessList,
+		})
+	case types.BlobTxType:
+		if tx.To == nil {
+			return nil, fmt.Errorf("blob transaction must not be a contract creation")
+		}
+		return d.rlpTypedHash(tx.Type, blobSigningData{
+			ChainID:             tx.ChainID,
+			Nonce:               tx.Nonce,
+			GasTipCap:           tx.GasTipCap,
+			GasFeeCap:           tx.GasFeeCap,
+			Gas:                 tx.Gas,
+			To:                  *tx.To,
+			Value:               tx.Value,
+			Data:                tx.Data,
+			AccessList:          tx.AccessList,
+			MaxFeePerBlobGas:    tx.MaxFeePerBlobGas,
+			BlobVersionedHashes: tx.BlobVersionedHashes,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported transaction type 0x%x", tx.Type)
+	}
+}
+
+// legacySigningHash implements EIP-155 (falling back to pre-EIP-155 framing
+// when no chain ID is present): keccak256(rlp([nonce, gasPrice, gas, to,
+// value, data, chainId, 0, 0])).
+func (d *TxDecoder) legacySigningHash(tx RawTx) (*big.Int, error) {
+	var (
+		enc []byte
+		err error
+	)
+	if tx.ChainID != nil && tx.ChainID.Sign() > 0 {
+		enc, err = rlp.EncodeToBytes(legacyEIP155SigningData{
+			Nonce:    tx.Nonce,
+			GasPrice: tx.GasPrice,
+			Gas:      tx.Gas,
+			To:       tx.To,
+			Value:    tx.Value,
+			Data:     tx.Data,
+			ChainID:  tx.ChainID,
+		})
+	} else {
+		enc, err = rlp.EncodeToBytes(legacySigningData{
+			Nonce:    tx.Nonce,
+			GasPrice: tx.GasPrice,
+			Gas:      tx.Gas,
+			To:       tx.To,
+			Value:    tx.Value,
+			Data:     tx.Data,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rlp encode legacy tx: %w", err)
+	}
+	return new(big.Int).SetBytes(crypto.Keccak256(enc)), nil
+}
+
+// rlpTypedHash implements EIP-2718: keccak256(type || rlp(fields)), i.e. the
+// type-prefixed payload with the (v, r, s) signature fields stripped.
+func (d *TxDecoder) rlpTypedHash(txType byte, fields interface{}) (*big.Int, error) {
+	enc, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		return nil, fmt.Errorf("rlp encode typed tx: %w", err)
+	}
+	payload := append([]byte{txType}, enc...)
+	return new(big.Int).SetBytes(crypto.Keccak256(payload)), nil
+}
@@ -0,0 +1,160 @@
+package scanner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+// txSigningHash builds a go-ethereum transaction of the given type and
+// returns the hash the latest signer would have signed for it, so we can
+// check TxDecoder against the reference implementation.
+func txSigningHash(t *testing.T, chainID *big.Int, inner types.TxData) common.Hash {
+	t.Helper()
+	tx := types.NewTx(inner)
+	signer := types.LatestSignerForChainID(chainID)
+	return signer.Hash(tx)
+}
+
+func TestTxDecoderSigningHash_Legacy(t *testing.T) {
+	chainID := big.NewInt(1)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	want := txSigningHash(t, chainID, &types.LegacyTx{
+		Nonce:    7,
+		GasPrice: big.NewInt(20_000_000_000),
+		Gas:      21000,
+		To:       &to,
+		Value:    big.NewInt(1_000_000),
+		Data:     []byte{0xde, 0xad, 0xbe, 0xef},
+	})
+
+	got, err := NewTxDecoder().SigningHash(RawTx{
+		Type:     types.LegacyTxType,
+		ChainID:  chainID,
+		Nonce:    7,
+		GasPrice: big.NewInt(20_000_000_000),
+		Gas:      21000,
+		To:       &to,
+		Value:    big.NewInt(1_000_000),
+		Data:     []byte{0xde, 0xad, 0xbe, 0xef},
+	})
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	if got.Cmp(new(big.Int).SetBytes(want.Bytes())) != 0 {
+		t.Errorf("legacy signing hash mismatch: got %x want %x", got, want)
+	}
+}
+
+func TestTxDecoderSigningHash_AccessList(t *testing.T) {
+	chainID := big.NewInt(1)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	accessList := types.AccessList{
+		{Address: to, StorageKeys: []common.Hash{{0x01}}},
+	}
+	want := txSigningHash(t, chainID, &types.AccessListTx{
+		ChainID:    chainID,
+		Nonce:      3,
+		GasPrice:   big.NewInt(30_000_000_000),
+		Gas:        50000,
+		To:         &to,
+		Value:      big.NewInt(0),
+		AccessList: accessList,
+	})
+
+	got, err := NewTxDecoder().SigningHash(RawTx{
+		Type:       types.AccessListTxType,
+		ChainID:    chainID,
+		Nonce:      3,
+		GasPrice:   big.NewInt(30_000_000_000),
+		Gas:        50000,
+		To:         &to,
+		Value:      big.NewInt(0),
+		AccessList: accessList,
+	})
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	if got.Cmp(new(big.Int).SetBytes(want.Bytes())) != 0 {
+		t.Errorf("access-list signing hash mismatch: got %x want %x", got, want)
+	}
+}
+
+func TestTxDecoderSigningHash_DynamicFee(t *testing.T) {
+	chainID := big.NewInt(8453)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000003")
+	want := txSigningHash(t, chainID, &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     12,
+		GasTipCap: big.NewInt(1_500_000_000),
+		GasFeeCap: big.NewInt(40_000_000_000),
+		Gas:       100000,
+		To:        &to,
+		Value:     big.NewInt(42),
+		Data:      []byte{0x01, 0x02},
+	})
+
+	got, err := NewTxDecoder().SigningHash(RawTx{
+		Type:      types.DynamicFeeTxType,
+		ChainID:   chainID,
+		Nonce:     12,
+		GasTipCap: big.NewInt(1_500_000_000),
+		GasFeeCap: big.NewInt(40_000_000_000),
+		Gas:       100000,
+		To:        &to,
+		Value:     big.NewInt(42),
+		Data:      []byte{0x01, 0x02},
+	})
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	if got.Cmp(new(big.Int).SetBytes(want.Bytes())) != 0 {
+		t.Errorf("dynamic-fee signing hash mismatch: got %x want %x", got, want)
+	}
+}
+
+func TestTxDecoderSigningHash_Blob(t *testing.T) {
+	chainID := big.NewInt(1)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000004")
+	blobHashes := []common.Hash{{0xaa}, {0xbb}}
+	want := txSigningHash(t, chainID, &types.BlobTx{
+		ChainID:    uint256.MustFromBig(chainID),
+		Nonce:      1,
+		GasTipCap:  uint256.MustFromBig(big.NewInt(1_000_000_000)),
+		GasFeeCap:  uint256.MustFromBig(big.NewInt(60_000_000_000)),
+		Gas:        21000,
+		To:         to,
+		Value:      uint256.MustFromBig(big.NewInt(0)),
+		BlobFeeCap: uint256.MustFromBig(big.NewInt(1)),
+		BlobHashes: blobHashes,
+	})
+
+	got, err := NewTxDecoder().SigningHash(RawTx{
+		Type:                types.BlobTxType,
+		ChainID:             chainID,
+		Nonce:               1,
+		GasTipCap:           big.NewInt(1_000_000_000),
+		GasFeeCap:           big.NewInt(60_000_000_000),
+		Gas:                 21000,
+		To:                  &to,
+		Value:               big.NewInt(0),
+		MaxFeePerBlobGas:    big.NewInt(1),
+		BlobVersionedHashes: blobHashes,
+	})
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	if got.Cmp(new(big.Int).SetBytes(want.Bytes())) != 0 {
+		t.Errorf("blob signing hash mismatch: got %x want %x", got, want)
+	}
+}
+
+func TestTxDecoderSigningHash_UnsupportedType(t *testing.T) {
+	_, err := NewTxDecoder().SigningHash(RawTx{Type: 0x7f})
+	if err == nil {
+		t.Fatal("expected an error for an unsupported transaction type")
+	}
+}
@@ -0,0 +1,133 @@
+// Package storage selects and wires up a concrete db.Store backend from
+// config, without db itself (or its subpackages) needing to import the
+// others - db/postgres imports db for the Store interface and shared types,
+// so the factory has to live in a sibling package rather than in db.
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"ecdsa-scanner/internal/db"
+	"ecdsa-scanner/internal/db/clickhouse"
+	"ecdsa-scanner/internal/db/postgres"
+	"ecdsa-scanner/internal/logger"
+)
+
+// New builds the db.Store named by driver:
+//
+//   - "postgres" (default): db/postgres only, the authoritative transactional store.
+//   - "clickhouse": db/clickhouse only, for analytics-focused deployments with
+//     no Postgres at all.
+//   - "hybrid": both, with Postgres authoritative for writes and ClickHouse
+//     serving the analytics-heavy reads (GetStats, GetAllCollisions). See Hybrid.
+func New(driver, postgresURL, clickhouseURL string, systemAddresses map[string]bool, log *logger.Logger) (db.Store, error) {
+	switch driver {
+	case "", "postgres":
+		return postgres.New(postgresURL, systemAddresses)
+	case "clickhouse":
+		return clickhouse.New(clickhouseURL, systemAddresses)
+	case "hybrid":
+		primary, err := postgres.New(postgresURL, systemAddresses)
+		if err != nil {
+			return nil, err
+		}
+		analytics, err := clickhouse.New(clickhouseURL, systemAddresses)
+		if err != nil {
+			primary.Close()
+			return nil, err
+		}
+		return NewHybrid(primary, analytics, log), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q (want postgres, clickhouse, or hybrid)", driver)
+	}
+}
+
+// Hybrid pairs a write-authoritative primary store (db/postgres in
+// practice) with an analytics store (db/clickhouse) that serves the reads
+// ClickHouse is better suited for. Every other method delegates to primary
+// unchanged; primary's correctness is never allowed to depend on analytics
+// being up.
+type Hybrid struct {
+	db.Store
+	primary   db.Store
+	analytics db.Store
+	logger    *logger.Logger
+}
+
+// NewHybrid wraps primary and analytics into a single db.Store. primary
+// handles every method except the analytics-heavy reads and the dual-write
+// on the r-value ingestion path; see CheckAndInsertRValue and
+// BatchCheckAndInsertRValues.
+func NewHybrid(primary, analytics db.Store, log *logger.Logger) *Hybrid {
+	return &Hybrid{Store: primary, primary: primary, analytics: analytics, logger: log}
+}
+
+// Close closes both backends, returning primary's error (the one that
+// matters for durability) if both fail.
+func (h *Hybrid) Close() error {
+	analyticsErr := h.analytics.Close()
+	primaryErr := h.primary.Close()
+	if primaryErr != nil {
+		return primaryErr
+	}
+	return analyticsErr
+}
+
+// CheckAndInsertRValue writes to primary first since it's authoritative for
+// collision detection; the analytics store gets a best-effort copy so
+// GetStats/GetAllCollisions stay current. An analytics write failure is
+// logged, not returned - callers on the ingestion hot path shouldn't fail a
+// scan because the dashboard's backend hiccuped.
+func (h *Hybrid) CheckAndInsertRValue(ctx context.Context, rValue, txHash string, chainID int, blockNumber uint64) (*db.TxRef, bool, error) {
+	ref, collision, err := h.primary.CheckAndInsertRValue(ctx, rValue, txHash, chainID, blockNumber)
+	if err != nil {
+		return nil, false, err
+	}
+	if !collision {
+		if _, _, analyticsErr := h.analytics.CheckAndInsertRValue(ctx, rValue, txHash, chainID, blockNumber); analyticsErr != nil {
+			h.logWarn("hybrid: analytics CheckAndInsertRValue failed for r=%s: %v", rValue, analyticsErr)
+		}
+	}
+	return ref, collision, nil
+}
+
+// BatchCheckAndInsertRValues dual-writes the same way as
+// CheckAndInsertRValue: primary's result is authoritative and returned to
+// the caller, analytics gets the same batch best-effort.
+func (h *Hybrid) BatchCheckAndInsertRValues(ctx context.Context, txs []db.TxInput) ([]db.CollisionResult, error) {
+	collisions, err := h.primary.BatchCheckAndInsertRValues(ctx, txs)
+	if err != nil {
+		return nil, err
+	}
+	if _, analyticsErr := h.analytics.BatchCheckAndInsertRValues(ctx, txs); analyticsErr != nil {
+		h.logWarn("hybrid: analytics BatchCheckAndInsertRValues failed for %d rows: %v", len(txs), analyticsErr)
+	}
+	return collisions, nil
+}
+
+// GetStats reads from analytics, which is what it's there for.
+func (h *Hybrid) GetStats(ctx context.Context) (*db.Stats, error) {
+	return h.analytics.GetStats(ctx)
+}
+
+// GetAllCollisions reads from analytics; see db/clickhouse.GetAllCollisions
+// for why that's a better answer than primary's windowed view.
+func (h *Hybrid) GetAllCollisions(ctx context.Context) ([]db.Collision, error) {
+	return h.analytics.GetAllCollisions(ctx)
+}
+
+// RefreshStats is a no-op for Hybrid: it delegates GetStats to ClickHouse,
+// which (per db/clickhouse.RefreshStats) computes counts live rather than
+// off a cached materialized view, so there is nothing to refresh.
+func (h *Hybrid) RefreshStats(ctx context.Context) error {
+	return nil
+}
+
+func (h *Hybrid) logWarn(format string, args ...interface{}) {
+	if h.logger != nil {
+		h.logger.Warn(format, args...)
+	}
+}
+
+var _ db.Store = (*Hybrid)(nil)